@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+const (
+	// DeleteLeftoverResourcesAnnotation allows aws-vpc-operator to delete
+	// leftover network interfaces and security groups that block deletion of
+	// subnets or the VPC, when they have been tagged as owned by the cluster.
+	// Resources that are not owned by the cluster are never deleted.
+	DeleteLeftoverResourcesAnnotation = "aws-vpc-operator.giantswarm.io/delete-leftover-resources"
+
+	// RequeueScheduleAnnotation overrides the operator requeue schedule for
+	// the AWSCluster. The value is in the format of the --requeue-schedule
+	// flag, e.g. "0s:1m,5m:5m,15m:15m:Warning".
+	RequeueScheduleAnnotation = "aws-vpc-operator.giantswarm.io/requeue-schedule"
+
+	// DriftCheckIntervalAnnotation overrides the operator drift check
+	// interval for the AWSCluster, as a duration, e.g. "10m". Drift checks
+	// are disabled with "0".
+	DriftCheckIntervalAnnotation = "aws-vpc-operator.giantswarm.io/drift-check-interval"
+
+	// DriftAutoRepairAnnotation set to "true" or "false" overrides whether
+	// drift found by the drift checks of the AWSCluster is repaired.
+	DriftAutoRepairAnnotation = "aws-vpc-operator.giantswarm.io/drift-auto-repair"
+
+	// NetworkManagementAnnotation set to NetworkManagementUnmanaged marks the
+	// VPC and subnets in AWSCluster spec as brought in by the user, e.g. a
+	// shared VPC. aws-vpc-operator then never creates, changes or deletes the
+	// VPC, subnets and route tables, it only reconciles VPC endpoints.
+	NetworkManagementAnnotation = "aws-vpc-operator.giantswarm.io/network-management"
+	NetworkManagementUnmanaged  = "unmanaged"
+
+	// LastAppliedTagKeysAnnotation records the keys of AWSCluster
+	// AdditionalTags that have been applied to all network resources, as a
+	// JSON list. When a key is removed from AdditionalTags, the tag is
+	// removed from the resources, while tags added by users are preserved.
+	LastAppliedTagKeysAnnotation = "aws-vpc-operator.giantswarm.io/last-applied-tag-keys"
+
+	// LastAppliedGenerationAnnotation records the generation of the
+	// AWSCluster whose spec has been applied to all network resources,
+	// together with a hash of the operator configuration, the operator build
+	// and the annotations that it has been applied with. While it is current
+	// and drift is not repaired, reconciliations of the ready AWSCluster only
+	// check drift, so that they do not revert changes made in AWS.
+	LastAppliedGenerationAnnotation = "aws-vpc-operator.giantswarm.io/last-applied-generation"
+
+	// OrphanResourcesOnDeleteAnnotation set to "true" on a deleted AWSCluster
+	// removes our finalizer without any AWS calls, leaving the VPC, subnets,
+	// route tables and VPC endpoints in AWS. It unblocks deletion when the
+	// AWSClusterRoleIdentity is gone or the role cannot be assumed anymore.
+	OrphanResourcesOnDeleteAnnotation = "aws-vpc-operator.giantswarm.io/orphan-resources-on-delete"
+
+	// ForceDeleteAnnotation set to "true" on a deleted AWSCluster continues
+	// deletion when deleting some resources failed, so that our finalizer is
+	// removed after all resources have been tried once. Resources that could
+	// not be deleted are left in AWS.
+	ForceDeleteAnnotation = "aws-vpc-operator.giantswarm.io/force-delete"
+)
+
+func isAnnotationTrue(annotations map[string]string, annotation string) bool {
+	return annotations[annotation] == "true"
+}
+
+func isUnmanagedNetwork(awsCluster *capa.AWSCluster) bool {
+	return awsCluster.Annotations[NetworkManagementAnnotation] == NetworkManagementUnmanaged
+}
@@ -19,34 +19,46 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"strings"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/giantswarm/k8smetadata/pkg/annotation"
 	"github.com/giantswarm/microerror"
 	"github.com/go-logr/logr"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/blockers"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/discovery"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/permissions"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/quotas"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/routetables"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/subnets"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpc"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
 )
 
 const (
@@ -63,30 +75,61 @@ type AWSClusterReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 
+	recorder record.EventRecorder
+
+	// config is the operator configuration, which is read at each use, as it
+	// is reloaded when the configuration file changes.
+	config *config.Store
+
+	// plans has the latest dry-run plan of each AWSCluster.
+	plans *dryrun.Plans
+
+	driftChecks *driftChecks
+
 	vpcReconciler         vpc.Reconciler
 	subnetsReconciler     subnets.Reconciler
 	subnetsClient         subnets.Client
 	routeTablesReconciler routetables.Reconciler
 	routeTablesClient     routetables.Client
 	vpcEndpointReconciler vpcendpoint.Reconciler
+	blockersClient        blockers.Client
+	discoveryClient       discovery.Client
+	permissionsClient     permissions.Client
+	quotasClient          quotas.Client
 }
 
 // NewAWSClusterReconciler creates a new AWSClusterReconciler for specified client and scheme.
 func NewAWSClusterReconciler(
 	client client.Client,
 	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 	ec2Client *ec2.Client,
 	assumeRoleClient assumerole.Client,
+	limits quotas.LimitsAPI,
+	config *config.Store,
+	plans *dryrun.Plans,
 ) (*AWSClusterReconciler, error) {
 	if client == nil {
 		return nil, microerror.Maskf(errors.InvalidConfigError, "client must not be empty")
 	}
+	if recorder == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "recorder must not be empty")
+	}
 	if ec2Client == nil {
 		return nil, microerror.Maskf(errors.InvalidConfigError, "ec2Client must not be empty")
 	}
 	if assumeRoleClient == nil {
 		return nil, microerror.Maskf(errors.InvalidConfigError, "assumeRoleClient must not be empty")
 	}
+	if limits == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "limits must not be empty")
+	}
+	if config == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "config must not be empty")
+	}
+	if plans == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "plans must not be empty")
+	}
 	var err error
 
 	var vpcReconciler vpc.Reconciler
@@ -152,9 +195,34 @@ func NewAWSClusterReconciler(
 		}
 	}
 
+	blockersClient, err := blockers.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	discoveryClient, err := discovery.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	permissionsClient, err := permissions.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	quotasClient, err := quotas.NewClient(ec2Client, assumeRoleClient, limits)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	return &AWSClusterReconciler{
-		Client: client,
-		Scheme: scheme,
+		Client:   client,
+		Scheme:   scheme,
+		recorder: recorder,
+
+		config:      config,
+		plans:       plans,
+		driftChecks: newDriftChecks(),
 
 		vpcReconciler:         vpcReconciler,
 		subnetsReconciler:     subnetsReconciler,
@@ -162,12 +230,19 @@ func NewAWSClusterReconciler(
 		routeTablesReconciler: routeTablesReconciler,
 		routeTablesClient:     routeTablesClient,
 		vpcEndpointReconciler: vpcEndpointReconciler,
+		blockersClient:        blockersClient,
+		discoveryClient:       discoveryClient,
+		permissionsClient:     permissionsClient,
+		quotasClient:          quotasClient,
 	}, nil
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io.giantswarm.io,resources=awsclusters,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io.giantswarm.io,resources=awsclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io.giantswarm.io,resources=awsclusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusterroleidentities,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -183,6 +258,11 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	log.Info("Started reconciling AWSCluster", "namespace", req.Namespace, "name", req.Name)
 	defer log.Info("Finished reconciling AWSCluster", "namespace", req.Namespace, "name", req.Name)
 
+	ctx, span := tracing.Start(ctx, "AWSClusterReconciler.Reconcile", tracing.Cluster(req.Namespace, req.Name)...)
+	defer func() {
+		tracing.End(span, reterr)
+	}()
+
 	//
 	// Get AWSCluster that we are reconciling
 	//
@@ -191,6 +271,8 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	err := r.Get(ctx, req.NamespacedName, awsCluster)
 	if apierrors.IsNotFound(err) {
 		log.Info("AWSCluster no longer exists")
+		r.plans.Delete(req.NamespacedName)
+		r.driftChecks.Delete(req.NamespacedName)
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		return ctrl.Result{}, microerror.Mask(err)
@@ -209,9 +291,33 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return
 	}
 
+	span.SetAttributes(tracing.RegionKey.String(awsCluster.Spec.Region))
+
+	// Check if reconciliation is paused, e.g. during clusterctl move or an
+	// incident. Paused AWSClusters are not changed, not even when deleted.
+	paused, err := r.isPaused(ctx, awsCluster)
+	if err != nil {
+		return ctrl.Result{}, microerror.Mask(err)
+	}
+	if paused {
+		log.Info("Reconciliation is paused, skipping", "namespace", req.Namespace, "name", req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// Deleted AWSClusters can be released without deleting AWS resources,
+	// e.g. when the identity or the role is gone.
+	deleted := !awsCluster.DeletionTimestamp.IsZero()
+	if deleted && isAnnotationTrue(awsCluster.Annotations, OrphanResourcesOnDeleteAnnotation) {
+		return ctrl.Result{}, microerror.Mask(r.orphanResources(ctx, awsCluster))
+	}
+
 	// We need Spec.IdentityRef to be set, TODO check this
 	if awsCluster.Spec.IdentityRef == nil {
-		return ctrl.Result{}, microerror.Maskf(errors.IdentityNotSetError, "AWSCluster %s/%s does not have Spec.IdentityRef set", awsCluster.Namespace, awsCluster.Name)
+		err = microerror.Maskf(errors.IdentityNotSetError, "AWSCluster %s/%s does not have Spec.IdentityRef set", awsCluster.Namespace, awsCluster.Name)
+		if deleted {
+			r.reportIdentityUnavailable(ctx, awsCluster, err)
+		}
+		return ctrl.Result{}, err
 	}
 
 	identity := &capa.AWSClusterRoleIdentity{}
@@ -221,10 +327,33 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	err = r.Get(ctx, identityNamespacedName, identity)
+	if deleted && apierrors.IsNotFound(err) {
+		r.reportIdentityUnavailable(ctx, awsCluster, err)
+	}
 	if err != nil {
 		return ctrl.Result{}, microerror.Mask(err)
 	}
 
+	//
+	// In dry-run mode, AWS clients record the changes in a plan instead of
+	// making them.
+	//
+	var plan *dryrun.Plan
+	var unchangedAWSCluster *capa.AWSCluster
+	if r.config.Get().DryRun {
+		plan = dryrun.NewPlan()
+		ctx = dryrun.NewContext(ctx, plan)
+		unchangedAWSCluster = awsCluster.DeepCopy()
+	} else {
+		r.plans.Delete(req.NamespacedName)
+	}
+
+	//
+	// The resources of the VPC are described once per reconciliation, and
+	// shared by all phases, until a change invalidates them.
+	//
+	ctx = snapshot.NewContext(ctx)
+
 	//
 	// Create patch helper that will update reconciler AWSCLuster if there are any changes in the CR
 	//
@@ -233,14 +362,24 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, microerror.Mask(err)
 	}
 	defer func() {
-		conditionsToUpdate := []capi.ConditionType{
-			capa.VpcReadyCondition,
-			capa.SubnetsReadyCondition,
-			// capa.RouteTablesReadyCondition,
+		patchedAWSCluster := awsCluster
+		var conditionsToUpdate []capi.ConditionType
+		if plan != nil {
+			// AWSCluster spec and status would be updated with resources
+			// that do not exist, so only the plan is written
+			patchedAWSCluster = unchangedAWSCluster
+			r.reportPlan(patchedAWSCluster, plan)
+			conditionsToUpdate = []capi.ConditionType{NoChangesPlanned}
+		} else {
+			phases := r.phases()
+			setNetworkReadyCondition(awsCluster, phases)
+			conditions.Delete(awsCluster, NoChangesPlanned)
+			conditionsToUpdate = append(phaseConditions(phases), NetworkReady, DeletionBlocked, NoChangesPlanned)
 		}
+
 		err := patchHelper.Patch(
 			ctx,
-			awsCluster,
+			patchedAWSCluster,
 			patch.WithOwnedConditions{
 				Conditions: conditionsToUpdate,
 			})
@@ -255,7 +394,9 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}()
 
-	if !awsCluster.DeletionTimestamp.IsZero() {
+	span.SetAttributes(tracing.RoleARNKey.String(identity.Spec.RoleArn))
+
+	if deleted {
 		return r.reconcileDelete(ctx, log, awsCluster, identity.Spec.RoleArn)
 	}
 
@@ -263,359 +404,121 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 }
 
 func (r *AWSClusterReconciler) reconcileNormal(ctx context.Context, logger logr.Logger, awsCluster *capa.AWSCluster, roleArn string) (_ ctrl.Result, reterr error) {
-	log := log.FromContext(ctx)
+	ctx, span := tracing.Start(ctx, "AWSClusterReconciler.reconcileNormal")
+	defer func() {
+		span.SetAttributes(tracing.VpcIdKey.String(awsCluster.Spec.NetworkSpec.VPC.ID))
+		tracing.End(span, reterr)
+	}()
+
 	// If the AWSCluster doesn't have our finalizer, add it.
 	controllerutil.AddFinalizer(awsCluster, AwsVpcOperatorFinalizer)
 
-	vpcSpec := vpc.Spec{
-		ClusterName:    awsCluster.Name,
-		RoleARN:        roleArn,
-		Region:         awsCluster.Spec.Region,
-		VpcId:          awsCluster.Spec.NetworkSpec.VPC.ID,
-		CidrBlock:      awsCluster.Spec.NetworkSpec.VPC.CidrBlock,
-		AdditionalTags: awsCluster.Spec.AdditionalTags,
-	}
-	status, err := r.vpcReconciler.Reconcile(ctx, vpcSpec)
+	phases := r.phases()
+	driftInterval, driftAutoRepair := r.driftSettings(ctx, awsCluster)
+	appliedState, err := r.appliedState(awsCluster)
 	if err != nil {
 		return ctrl.Result{}, microerror.Mask(err)
 	}
-
-	// Update AWSCluster CR
-	awsCluster.Spec.NetworkSpec.VPC.ID = status.VpcId
-	awsCluster.Spec.NetworkSpec.VPC.CidrBlock = status.CidrBlock
-	awsCluster.Spec.NetworkSpec.VPC.Tags = status.Tags
-	switch status.State {
-	case vpc.VpcStateAvailable:
-		conditions.MarkTrue(awsCluster, capa.VpcReadyCondition)
-	case vpc.VpcStatePending:
-		conditions.MarkFalse(awsCluster, capa.VpcReadyCondition, "VpcStatePending", capi.ConditionSeverityWarning, "VPC is in pending state")
-		return ctrl.Result{RequeueAfter: time.Minute}, nil
-	case "":
-		conditions.MarkFalse(awsCluster, capa.VpcReadyCondition, "VpcStateNotSet", capi.ConditionSeverityError, "VPC state is not set")
-		return ctrl.Result{}, microerror.Maskf(errors.VpcStateNotSetError, "VPC state is not set '%s'", status.State)
-	default:
-		conditions.MarkFalse(awsCluster, capa.VpcReadyCondition, "VpcStateUnknown", capi.ConditionSeverityError, "VPC is in unknown state")
-		return ctrl.Result{}, microerror.Maskf(errors.VpcStateUnknownError, "VPC is in unknown state '%s'", status.State)
-	}
-
-	//
-	// Reconcile subnets
-	//
-	subnetsReconcileRequest := subnets.ReconcileRequest{
-		Resource: awsCluster,
-		Spec: subnets.Spec{
-			ClusterName:    awsCluster.Name,
-			RoleARN:        roleArn,
-			VpcId:          awsCluster.Spec.NetworkSpec.VPC.ID,
-			AdditionalTags: awsCluster.Spec.AdditionalTags,
-			Region:         awsCluster.Spec.Region,
-		},
-	}
-	for _, awsSubnetSpec := range awsCluster.Spec.NetworkSpec.Subnets {
-		subnetSpec := subnets.SubnetSpec{
-			SubnetId:         awsSubnetSpec.ID,
-			CidrBlock:        awsSubnetSpec.CidrBlock,
-			AvailabilityZone: awsSubnetSpec.AvailabilityZone,
-			Tags:             awsSubnetSpec.Tags,
-		}
-		subnetsReconcileRequest.Spec.Subnets = append(subnetsReconcileRequest.Spec.Subnets, subnetSpec)
-	}
-	subnetsReconcileResult, err := r.subnetsReconciler.Reconcile(ctx, subnetsReconcileRequest)
-	if err != nil {
-		return ctrl.Result{}, microerror.Mask(err)
-	}
-
-	// Update AWSCluster subnets
-	allSubnetsAvailable := true
-	allRouteTablesReady := true
-	allRouteTablesNotReadyMessage := ""
-	allRouteTablesNotReadyReason := ""
-	for _, existingSubnet := range subnetsReconcileResult.Subnets {
-		for i := range awsCluster.Spec.NetworkSpec.Subnets {
-			desiredSubnetSpec := &awsCluster.Spec.NetworkSpec.Subnets[i]
-			if desiredSubnetSpec.ID == existingSubnet.SubnetId || desiredSubnetSpec.CidrBlock == existingSubnet.CidrBlock {
-				desiredSubnetSpec.ID = existingSubnet.SubnetId
-				desiredSubnetSpec.CidrBlock = existingSubnet.CidrBlock
-				desiredSubnetSpec.AvailabilityZone = existingSubnet.AvailabilityZone
-				desiredSubnetSpec.Tags = existingSubnet.Tags
-
-				// Update subnet route table ID in the subnet spec
-				if existingSubnet.RouteTableAssociation.RouteTableId != "" {
-					routeTableId := existingSubnet.RouteTableAssociation.RouteTableId
-					desiredSubnetSpec.RouteTableID = &routeTableId
-					if existingSubnet.RouteTableAssociation.AssociationStateCode != subnets.AssociationStateCodeAssociated {
-						allRouteTablesReady = false
-						allRouteTablesNotReadyMessage = fmt.Sprintf("Route table %s for subnet %s not ready", routeTableId, existingSubnet.SubnetId)
-						// e.g. RouteTableAssociationStateAssociating
-						allRouteTablesNotReadyReason = "RouteTableAssociationState" + cases.Title(language.English).String(string(existingSubnet.RouteTableAssociation.AssociationStateCode))
-					}
-				} else {
-					allRouteTablesReady = false
-					allRouteTablesNotReadyReason = "RouteTableNotCreated"
-					allRouteTablesNotReadyMessage = fmt.Sprintf("Route table not created for subnet %s", existingSubnet.SubnetId)
-				}
-
-				if existingSubnet.State != subnets.SubnetStateAvailable {
-					allSubnetsAvailable = false
-				}
-				break
-			}
-		}
-	}
-
-	subnetsReadyConditionSeverity := conditions.GetSeverity(awsCluster, capa.SubnetsReadyCondition)
-	subnetsReadyConditionSeverityInfo := subnetsReadyConditionSeverity != nil && *subnetsReadyConditionSeverity == capi.ConditionSeverityInfo
-
-	subnetsReadyConditionLastChange := conditions.GetLastTransitionTime(awsCluster, capa.SubnetsReadyCondition)
-	subnetsReadyConditionTimeSinceLastChange := 0 * time.Minute
-	if subnetsReadyConditionLastChange != nil {
-		subnetsReadyConditionTimeSinceLastChange = time.Since(subnetsReadyConditionLastChange.Time)
-	}
-
-	if !allSubnetsAvailable {
-		// subnets are not available, so we wait for subnets to become available before proceeding
-		const subnetsNotAvailableReason = "SubnetsNotAvailable"
-		var newSeverity capi.ConditionSeverity
-		var newMessage string
-		var requeueTime time.Duration
-
-		// initially retry more often, but then back off, so we don't hammer the API server
-		if subnetsReadyConditionTimeSinceLastChange < 5*time.Minute && subnetsReadyConditionSeverityInfo {
-			// it's been less than 5 minutes, all good, just an info here,
-			// let's try again in a minute
-			newSeverity = capi.ConditionSeverityInfo
-			requeueTime = 1 * time.Minute
-			newMessage = "One or more subnets is still not available"
-		} else if subnetsReadyConditionTimeSinceLastChange < 15*time.Minute && subnetsReadyConditionSeverityInfo {
-			// it's been less than 15 minutes, all good, just an info here,
-			// let just wait a bit longer and try again in 5 minutes
-			newSeverity = capi.ConditionSeverityInfo
-			requeueTime = 5 * time.Minute
-			newMessage = "One or more subnets is still not available"
-		} else {
-			// it's been more than 15 minutes, it's taking a while, so now
-			// it's a warning, and let's try again in 15 minutes
-			newSeverity = capi.ConditionSeverityWarning
-			requeueTime = 15 * time.Minute
-			newMessage = "One or more subnets is still not available for more than 15 minutes"
-		}
-
-		conditions.MarkFalse(awsCluster, capa.SubnetsReadyCondition, subnetsNotAvailableReason, newSeverity, "%s", newMessage)
-		return ctrl.Result{RequeueAfter: requeueTime}, nil
-	}
-
-	if allRouteTablesReady {
-		conditions.MarkTrue(awsCluster, capa.SubnetsReadyCondition)
-	} else {
-		// route tables are not ready (or not even created), we update condition
-		// and proceed with route tables reconciliation
-		if subnetsReadyConditionTimeSinceLastChange < 15*time.Minute && subnetsReadyConditionSeverityInfo {
-			// it's been less than 15 minutes, so all is still good
-			conditions.MarkFalse(awsCluster, capa.SubnetsReadyCondition, allRouteTablesNotReadyReason, capi.ConditionSeverityInfo, "%s", allRouteTablesNotReadyMessage)
-		} else {
-			// it's been more than 15 minutes, route tables should have been associated until now
-			conditions.MarkFalse(awsCluster, capa.SubnetsReadyCondition, allRouteTablesNotReadyReason, capi.ConditionSeverityWarning, "%s", allRouteTablesNotReadyMessage+" for more than 15 minutes")
-		}
-	}
-
-	//
-	// Reconcile route tables
-	//
-	{
-		reconcileRequest := aws.ReconcileRequest[routetables.Spec]{
-			Resource:    awsCluster,
-			ClusterName: awsCluster.Name,
-			CloudResourceRequest: aws.CloudResourceRequest[routetables.Spec]{
-				RoleARN:        roleArn,
-				Region:         awsCluster.Spec.Region,
-				AdditionalTags: awsCluster.Spec.AdditionalTags,
-				Spec: routetables.Spec{
-					VpcId: awsCluster.Spec.NetworkSpec.VPC.ID,
-				},
-			},
-		}
-		for _, awsSubnetSpec := range awsCluster.Spec.NetworkSpec.Subnets {
-			reconcileRequest.Spec.Subnets = append(reconcileRequest.Spec.Subnets, routetables.Subnet{
-				Id:               awsSubnetSpec.ID,
-				AvailabilityZone: awsSubnetSpec.AvailabilityZone,
-			})
-		}
-
-		result, err := r.routeTablesReconciler.Reconcile(ctx, reconcileRequest)
+	if r.isDriftCheck(ctx, awsCluster, appliedState, driftInterval, driftAutoRepair) {
+		plan, err := r.checkDrift(ctx, awsCluster, roleArn, phases)
 		if err != nil {
 			return ctrl.Result{}, microerror.Mask(err)
 		}
+		r.reportDrift(awsCluster, plan)
 
-		allRouteTablesReady = true
-		allRouteTablesNotReadyMessage = ""
-		allRouteTablesNotReadyReason = ""
-		for _, routeTableStatus := range result.Status {
-			for _, association := range routeTableStatus.RouteTableAssociation {
-				if association.AssociationStateCode != routetables.AssociationStateCodeAssociated {
-					allRouteTablesReady = false
-					allRouteTablesNotReadyMessage = fmt.Sprintf("Route table %s for subnet %s not ready", routeTableStatus.RouteTableId, association.SubnetId)
-					// e.g. RouteTableAssociationStateAssociating
-					allRouteTablesNotReadyReason = "RouteTableAssociationState" + cases.Title(language.English).String(string(association.AssociationStateCode))
-					break
-				}
-			}
+		if len(plan.Operations()) == 0 || !driftAutoRepair {
+			logger.Info("Checked drift", "changes", len(plan.Operations()), "auto-repair", driftAutoRepair)
+			r.driftChecks.Checked(client.ObjectKeyFromObject(awsCluster))
+			return ctrl.Result{RequeueAfter: r.driftRequeueAfter(driftInterval)}, nil
 		}
 
-		if allRouteTablesReady {
-			// route tables are not ready
-			conditions.MarkTrue(awsCluster, capa.RouteTablesReadyCondition)
-		} else {
-			// route tables are not ready, so we requeue, let's just check how
-			// much we should wait before retrying
-			routeTablesReadyConditionSeverity := conditions.GetSeverity(awsCluster, capa.RouteTablesReadyCondition)
-			routeTablesReadyConditionSeverityInfo := routeTablesReadyConditionSeverity != nil && *routeTablesReadyConditionSeverity == capi.ConditionSeverityInfo
-
-			routeTablesReadyConditionLastChange := conditions.GetLastTransitionTime(awsCluster, capa.RouteTablesReadyCondition)
-			timeSinceLastChange := 0 * time.Minute
-			if routeTablesReadyConditionLastChange != nil {
-				timeSinceLastChange = time.Since(routeTablesReadyConditionLastChange.Time)
-			}
-
-			// initially retry more often, but then back off, so we don't hammer the API server
-			if timeSinceLastChange < 5*time.Minute && routeTablesReadyConditionSeverityInfo {
-				// it's been less than 5 minutes, all good, just an info here,
-				// let's try again in a minute
-				conditions.MarkFalse(awsCluster, capa.RouteTablesReadyCondition, allRouteTablesNotReadyReason, capi.ConditionSeverityInfo, "%s", allRouteTablesNotReadyMessage)
-				return ctrl.Result{RequeueAfter: time.Minute}, nil
-			} else if timeSinceLastChange < 15*time.Minute && routeTablesReadyConditionSeverityInfo {
-				// it's been less than 15 minutes, all good, just an info here,
-				// let just wait a bit longer and try again in 5 minutes
-				conditions.MarkFalse(awsCluster, capa.RouteTablesReadyCondition, allRouteTablesNotReadyReason, capi.ConditionSeverityInfo, "%s", allRouteTablesNotReadyMessage)
-				return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
-			} else {
-				// it's been more than 15 minutes, it's taking a while, so now
-				// it's a warning, and let's try again in 15 minutes
-				conditions.MarkFalse(awsCluster, capa.RouteTablesReadyCondition, allRouteTablesNotReadyReason, capi.ConditionSeverityWarning, "%s", allRouteTablesNotReadyMessage+" for more than 15 minutes")
-				return ctrl.Result{RequeueAfter: 15 * time.Minute}, nil
-			}
-		}
+		logger.Info("Repairing drift", "changes", len(plan.Operations()))
+		r.recorder.Eventf(awsCluster, corev1.EventTypeNormal, RepairingDriftReason, "Repairing drift with %d changes", len(plan.Operations()))
+		driftRepaired.WithLabelValues(awsCluster.Namespace, awsCluster.Name).Inc()
 	}
 
-	cluster := &capi.Cluster{}
-	clusterKey := types.NamespacedName{
-		Namespace: awsCluster.Namespace,
-		Name:      awsCluster.Name,
+	result, err := r.runPhases(ctx, awsCluster, roleArn, phases)
+	if err == nil && allPhasesReady(awsCluster, phases) {
+		// all resources have been tagged with current AdditionalTags
+		setLastAppliedTagKeys(awsCluster)
+		if !dryrun.Enabled(ctx) {
+			setLastAppliedState(awsCluster, appliedState)
+		}
+		r.driftChecks.Checked(client.ObjectKeyFromObject(awsCluster))
 	}
-	err = r.Get(ctx, clusterKey, cluster)
-	if err != nil {
-		return ctrl.Result{}, microerror.Mask(err)
+	if err == nil && result.IsZero() && driftInterval > 0 {
+		// nothing else would recheck AWS resources until the AWSCluster
+		// changes, so the next drift check is scheduled
+		result.RequeueAfter = r.driftRequeueAfter(driftInterval)
 	}
 
-	//
-	// Reconcile VPC endpoints
-	//
-	if !conditions.IsTrue(awsCluster, capa.ClusterSecurityGroupsReadyCondition) {
-		// Security groups are still not ready, we wait for them first
-		conditions.MarkFalse(awsCluster, VpcEndpointReady, ClusterSecurityGroupsNotReady, capi.ConditionSeverityWarning, "Security groups are still not ready")
-		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
-	} else {
-		reconcileRequest := aws.ReconcileRequest[vpcendpoint.Spec]{
-			Resource:    awsCluster,
-			ClusterName: awsCluster.Name,
-			CloudResourceRequest: aws.CloudResourceRequest[vpcendpoint.Spec]{
-				RoleARN: roleArn,
-				Region:  awsCluster.Spec.Region,
-				Spec: vpcendpoint.Spec{
-					VpcId: awsCluster.Spec.NetworkSpec.VPC.ID,
-				},
-				AdditionalTags: awsCluster.Spec.AdditionalTags,
-			},
-		}
-
-		subnetIDs, err := r.subnetsClient.GetEndpointSubnets(ctx, subnets.GetEndpointSubnetsInput{
-			ClusterName: awsCluster.Name,
-			RoleARN:     roleArn,
-			Region:      awsCluster.Spec.Region,
-		})
-		if err != nil {
-			log.Error(err, "Failed to lookup subnets")
-			conditions.MarkFalse(awsCluster, VpcEndpointReady, SubnetLookupFailed, capi.ConditionSeverityWarning, "Failed to lookup subnets to use for VPC Endpoints")
-			return ctrl.Result{}, err
-		}
-		// If no specific subnets found we'll fallback to picking any from the cluster
-		if len(subnetIDs) == 0 {
-			log.Info("No specific subnets found for VPC endpoints, falling back to using subnets from AWSCluster spec")
-			selectedAZs := map[string]bool{}
-			for _, subnet := range awsCluster.Spec.NetworkSpec.Subnets {
-				if selectedAZs[subnet.AvailabilityZone] {
-					// VPC endpoints can only have a single subnet per AZ so we'll skip any additional
-					continue
-				}
-				subnetIDs = append(reconcileRequest.Spec.SubnetIds, subnet.ID)
-				selectedAZs[subnet.AvailabilityZone] = true
-			}
-		}
-		reconcileRequest.Spec.SubnetIds = subnetIDs
-
-		for _, securityGroup := range awsCluster.Status.Network.SecurityGroups {
-			reconcileRequest.Spec.SecurityGroupIds = append(reconcileRequest.Spec.SecurityGroupIds, securityGroup.ID)
-		}
-
-		routeTablesListOutput, err := r.routeTablesClient.List(ctx, routetables.ListRouteTablesInput{
-			Region:  awsCluster.Spec.Region,
-			RoleARN: roleArn,
-			VpcId:   awsCluster.Spec.NetworkSpec.VPC.ID,
-		})
-		if err != nil {
-			log.Error(err, "Failed to lookup route tables")
-			conditions.MarkFalse(awsCluster, VpcEndpointReady, SubnetLookupFailed, capi.ConditionSeverityWarning, "Failed to lookup route tables to use for VPC Endpoints")
-			return ctrl.Result{}, err
-		}
-
-		for _, rt := range routeTablesListOutput {
-			reconcileRequest.Spec.RouteTableIds = append(reconcileRequest.Spec.RouteTableIds, rt.RouteTableId)
-		}
+	return result, err
+}
 
-		result, err := r.vpcEndpointReconciler.Reconcile(ctx, reconcileRequest)
-		if err != nil {
-			conditions.MarkFalse(awsCluster, VpcEndpointReady, "ReconciliationError", capi.ConditionSeverityError, "An error occurred during reconciliation, check logs")
-			return ctrl.Result{}, microerror.Mask(err)
-		}
+func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, logger logr.Logger, awsCluster *capa.AWSCluster, roleArn string) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "AWSClusterReconciler.reconcileDelete", tracing.VpcIdKey.String(awsCluster.Spec.NetworkSpec.VPC.ID))
+	defer func() {
+		tracing.End(span, err)
+	}()
+	schedule := r.requeueSchedule(ctx, awsCluster)
+	unmanagedNetwork := isUnmanagedNetwork(awsCluster)
 
-		if strings.EqualFold(result.Status.VpcEndpointState, vpcendpoint.StateAvailable) {
-			conditions.MarkTrue(awsCluster, VpcEndpointReady)
-		} else {
-			reason := fmt.Sprintf("VpcEndpointState%s", result.Status.VpcEndpointState)
-			conditions.MarkFalse(awsCluster, VpcEndpointReady, reason, capi.ConditionSeverityWarning, "VPC endpoint is not available")
-			return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
-		}
+	//
+	// Find all resources that have to be deleted, both from AWSCluster spec
+	// and by the ownership tag of the cluster.
+	//
+	network, err := r.getDeletedNetwork(ctx, awsCluster, roleArn)
+	if err != nil && isDeletionForced(awsCluster) {
+		// without discovery, only the resources from AWSCluster spec are
+		// deleted
+		r.skipFailedDeletion(ctx, awsCluster, capa.VpcReadyCondition, err)
+		network = specDeletedNetwork(awsCluster)
+	} else if err != nil {
+		return handleError(ctx, awsCluster, capa.VpcReadyCondition, err)
 	}
 
-	return ctrl.Result{}, nil
-}
-
-func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, logger logr.Logger, awsCluster *capa.AWSCluster, roleArn string) (_ ctrl.Result, err error) {
 	//
 	// Delete VPC endpoint. We delete VPC endpoint first, regardless of what CAPA
 	// deleted (if anything) until now.
 	//
-	if isDeleted(awsCluster, VpcEndpointReady) {
+	if isDeleted(awsCluster, VpcEndpointReady) && len(network.VpcEndpointIds) == 0 {
 		logger.Info("VPC endpoint is already deleted")
 	} else {
-		vpcId := awsCluster.Spec.NetworkSpec.VPC.ID
-		logger.Info("Deleting VPC endpoint", "vpc-id", vpcId)
-		vpcEndpointDeleteRequest := aws.ReconcileRequest[aws.DeletedCloudResourceSpec]{
-			Resource:    awsCluster,
-			ClusterName: awsCluster.Name,
-			CloudResourceRequest: aws.CloudResourceRequest[aws.DeletedCloudResourceSpec]{
-				RoleARN: roleArn,
-				Region:  awsCluster.Spec.Region,
-				Spec: aws.DeletedCloudResourceSpec{
-					Id: awsCluster.Spec.NetworkSpec.VPC.ID,
+		for _, vpcId := range network.ContainingVpcIds {
+			logger.Info("Deleting VPC endpoint", "vpc-id", vpcId)
+			vpcEndpointDeleteRequest := aws.ReconcileRequest[vpcendpoint.DeleteSpec]{
+				Resource:    awsCluster,
+				ClusterName: awsCluster.Name,
+				ClusterUID:  string(awsCluster.UID),
+				CloudResourceRequest: aws.CloudResourceRequest[vpcendpoint.DeleteSpec]{
+					RoleARN: roleArn,
+					Region:  awsCluster.Spec.Region,
+					Spec: vpcendpoint.DeleteSpec{
+						VpcId:          vpcId,
+						Services:       r.config.Get().VpcEndpoints.GatewayServices,
+						VpcEndpointIds: network.VpcEndpointIds[vpcId],
+					},
 				},
-			},
-		}
-		err = r.vpcEndpointReconciler.ReconcileDelete(ctx, vpcEndpointDeleteRequest)
-		if err != nil {
-			return ctrl.Result{}, microerror.Mask(err)
+			}
+			err = r.vpcEndpointReconciler.ReconcileDelete(ctx, vpcEndpointDeleteRequest)
+			if errors.IsResourceDeletionInProgress(err) {
+				// route tables and the VPC cannot be deleted before the VPC
+				// endpoint is gone, so we wait here
+				decision := schedule.ForCondition(awsCluster, VpcEndpointReady)
+				conditions.MarkFalse(awsCluster, VpcEndpointReady, capi.DeletingReason, decision.Severity, "VPC endpoint is being deleted%s", decision.MessageSuffix)
+				logger.Info("Waiting for VPC endpoint to be deleted", "vpc-id", vpcId, "requeue-after", decision.RequeueAfter)
+				return ctrl.Result{RequeueAfter: decision.RequeueAfter}, nil
+			} else if errors.IsResourceNotOwned(err) {
+				r.reportNotOwned(ctx, awsCluster, VpcEndpointReady, err)
+			} else if err != nil && isDeletionForced(awsCluster) {
+				r.skipFailedDeletion(ctx, awsCluster, VpcEndpointReady, err)
+			} else if err != nil {
+				return handleError(ctx, awsCluster, VpcEndpointReady, err)
+			} else {
+				conditions.MarkFalse(awsCluster, VpcEndpointReady, capi.DeletedReason, capi.ConditionSeverityInfo, "VPC endpoint has been deleted")
+				logger.Info("Deleted VPC endpoint", "vpc-id", vpcId)
+			}
 		}
-		conditions.MarkFalse(awsCluster, VpcEndpointReady, capi.DeletedReason, capi.ConditionSeverityInfo, "VPC endpoint has been deleted")
-		logger.Info("Deleted VPC endpoint", "vpc-id", vpcId)
 	}
 
 	//
@@ -625,13 +528,19 @@ func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, logger logr.
 		if conditions.IsTrue(awsCluster, capa.LoadBalancerReadyCondition) ||
 			isBeingDeleted(awsCluster, capa.LoadBalancerReadyCondition) {
 			// load balancer deletion did not start, or it is in progress
-			logger.Info("Waiting for CAPA to delete load balancer, trying deletion again in a minute")
-			return ctrl.Result{RequeueAfter: time.Minute}, nil
+			decision := schedule.ForCondition(awsCluster, capa.LoadBalancerReadyCondition)
+			logger.Info("Waiting for CAPA to delete load balancer", "requeue-after", decision.RequeueAfter)
+			return ctrl.Result{RequeueAfter: decision.RequeueAfter}, nil
+		} else if deletionFailed(awsCluster, capa.LoadBalancerReadyCondition) && isDeletionForced(awsCluster) {
+			logger.Info("CAPA failed to delete load balancer, proceeding with deletion because deletion is forced")
+			r.recorder.Eventf(awsCluster, corev1.EventTypeWarning, DeletionForcedReason, "CAPA failed to delete load balancer, continuing because annotation %s is set", ForceDeleteAnnotation)
 		} else if deletionFailed(awsCluster, capa.LoadBalancerReadyCondition) {
-			logger.Info("CAPA failed to delete load balancer, trying deletion of route tables, subnets and VPC again in 15 minutes")
-			return ctrl.Result{RequeueAfter: 15 * time.Minute}, nil
+			decision := schedule.Last()
+			logger.Info("CAPA failed to delete load balancer, trying deletion of route tables, subnets and VPC again later", "requeue-after", decision.RequeueAfter)
+			return ctrl.Result{RequeueAfter: decision.RequeueAfter}, nil
+		} else {
+			logger.Info("CAPA deleted load balancer, proceeding with deletion")
 		}
-		logger.Info("CAPA deleted load balancer, proceeding with deletion")
 
 		//
 		// Wait for CAPA to delete security groups before we delete VPC, subnets and route tables.
@@ -639,13 +548,19 @@ func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, logger logr.
 		if conditions.IsTrue(awsCluster, capa.ClusterSecurityGroupsReadyCondition) ||
 			isBeingDeleted(awsCluster, capa.ClusterSecurityGroupsReadyCondition) {
 			// security groups deletion did not start, or it is in progress
-			logger.Info("Waiting for CAPA to delete security groups, trying deletion again in a minute")
-			return ctrl.Result{RequeueAfter: time.Minute}, nil
+			decision := schedule.ForCondition(awsCluster, capa.ClusterSecurityGroupsReadyCondition)
+			logger.Info("Waiting for CAPA to delete security groups", "requeue-after", decision.RequeueAfter)
+			return ctrl.Result{RequeueAfter: decision.RequeueAfter}, nil
+		} else if deletionFailed(awsCluster, capa.ClusterSecurityGroupsReadyCondition) && isDeletionForced(awsCluster) {
+			logger.Info("CAPA failed to delete security groups, proceeding with deletion because deletion is forced")
+			r.recorder.Eventf(awsCluster, corev1.EventTypeWarning, DeletionForcedReason, "CAPA failed to delete security groups, continuing because annotation %s is set", ForceDeleteAnnotation)
 		} else if deletionFailed(awsCluster, capa.ClusterSecurityGroupsReadyCondition) {
-			logger.Info("CAPA failed to delete security groups, trying deletion of route tables, subnets and VPC again in 15 minutes")
-			return ctrl.Result{RequeueAfter: 15 * time.Minute}, nil
+			decision := schedule.Last()
+			logger.Info("CAPA failed to delete security groups, trying deletion of route tables, subnets and VPC again later", "requeue-after", decision.RequeueAfter)
+			return ctrl.Result{RequeueAfter: decision.RequeueAfter}, nil
+		} else {
+			logger.Info("CAPA deleted security groups, proceeding with deletion")
 		}
-		logger.Info("CAPA deleted security groups, proceeding with deletion")
 	} else {
 		logger.Info("CAPA finalizer already gone, proceeding with deletion")
 	}
@@ -653,46 +568,58 @@ func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, logger logr.
 	//
 	// Delete route tables
 	//
-	if awsCluster.Spec.NetworkSpec.VPC.ID != "" {
-		logger.Info("Deleting route tables")
-		routeTablesDeleteRequest := aws.ReconcileRequest[aws.DeletedCloudResourceSpec]{
-			Resource:    awsCluster,
-			ClusterName: awsCluster.Name,
-			CloudResourceRequest: aws.CloudResourceRequest[aws.DeletedCloudResourceSpec]{
-				RoleARN: roleArn,
-				Region:  awsCluster.Spec.Region,
-				Spec: aws.DeletedCloudResourceSpec{
-					Id: awsCluster.Spec.NetworkSpec.VPC.ID,
-				},
-			},
-		}
+	if unmanagedNetwork {
+		skipUnmanagedDeletion(ctx, awsCluster, capa.RouteTablesReadyCondition)
+	} else if len(network.ContainingVpcIds) > 0 {
 		conditions.MarkFalse(awsCluster, capa.RouteTablesReadyCondition, capi.DeletingReason, capi.ConditionSeverityInfo, "Route tables are being deleted")
-		err = r.routeTablesReconciler.ReconcileDelete(ctx, routeTablesDeleteRequest)
-		if err != nil {
-			return ctrl.Result{}, microerror.Mask(err)
+		routeTablesSkipped := false
+		for _, vpcId := range network.ContainingVpcIds {
+			logger.Info("Deleting route tables", "vpc-id", vpcId)
+			routeTablesDeleteRequest := aws.ReconcileRequest[aws.DeletedCloudResourceSpec]{
+				Resource:    awsCluster,
+				ClusterName: awsCluster.Name,
+				ClusterUID:  string(awsCluster.UID),
+				CloudResourceRequest: aws.CloudResourceRequest[aws.DeletedCloudResourceSpec]{
+					RoleARN: roleArn,
+					Region:  awsCluster.Spec.Region,
+					Spec: aws.DeletedCloudResourceSpec{
+						Id: vpcId,
+					},
+				},
+			}
+			err = r.routeTablesReconciler.ReconcileDelete(ctx, routeTablesDeleteRequest)
+			if errors.IsResourceNotOwned(err) {
+				r.reportNotOwned(ctx, awsCluster, capa.RouteTablesReadyCondition, err)
+				routeTablesSkipped = true
+			} else if err != nil && isDeletionForced(awsCluster) {
+				r.skipFailedDeletion(ctx, awsCluster, capa.RouteTablesReadyCondition, err)
+				routeTablesSkipped = true
+			} else if err != nil {
+				return handleError(ctx, awsCluster, capa.RouteTablesReadyCondition, err)
+			}
 		}
-		// remove route table IDs
-		for i := range awsCluster.Spec.NetworkSpec.Subnets {
-			awsCluster.Spec.NetworkSpec.Subnets[i].RouteTableID = nil
+		if !routeTablesSkipped {
+			// remove route table IDs
+			for i := range awsCluster.Spec.NetworkSpec.Subnets {
+				awsCluster.Spec.NetworkSpec.Subnets[i].RouteTableID = nil
+			}
+			conditions.MarkFalse(awsCluster, capa.RouteTablesReadyCondition, capi.DeletedReason, capi.ConditionSeverityInfo, "Route tables have been deleted")
+			logger.Info("Deleted route tables")
 		}
-		conditions.MarkFalse(awsCluster, capa.RouteTablesReadyCondition, capi.DeletedReason, capi.ConditionSeverityInfo, "Route tables have been deleted")
-		logger.Info("Deleted route tables")
 	}
 
 	//
 	// Delete subnets
 	//
-	var subnetsToDelete []string
-	for _, subnet := range awsCluster.Spec.NetworkSpec.Subnets {
-		if subnet.ID != "" {
-			subnetsToDelete = append(subnetsToDelete, subnet.ID)
-		}
-	}
-	if len(subnetsToDelete) > 0 {
+	subnetsToDelete := network.SubnetIds
+	if unmanagedNetwork {
+		skipUnmanagedDeletion(ctx, awsCluster, capa.SubnetsReadyCondition)
+	} else if len(subnetsToDelete) > 0 {
 		logger.Info("Deleting subnets", "subnet-ids", subnetsToDelete)
 		subnetsDeleteRequest := aws.ReconcileRequest[[]aws.DeletedCloudResourceSpec]{
 			Resource:    awsCluster,
 			ClusterName: awsCluster.Name,
+			ClusterUID:  string(awsCluster.UID),
 			CloudResourceRequest: aws.CloudResourceRequest[[]aws.DeletedCloudResourceSpec]{
 				RoleARN: roleArn,
 				Region:  awsCluster.Spec.Region,
@@ -706,37 +633,63 @@ func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, logger logr.
 		}
 		conditions.MarkFalse(awsCluster, capa.SubnetsReadyCondition, capi.DeletingReason, capi.ConditionSeverityInfo, "Subnets are being deleted")
 		err = r.subnetsReconciler.ReconcileDelete(ctx, subnetsDeleteRequest)
-		if err != nil {
-			return ctrl.Result{}, microerror.Mask(err)
+		if errors.IsDependencyViolation(err) {
+			r.reportDeletionBlocked(ctx, awsCluster, roleArn, "subnets", blockers.ListBlockingResourcesInput{
+				SubnetIds: subnetsToDelete,
+			})
+		}
+		if errors.IsResourceNotOwned(err) {
+			r.reportNotOwned(ctx, awsCluster, capa.SubnetsReadyCondition, err)
+		} else if err != nil && isDeletionForced(awsCluster) {
+			r.skipFailedDeletion(ctx, awsCluster, capa.SubnetsReadyCondition, err)
+		} else if err != nil {
+			return handleError(ctx, awsCluster, capa.SubnetsReadyCondition, err)
+		} else {
+			conditions.Delete(awsCluster, DeletionBlocked)
+			conditions.MarkFalse(awsCluster, capa.SubnetsReadyCondition, capi.DeletedReason, capi.ConditionSeverityInfo, "Subnets have been deleted")
+			logger.Info("Deleted subnets")
 		}
-		conditions.MarkFalse(awsCluster, capa.SubnetsReadyCondition, capi.DeletedReason, capi.ConditionSeverityInfo, "Subnets have been deleted")
-		logger.Info("Deleted subnets")
 	}
 
 	//
-	// Delete VPC
+	// Delete VPCs
 	//
-	if awsCluster.Spec.NetworkSpec.VPC.ID != "" {
-		vpcId := awsCluster.Spec.NetworkSpec.VPC.ID
-		logger.Info("Deleting VPC", "vpc-id", vpcId)
-		vpcDeleteRequest := aws.ReconcileRequest[aws.DeletedCloudResourceSpec]{
-			Resource:    awsCluster,
-			ClusterName: awsCluster.Name,
-			CloudResourceRequest: aws.CloudResourceRequest[aws.DeletedCloudResourceSpec]{
-				RoleARN: roleArn,
-				Region:  awsCluster.Spec.Region,
-				Spec: aws.DeletedCloudResourceSpec{
-					Id: awsCluster.Spec.NetworkSpec.VPC.ID,
+	if unmanagedNetwork {
+		skipUnmanagedDeletion(ctx, awsCluster, capa.VpcReadyCondition)
+	} else {
+		for _, vpcId := range network.VpcIds {
+			logger.Info("Deleting VPC", "vpc-id", vpcId)
+			vpcDeleteRequest := aws.ReconcileRequest[aws.DeletedCloudResourceSpec]{
+				Resource:    awsCluster,
+				ClusterName: awsCluster.Name,
+				ClusterUID:  string(awsCluster.UID),
+				CloudResourceRequest: aws.CloudResourceRequest[aws.DeletedCloudResourceSpec]{
+					RoleARN: roleArn,
+					Region:  awsCluster.Spec.Region,
+					Spec: aws.DeletedCloudResourceSpec{
+						Id: vpcId,
+					},
 				},
-			},
-		}
-		conditions.MarkFalse(awsCluster, capa.VpcReadyCondition, capi.DeletingReason, capi.ConditionSeverityInfo, "VPC is being deleted")
-		err = r.vpcReconciler.ReconcileDelete(ctx, vpcDeleteRequest)
-		if err != nil {
-			return ctrl.Result{}, microerror.Mask(err)
+			}
+			conditions.MarkFalse(awsCluster, capa.VpcReadyCondition, capi.DeletingReason, capi.ConditionSeverityInfo, "VPC is being deleted")
+			err = r.vpcReconciler.ReconcileDelete(ctx, vpcDeleteRequest)
+			if errors.IsDependencyViolation(err) {
+				r.reportDeletionBlocked(ctx, awsCluster, roleArn, fmt.Sprintf("VPC %s", vpcId), blockers.ListBlockingResourcesInput{
+					VpcId: vpcId,
+				})
+			}
+			if errors.IsResourceNotOwned(err) {
+				r.reportNotOwned(ctx, awsCluster, capa.VpcReadyCondition, err)
+			} else if err != nil && isDeletionForced(awsCluster) {
+				r.skipFailedDeletion(ctx, awsCluster, capa.VpcReadyCondition, err)
+			} else if err != nil {
+				return handleError(ctx, awsCluster, capa.VpcReadyCondition, err)
+			} else {
+				conditions.Delete(awsCluster, DeletionBlocked)
+				conditions.MarkFalse(awsCluster, capa.VpcReadyCondition, capi.DeletedReason, capi.ConditionSeverityInfo, "VPC has been deleted")
+				logger.Info("Deleted VPC", "vpc-id", vpcId)
+			}
 		}
-		conditions.MarkFalse(awsCluster, capa.VpcReadyCondition, capi.DeletedReason, capi.ConditionSeverityInfo, "VPC has been deleted")
-		logger.Info("Deleted VPC", "vpc-id", vpcId)
 	}
 
 	// Cluster is deleted so remove the finalizer.
@@ -744,9 +697,27 @@ func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, logger logr.
 	return ctrl.Result{}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *AWSClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+// SetupWithManager sets up the controller with the Manager. The options
+// configure e.g. the number of concurrent reconciliations.
+func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	logger := mgr.GetLogger().WithValues("controller", "AWSCluster")
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&capa.AWSCluster{}).
+		For(&capa.AWSCluster{}, builder.WithPredicates(
+			reconciledAWSClusters(),
+			predicates.ResourceNotPaused(logger),
+		)).
+		Watches(
+			&capi.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(util.ClusterToInfrastructureMapFunc(ctx, capa.GroupVersion.WithKind("AWSCluster"), mgr.GetClient(), &capa.AWSCluster{})),
+			// reconcile AWSClusters when their Cluster is unpaused
+			builder.WithPredicates(predicates.ClusterUnpaused(logger)),
+		).
+		Watches(
+			&capa.AWSClusterRoleIdentity{},
+			handler.EnqueueRequestsFromMapFunc(r.identityToAWSClusters),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		WithOptions(options).
 		Complete(r)
 }
@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"k8s.io/client-go/tools/record"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+// dryRunOperationError is returned by EC2 API calls with DryRun set that
+// would have succeeded.
+var dryRunOperationError = &smithy.GenericAPIError{Code: "DryRunOperation"}
+
+// fakeNetwork is the network of a cluster in AWS, which is changed by the
+// faked EC2 API calls.
+type fakeNetwork struct {
+	// tags of all resources, by resource ID
+	tags map[string]map[string]string
+
+	// route table ID -> associated subnet IDs
+	associations map[string][]string
+
+	vpcEndpointRouteTableIds []string
+	createdRouteTables       int
+}
+
+func newFakeNetwork(clusterName string) *fakeNetwork {
+	owned := func() map[string]string {
+		return map[string]string{tags.NameAWSProviderPrefix + clusterName: "owned"}
+	}
+	return &fakeNetwork{
+		tags: map[string]map[string]string{
+			"vpc-1":      owned(),
+			"subnet-a":   owned(),
+			"subnet-b":   owned(),
+			"rtb-main":   {},
+			"rtb-a":      owned(),
+			"rtb-b":      owned(),
+			"vpce-s3":    owned(),
+			"rtbassoc-0": {},
+		},
+		associations: map[string][]string{
+			"rtb-main": nil,
+			"rtb-a":    {"subnet-a"},
+			"rtb-b":    {"subnet-b"},
+		},
+		vpcEndpointRouteTableIds: []string{"rtb-a", "rtb-b", "rtb-main"},
+	}
+}
+
+func (n *fakeNetwork) ec2Tags(resourceId string) []ec2Types.Tag {
+	var ec2Tags []ec2Types.Tag
+	for key, value := range n.tags[resourceId] {
+		ec2Tags = append(ec2Tags, ec2Types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return ec2Tags
+}
+
+func (n *fakeNetwork) EC2() *awstest.EC2 {
+	subnet := func(id, cidrBlock, zone string) ec2Types.Subnet {
+		return ec2Types.Subnet{
+			SubnetId:         aws.String(id),
+			VpcId:            aws.String("vpc-1"),
+			CidrBlock:        aws.String(cidrBlock),
+			AvailabilityZone: aws.String(zone),
+			State:            ec2Types.SubnetStateAvailable,
+			Tags:             n.ec2Tags(id),
+		}
+	}
+
+	return awstest.NewEC2().
+		On("DescribeVpcs", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcsOutput{Vpcs: []ec2Types.Vpc{
+				{VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.0.0/16"), State: ec2Types.VpcStateAvailable, Tags: n.ec2Tags("vpc-1")},
+			}}, nil
+		}).
+		On("DescribeSubnets", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeSubnetsOutput{Subnets: []ec2Types.Subnet{
+				subnet("subnet-a", "10.0.0.0/24", "eu-west-1a"),
+				subnet("subnet-b", "10.0.1.0/24", "eu-west-1b"),
+			}}, nil
+		}).
+		On("DescribeRouteTables", func(interface{}) (interface{}, error) {
+			output := &ec2.DescribeRouteTablesOutput{}
+			for routeTableId, subnetIds := range n.associations {
+				routeTable := ec2Types.RouteTable{RouteTableId: aws.String(routeTableId), VpcId: aws.String("vpc-1"), Tags: n.ec2Tags(routeTableId)}
+				if routeTableId == "rtb-main" {
+					routeTable.Associations = append(routeTable.Associations, ec2Types.RouteTableAssociation{RouteTableAssociationId: aws.String("rtbassoc-0"), Main: aws.Bool(true)})
+				}
+				for _, subnetId := range subnetIds {
+					routeTable.Associations = append(routeTable.Associations, ec2Types.RouteTableAssociation{
+						RouteTableAssociationId: aws.String("rtbassoc-" + subnetId),
+						RouteTableId:            aws.String(routeTableId),
+						SubnetId:                aws.String(subnetId),
+						AssociationState:        &ec2Types.RouteTableAssociationState{State: ec2Types.RouteTableAssociationStateCodeAssociated},
+					})
+				}
+				output.RouteTables = append(output.RouteTables, routeTable)
+			}
+			return output, nil
+		}).
+		On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcEndpointsOutput{VpcEndpoints: []ec2Types.VpcEndpoint{
+				{
+					VpcEndpointId:   aws.String("vpce-s3"),
+					VpcId:           aws.String("vpc-1"),
+					ServiceName:     aws.String(vpcendpoint.ServiceName(awstest.Region, vpcendpoint.S3)),
+					VpcEndpointType: ec2Types.VpcEndpointTypeGateway,
+					State:           ec2Types.StateAvailable,
+					RouteTableIds:   append([]string{}, n.vpcEndpointRouteTableIds...),
+					Tags:            n.ec2Tags("vpce-s3"),
+				},
+			}}, nil
+		}).
+		On("CreateTags", func(input interface{}) (interface{}, error) {
+			if aws.ToBool(input.(*ec2.CreateTagsInput).DryRun) {
+				return nil, dryRunOperationError
+			}
+			for _, resourceId := range input.(*ec2.CreateTagsInput).Resources {
+				for _, tag := range input.(*ec2.CreateTagsInput).Tags {
+					n.tags[resourceId][aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+			return &ec2.CreateTagsOutput{}, nil
+		}).
+		On("DeleteTags", func(input interface{}) (interface{}, error) {
+			if aws.ToBool(input.(*ec2.DeleteTagsInput).DryRun) {
+				return nil, dryRunOperationError
+			}
+			for _, resourceId := range input.(*ec2.DeleteTagsInput).Resources {
+				for _, tag := range input.(*ec2.DeleteTagsInput).Tags {
+					delete(n.tags[resourceId], aws.ToString(tag.Key))
+				}
+			}
+			return &ec2.DeleteTagsOutput{}, nil
+		}).
+		On("CreateRouteTable", func(input interface{}) (interface{}, error) {
+			if aws.ToBool(input.(*ec2.CreateRouteTableInput).DryRun) {
+				return nil, dryRunOperationError
+			}
+			n.createdRouteTables++
+			routeTableId := fmt.Sprintf("rtb-new-%d", n.createdRouteTables)
+			n.tags[routeTableId] = map[string]string{}
+			for _, tag := range input.(*ec2.CreateRouteTableInput).TagSpecifications[0].Tags {
+				n.tags[routeTableId][aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			n.associations[routeTableId] = nil
+			return &ec2.CreateRouteTableOutput{RouteTable: &ec2Types.RouteTable{RouteTableId: aws.String(routeTableId)}}, nil
+		}).
+		On("AssociateRouteTable", func(input interface{}) (interface{}, error) {
+			if aws.ToBool(input.(*ec2.AssociateRouteTableInput).DryRun) {
+				return nil, dryRunOperationError
+			}
+			routeTableId := aws.ToString(input.(*ec2.AssociateRouteTableInput).RouteTableId)
+			n.associations[routeTableId] = append(n.associations[routeTableId], aws.ToString(input.(*ec2.AssociateRouteTableInput).SubnetId))
+			return &ec2.AssociateRouteTableOutput{
+				AssociationState: &ec2Types.RouteTableAssociationState{State: ec2Types.RouteTableAssociationStateCodeAssociated},
+			}, nil
+		}).
+		On("ModifyVpcEndpoint", func(input interface{}) (interface{}, error) {
+			modifyInput := input.(*ec2.ModifyVpcEndpointInput)
+			if aws.ToBool(modifyInput.DryRun) {
+				return nil, dryRunOperationError
+			}
+			routeTableIds := map[string]bool{}
+			for _, routeTableId := range n.vpcEndpointRouteTableIds {
+				routeTableIds[routeTableId] = true
+			}
+			for _, routeTableId := range modifyInput.RemoveRouteTableIds {
+				delete(routeTableIds, routeTableId)
+			}
+			for _, routeTableId := range modifyInput.AddRouteTableIds {
+				routeTableIds[routeTableId] = true
+			}
+			n.vpcEndpointRouteTableIds = nil
+			for routeTableId := range routeTableIds {
+				n.vpcEndpointRouteTableIds = append(n.vpcEndpointRouteTableIds, routeTableId)
+			}
+			return &ec2.ModifyVpcEndpointOutput{}, nil
+		})
+}
+
+// networkTestEnv has an AWSClusterReconciler for an AWSCluster in private VPC
+// mode with the VPC, subnets, route tables and VPC endpoint of a fakeNetwork.
+// Permissions and quotas are not checked.
+type networkTestEnv struct {
+	network    *fakeNetwork
+	ec2        *awstest.EC2
+	k8sClient  client.Client
+	recorder   *record.FakeRecorder
+	reconciler *AWSClusterReconciler
+	request    ctrl.Request
+}
+
+func newNetworkTestEnv(t *testing.T, annotations map[string]string) networkTestEnv {
+	allAnnotations := map[string]string{annotation.AWSVPCMode: annotation.AWSVPCModePrivate}
+	for key, value := range annotations {
+		allAnnotations[key] = value
+	}
+	awsCluster := testAWSCluster("test", "test", allAnnotations)
+	awsCluster.Spec.Region = awstest.Region
+	awsCluster.Spec.NetworkSpec.VPC.ID = "vpc-1"
+	awsCluster.Spec.NetworkSpec.Subnets = capa.Subnets{
+		{ID: "subnet-a", CidrBlock: "10.0.0.0/24", AvailabilityZone: "eu-west-1a"},
+		{ID: "subnet-b", CidrBlock: "10.0.1.0/24", AvailabilityZone: "eu-west-1b"},
+	}
+	conditions.MarkTrue(awsCluster, capa.ClusterSecurityGroupsReadyCondition)
+
+	cluster := &capi.Cluster{}
+	cluster.Namespace = awsCluster.Namespace
+	cluster.Name = awsCluster.Name
+	identity := &capa.AWSClusterRoleIdentity{}
+	// the fake client treats all objects as namespaced
+	identity.Namespace = awsCluster.Namespace
+	identity.Name = "test"
+	identity.Spec.RoleArn = awstest.RoleARN
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(testScheme(t)).
+		WithObjects(awsCluster, cluster, identity).
+		WithStatusSubresource(&capa.AWSCluster{}).
+		Build()
+
+	base := config.Default()
+	base.FeatureGates = map[config.Feature]bool{
+		config.PermissionsPreflight: false,
+		config.QuotaPreflight:       false,
+	}
+	operatorConfig, err := config.Parse(nil, base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	network := newFakeNetwork(awsCluster.Name)
+	fakeEC2 := network.EC2()
+	recorder := record.NewFakeRecorder(100)
+	r, err := NewAWSClusterReconciler(k8sClient, testScheme(t), recorder, fakeEC2.Client(), awstest.AssumeRoleClient{}, awstest.Limits{}, config.NewStore(operatorConfig), dryrun.NewPlans())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return networkTestEnv{
+		network:    network,
+		ec2:        fakeEC2,
+		k8sClient:  k8sClient,
+		recorder:   recorder,
+		reconciler: r,
+		request:    ctrl.Request{NamespacedName: client.ObjectKeyFromObject(awsCluster)},
+	}
+}
+
+func Test_Reconcile_DescribeCalls(t *testing.T) {
+	testCases := []struct {
+		name string
+		// change is applied to the network after it converged
+		change               func(n *fakeNetwork)
+		expectedCalls        map[string]int
+		expectedNetworkReady bool
+	}{
+		{
+			name: "network is up-to-date",
+			expectedCalls: map[string]int{
+				"DescribeVpcs":         1,
+				"DescribeSubnets":      1,
+				"DescribeRouteTables":  1,
+				"DescribeVpcEndpoints": 1,
+			},
+			expectedNetworkReady: true,
+		},
+		{
+			name: "route tables are described again after a route table is created",
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			expectedCalls: map[string]int{
+				"DescribeVpcs":         1,
+				"DescribeSubnets":      1,
+				"DescribeRouteTables":  2,
+				"DescribeVpcEndpoints": 1,
+				"CreateRouteTable":     1,
+				"AssociateRouteTable":  1,
+				"ModifyVpcEndpoint":    1,
+			},
+			// the subnets phase saw the subnet without a route table
+			expectedNetworkReady: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			// with auto-repair, the reconciliation before the next drift
+			// check is a regular one
+			env := newNetworkTestEnv(t, map[string]string{DriftAutoRepairAnnotation: "true"})
+			network, fakeEC2, r, request := env.network, env.ec2, env.reconciler, env.request
+
+			// the first reconciliation sets the tags of all resources
+			_, err := r.Reconcile(ctx, request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.change != nil {
+				tc.change(network)
+			}
+
+			converged := len(fakeEC2.AllCalls())
+			_, err = r.Reconcile(ctx, request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			calls := map[string]int{}
+			for _, call := range fakeEC2.AllCalls()[converged:] {
+				calls[call.Operation]++
+			}
+			if !reflect.DeepEqual(calls, tc.expectedCalls) {
+				t.Errorf("expected EC2 calls %v, got %v", tc.expectedCalls, calls)
+			}
+
+			reconciled := &capa.AWSCluster{}
+			err = env.k8sClient.Get(ctx, request.NamespacedName, reconciled)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if conditions.IsTrue(reconciled, NetworkReady) != tc.expectedNetworkReady {
+				t.Errorf("expected NetworkReady condition to be %t, got %q", tc.expectedNetworkReady, conditions.GetMessage(reconciled, NetworkReady))
+			}
+		})
+	}
+}
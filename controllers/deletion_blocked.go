@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/blockers"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+const (
+	// DeletionBlocked is set to true when subnets or the VPC cannot be deleted,
+	// because other resources still depend on them. The condition message
+	// lists the blocking resources.
+	DeletionBlocked capi.ConditionType = "DeletionBlocked"
+
+	DependencyViolationReason     = "DependencyViolation"
+	LeftoverResourcesDeletedEvent = "LeftoverResourcesDeleted"
+
+	// maxBlockingResourcesInMessage limits the length of the condition and
+	// event messages when there are many blocking resources.
+	maxBlockingResourcesInMessage = 10
+)
+
+// reportDeletionBlocked is called when deletion of subnets or the VPC failed
+// with DependencyViolation. It finds the resources that block the deletion and
+// reports them in the DeletionBlocked condition and in an event. When the
+// AWSCluster has DeleteLeftoverResourcesAnnotation set, and the
+// DeleteLeftoverResources feature gate is enabled, blocking resources owned by
+// the cluster are deleted first, so that the next deletion attempt can
+// succeed.
+//
+// Errors are only logged, as the original DependencyViolation error is the
+// one that is handled by the caller.
+func (r *AWSClusterReconciler) reportDeletionBlocked(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string, blockedResource string, listInput blockers.ListBlockingResourcesInput) {
+	logger := log.FromContext(ctx)
+
+	listInput.RoleARN = roleArn
+	listInput.Region = awsCluster.Spec.Region
+	blockingResources, err := r.blockersClient.List(ctx, listInput)
+	if err != nil {
+		logger.Error(err, "Failed to find resources that block deletion", "blocked-resource", blockedResource)
+		return
+	}
+
+	deleteLeftoverResources := isAnnotationTrue(awsCluster.Annotations, DeleteLeftoverResourcesAnnotation) &&
+		r.config.Get().Enabled(config.DeleteLeftoverResources)
+	if deleteLeftoverResources && len(blockingResources) > 0 {
+		deleteOutput, err := r.blockersClient.DeleteOwned(ctx, blockers.DeleteOwnedInput{
+			RoleARN:     roleArn,
+			Region:      awsCluster.Spec.Region,
+			ClusterName: awsCluster.Name,
+			ClusterUID:  string(awsCluster.UID),
+			Resources:   blockingResources,
+		})
+		if err != nil {
+			logger.Error(err, "Failed to delete leftover resources that block deletion", "blocked-resource", blockedResource)
+		} else {
+			if len(deleteOutput.Deleted) > 0 {
+				r.recorder.Eventf(awsCluster, corev1.EventTypeNormal, LeftoverResourcesDeletedEvent,
+					"Deleted leftover resources that blocked deletion of %s: %s", blockedResource, formatBlockingResources(deleteOutput.Deleted))
+			}
+			blockingResources = deleteOutput.Skipped
+		}
+	}
+
+	var message string
+	if len(blockingResources) == 0 {
+		message = fmt.Sprintf("Deletion of %s is blocked by resources that depend on it", blockedResource)
+	} else {
+		message = fmt.Sprintf("Deletion of %s is blocked by %d resource(s): %s", blockedResource, len(blockingResources), formatBlockingResources(blockingResources))
+	}
+
+	conditions.Set(awsCluster, &capi.Condition{
+		Type:    DeletionBlocked,
+		Status:  corev1.ConditionTrue,
+		Reason:  DependencyViolationReason,
+		Message: message,
+	})
+	r.recorder.Event(awsCluster, corev1.EventTypeWarning, string(DeletionBlocked), message)
+}
+
+func formatBlockingResources(resources []blockers.BlockingResource) string {
+	var formatted []string
+	for i, resource := range resources {
+		if i == maxBlockingResourcesInMessage {
+			formatted = append(formatted, fmt.Sprintf("and %d more", len(resources)-maxBlockingResourcesInMessage))
+			break
+		}
+		formatted = append(formatted, resource.String())
+	}
+	return strings.Join(formatted, ", ")
+}
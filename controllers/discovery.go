@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/giantswarm/microerror"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/discovery"
+)
+
+// deletedNetwork contains IDs of all resources that are deleted when the
+// AWSCluster is deleted, i.e. the resources from AWSCluster spec together with
+// the resources that are discovered by the ownership tag of the cluster.
+type deletedNetwork struct {
+	// VpcIds are VPCs that are deleted, i.e. the VPC from AWSCluster spec
+	// and all VPCs owned by the cluster.
+	VpcIds []string
+
+	// ContainingVpcIds are VPCs in which VPC endpoints and route tables are
+	// deleted. Besides VpcIds, these are also VPCs that are not owned by the
+	// cluster, but contain resources that are owned by the cluster.
+	ContainingVpcIds []string
+
+	SubnetIds []string
+
+	// VpcEndpointIds are VPC endpoints owned by the cluster that still exist,
+	// by the ID of the VPC in which they are. They are deleted by ID, so that
+	// interface endpoints and endpoints of services that were removed from
+	// the configuration are deleted as well.
+	VpcEndpointIds map[string][]string
+}
+
+// getDeletedNetwork merges resources from AWSCluster spec with the resources
+// discovered by the ownership tag of the cluster, so that no resources are
+// left behind when their IDs are missing from the AWSCluster, e.g. because
+// the spec was edited or restored from a backup, or it was never patched
+// after the resources were created.
+func (r *AWSClusterReconciler) getDeletedNetwork(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string) (deletedNetwork, error) {
+	logger := log.FromContext(ctx)
+
+	discoverInput := discovery.DiscoverInput{
+		RoleARN:     roleArn,
+		Region:      awsCluster.Spec.Region,
+		ClusterName: awsCluster.Name,
+		ClusterUID:  string(awsCluster.UID),
+	}
+	discovered, err := r.discoveryClient.Discover(ctx, discoverInput)
+	if err != nil {
+		return deletedNetwork{}, microerror.Mask(err)
+	}
+
+	spec := specDeletedNetwork(awsCluster)
+	network := deletedNetwork{
+		VpcIds:           union(spec.VpcIds, discovered.Ids(discovery.ResourceTypeVpc)),
+		ContainingVpcIds: union(spec.ContainingVpcIds, discovered.VpcIds()),
+		SubnetIds:        union(spec.SubnetIds, discovered.Ids(discovery.ResourceTypeSubnet)),
+		VpcEndpointIds:   discovered.IdsByVpc(discovery.ResourceTypeVpcEndpoint),
+	}
+
+	if notInSpec := difference(network.VpcIds, spec.VpcIds); len(notInSpec) > 0 {
+		logger.Info("Discovered VPCs owned by the cluster that are not in AWSCluster spec", "vpc-ids", notInSpec)
+	}
+	if notInSpec := difference(network.SubnetIds, spec.SubnetIds); len(notInSpec) > 0 {
+		logger.Info("Discovered subnets owned by the cluster that are not in AWSCluster spec", "subnet-ids", notInSpec)
+	}
+
+	return network, nil
+}
+
+// specDeletedNetwork contains only the resources from AWSCluster spec, e.g.
+// when discovery failed and deletion is forced.
+func specDeletedNetwork(awsCluster *capa.AWSCluster) deletedNetwork {
+	var vpcIds, subnetIds []string
+	if awsCluster.Spec.NetworkSpec.VPC.ID != "" {
+		vpcIds = append(vpcIds, awsCluster.Spec.NetworkSpec.VPC.ID)
+	}
+	for _, subnet := range awsCluster.Spec.NetworkSpec.Subnets {
+		if subnet.ID != "" {
+			subnetIds = append(subnetIds, subnet.ID)
+		}
+	}
+
+	return deletedNetwork{
+		VpcIds:           vpcIds,
+		ContainingVpcIds: vpcIds,
+		SubnetIds:        subnetIds,
+	}
+}
+
+// union returns sorted unique strings from both slices.
+func union(s1, s2 []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, s := range append(append([]string{}, s1...), s2...) {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// difference returns strings from s1 that are not in s2.
+func difference(s1, s2 []string) []string {
+	var result []string
+	for _, s := range s1 {
+		found := false
+		for _, s2Item := range s2 {
+			if s == s2Item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/microerror"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	DriftDetectedReason  = "DriftDetected"
+	RepairingDriftReason = "RepairingDrift"
+)
+
+var (
+	clusterLabels = []string{"namespace", "name"}
+
+	driftChanges = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "aws_vpc_operator",
+		Subsystem: "drift",
+		Name:      "changes",
+		Help:      "Number of changes of AWS resources that are needed to repair the drift found by the last drift check of the AWSCluster.",
+	}, clusterLabels)
+
+	driftDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "aws_vpc_operator",
+		Subsystem: "drift",
+		Name:      "detected_total",
+		Help:      "Number of drift checks of the AWSCluster that found drift.",
+	}, clusterLabels)
+
+	driftRepaired = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "aws_vpc_operator",
+		Subsystem: "drift",
+		Name:      "repaired_total",
+		Help:      "Number of drift checks of the AWSCluster after which the drift was repaired.",
+	}, clusterLabels)
+)
+
+func init() {
+	metrics.Registry.MustRegister(driftChanges, driftDetected, driftRepaired)
+}
+
+// driftChecks has the time of the last drift check of each AWSCluster. A
+// reconciliation that finds all network resources ready counts as a drift
+// check too. It is safe for concurrent use.
+//
+// Times are kept in memory only, so after a restart the first reconciliation
+// of each ready AWSCluster is a drift check.
+type driftChecks struct {
+	mutex       sync.Mutex
+	lastChecked map[types.NamespacedName]time.Time
+}
+
+func newDriftChecks() *driftChecks {
+	return &driftChecks{
+		lastChecked: map[types.NamespacedName]time.Time{},
+	}
+}
+
+// Due checks if the last check of the AWSCluster is at least interval ago, or
+// if it has not been checked yet.
+func (d *driftChecks) Due(key types.NamespacedName, interval time.Duration) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	lastChecked, ok := d.lastChecked[key]
+	return !ok || time.Since(lastChecked) >= interval
+}
+
+func (d *driftChecks) Checked(key types.NamespacedName) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.lastChecked[key] = time.Now()
+}
+
+// Delete forgets the AWSCluster and removes its drift metrics.
+func (d *driftChecks) Delete(key types.NamespacedName) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.lastChecked, key)
+
+	labels := prometheus.Labels{"namespace": key.Namespace, "name": key.Name}
+	driftChanges.Delete(labels)
+	driftDetected.Delete(labels)
+	driftRepaired.Delete(labels)
+}
+
+// driftSettings returns the drift check interval and whether drift is
+// repaired for the AWSCluster. The operator configuration can be overridden
+// per cluster with DriftCheckIntervalAnnotation and DriftAutoRepairAnnotation.
+// Invalid annotations are logged and ignored.
+func (r *AWSClusterReconciler) driftSettings(ctx context.Context, awsCluster *capa.AWSCluster) (time.Duration, bool) {
+	logger := log.FromContext(ctx)
+	interval := r.config.Get().Drift.Interval.Duration
+	autoRepair := r.config.Get().Drift.AutoRepair
+
+	if rawInterval, ok := awsCluster.Annotations[DriftCheckIntervalAnnotation]; ok {
+		parsed, err := time.ParseDuration(rawInterval)
+		if err == nil && parsed < 0 {
+			err = microerror.Maskf(errors.InvalidConfigError, "%s must not be negative", DriftCheckIntervalAnnotation)
+		}
+		if err != nil {
+			logger.Error(err, "Invalid drift check interval annotation, using the default interval", "annotation", DriftCheckIntervalAnnotation)
+		} else {
+			interval = parsed
+		}
+	}
+
+	if rawAutoRepair, ok := awsCluster.Annotations[DriftAutoRepairAnnotation]; ok {
+		parsed, err := strconv.ParseBool(rawAutoRepair)
+		if err != nil {
+			logger.Error(err, "Invalid drift auto-repair annotation, using the default", "annotation", DriftAutoRepairAnnotation)
+		} else {
+			autoRepair = parsed
+		}
+	}
+
+	return interval, autoRepair
+}
+
+// driftRequeueAfter returns the time after which the next drift check of a
+// ready AWSCluster runs, with the jitter of the requeue schedule, so that the
+// checks of clusters that became ready together are spread.
+func (r *AWSClusterReconciler) driftRequeueAfter(interval time.Duration) time.Duration {
+	return wait.Jitter(interval, r.config.Get().Requeue.Jitter)
+}
+
+// checkDrift runs the phases on a copy of the AWSCluster in dry-run mode, and
+// returns the plan with the changes that would repair the drift of the AWS
+// resources from the AWSCluster. The AWSCluster is not changed.
+func (r *AWSClusterReconciler) checkDrift(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string, phases []phase) (*dryrun.Plan, error) {
+	plan := dryrun.NewPlan()
+	_, err := r.runPhases(dryrun.NewContext(ctx, plan), awsCluster.DeepCopy(), roleArn, phases)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return plan, nil
+}
+
+// reportDrift reports the changes found by a drift check in the drift
+// metrics, and in an event when there are any.
+func (r *AWSClusterReconciler) reportDrift(awsCluster *capa.AWSCluster, plan *dryrun.Plan) {
+	key := client.ObjectKeyFromObject(awsCluster)
+	labels := prometheus.Labels{"namespace": key.Namespace, "name": key.Name}
+
+	operations := plan.Operations()
+	driftChanges.With(labels).Set(float64(len(operations)))
+	if len(operations) == 0 {
+		return
+	}
+
+	driftDetected.With(labels).Inc()
+	r.recorder.Eventf(awsCluster, corev1.EventTypeWarning, DriftDetectedReason, "Drift found, %s", plan.Summary(maxOperationsInMessage))
+}
+
+// isDriftCheck checks if the reconciliation of the AWSCluster is a drift
+// check, i.e. the network was ready and it has been applied in the current
+// state, see LastAppliedGenerationAnnotation and appliedState. Without
+// auto-repair, every such reconciliation is a drift check, so that drift is
+// never repaired. Otherwise, it is one when the last check is at least
+// interval ago. Drift is not checked in dry-run mode, in which all changes are
+// planned anyway.
+func (r *AWSClusterReconciler) isDriftCheck(ctx context.Context, awsCluster *capa.AWSCluster, appliedState string, interval time.Duration, autoRepair bool) bool {
+	return interval > 0 &&
+		!dryrun.Enabled(ctx) &&
+		conditions.IsTrue(awsCluster, NetworkReady) &&
+		awsCluster.Annotations[LastAppliedGenerationAnnotation] == appliedState &&
+		(!autoRepair || r.driftChecks.Due(client.ObjectKeyFromObject(awsCluster), interval))
+}
+
+// appliedStateAnnotations are the annotations of the AWSCluster that change
+// how its network is reconciled.
+var appliedStateAnnotations = []string{
+	annotation.VPCEndpointModeAnnotation,
+	NetworkManagementAnnotation,
+}
+
+// appliedState returns the state of the AWSCluster and the operator in which
+// the network is reconciled, i.e. the generation of the AWSCluster and a hash
+// of the operator configuration, the operator build and the annotations that
+// change the reconciliation, e.g. "3/5f2a9c1d". A change of any of them is
+// applied like a change of the spec, also without auto-repair.
+func (r *AWSClusterReconciler) appliedState(awsCluster *capa.AWSCluster) (string, error) {
+	hash := fnv.New32a()
+	data, err := json.Marshal(r.config.Get())
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+	_, _ = hash.Write(data)
+	// the build changes with the operator version, whose behavior may differ
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		_, _ = hash.Write([]byte(buildInfo.String()))
+	}
+	for _, name := range appliedStateAnnotations {
+		value, ok := awsCluster.Annotations[name]
+		_, _ = fmt.Fprintf(hash, "\x00%s=%t:%s", name, ok, value)
+	}
+	return fmt.Sprintf("%d/%08x", awsCluster.Generation, hash.Sum32()), nil
+}
+
+// setLastAppliedState records the applied state of the AWSCluster in
+// LastAppliedGenerationAnnotation. It must be called only after the network
+// has been reconciled in that state, see appliedState.
+func setLastAppliedState(awsCluster *capa.AWSCluster, appliedState string) {
+	if awsCluster.Annotations == nil {
+		awsCluster.Annotations = map[string]string{}
+	}
+	awsCluster.Annotations[LastAppliedGenerationAnnotation] = appliedState
+}
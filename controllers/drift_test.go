@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+func Test_Reconcile_Drift(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		// change is applied to the network after it converged
+		change func(n *fakeNetwork)
+		// restart forgets the times of the last drift checks
+		restart bool
+		// specChanged increments the generation of the AWSCluster
+		specChanged bool
+		// changeConfig changes the operator configuration
+		changeConfig func(c *config.Config)
+		// changedAnnotations are set on the AWSCluster
+		changedAnnotations   map[string]string
+		expectedEventReasons []string
+		expectedAssociations int
+	}{
+		{
+			name:                 "case 0: no drift",
+			annotations:          map[string]string{DriftCheckIntervalAnnotation: "1ns"},
+			expectedAssociations: 1,
+		},
+		{
+			name:        "case 1: drift is reported",
+			annotations: map[string]string{DriftCheckIntervalAnnotation: "1ns"},
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			expectedEventReasons: []string{DriftDetectedReason},
+			expectedAssociations: 0,
+		},
+		{
+			name: "case 2: drift is repaired",
+			annotations: map[string]string{
+				DriftCheckIntervalAnnotation: "1ns",
+				DriftAutoRepairAnnotation:    "true",
+			},
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			expectedEventReasons: []string{DriftDetectedReason, RepairingDriftReason},
+			expectedAssociations: 1,
+		},
+		{
+			name:        "case 3: drift is reported before the interval without auto-repair",
+			annotations: map[string]string{DriftCheckIntervalAnnotation: "1h"},
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			expectedEventReasons: []string{DriftDetectedReason},
+			expectedAssociations: 0,
+		},
+		{
+			name: "case 4: drift is not checked before the interval with auto-repair",
+			annotations: map[string]string{
+				DriftCheckIntervalAnnotation: "1h",
+				DriftAutoRepairAnnotation:    "true",
+			},
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			// a reconciliation that is not a drift check changes the
+			// network as before
+			expectedAssociations: 1,
+		},
+		{
+			name: "case 5: drift is checked after a restart",
+			annotations: map[string]string{
+				DriftCheckIntervalAnnotation: "1h",
+				DriftAutoRepairAnnotation:    "true",
+			},
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			restart:              true,
+			expectedEventReasons: []string{DriftDetectedReason, RepairingDriftReason},
+			expectedAssociations: 1,
+		},
+		{
+			name:        "case 6: changed spec is applied without auto-repair",
+			annotations: map[string]string{DriftCheckIntervalAnnotation: "1h"},
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			specChanged:          true,
+			expectedAssociations: 1,
+		},
+		{
+			name:        "case 7: changed operator configuration is applied without auto-repair",
+			annotations: map[string]string{DriftCheckIntervalAnnotation: "1h"},
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			changeConfig: func(c *config.Config) {
+				c.Defaults.AvailabilityZoneCount++
+			},
+			expectedAssociations: 1,
+		},
+		{
+			name:        "case 8: changed annotation is applied without auto-repair",
+			annotations: map[string]string{DriftCheckIntervalAnnotation: "1h"},
+			change: func(n *fakeNetwork) {
+				delete(n.associations, "rtb-b")
+			},
+			changedAnnotations:   map[string]string{annotation.VPCEndpointModeAnnotation: annotation.VPCEndpointModeUserManaged},
+			expectedAssociations: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newNetworkTestEnv(t, tc.annotations)
+
+			// the first reconciliation converges the network and makes it
+			// ready
+			_, err := env.reconciler.Reconcile(ctx, env.request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for len(env.recorder.Events) > 0 {
+				<-env.recorder.Events
+			}
+			if tc.change != nil {
+				tc.change(env.network)
+			}
+			if tc.restart {
+				env.reconciler.driftChecks = newDriftChecks()
+			}
+			if tc.changeConfig != nil {
+				operatorConfig := *env.reconciler.config.Get()
+				tc.changeConfig(&operatorConfig)
+				env.reconciler.config = config.NewStore(&operatorConfig)
+			}
+			if tc.specChanged || tc.changedAnnotations != nil {
+				awsCluster := &capa.AWSCluster{}
+				err = env.k8sClient.Get(ctx, env.request.NamespacedName, awsCluster)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if tc.specChanged {
+					awsCluster.Generation++
+				}
+				for name, value := range tc.changedAnnotations {
+					awsCluster.Annotations[name] = value
+				}
+				err = env.k8sClient.Update(ctx, awsCluster)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			result, err := env.reconciler.Reconcile(ctx, env.request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.RequeueAfter <= 0 {
+				t.Errorf("expected next drift check to be scheduled, got %v", result)
+			}
+
+			var eventReasons []string
+			for len(env.recorder.Events) > 0 {
+				// events are formatted as "<type> <reason> <message>"
+				eventReasons = append(eventReasons, strings.Fields(<-env.recorder.Events)[1])
+			}
+			if strings.Join(eventReasons, ",") != strings.Join(tc.expectedEventReasons, ",") {
+				t.Errorf("expected events %v, got %v", tc.expectedEventReasons, eventReasons)
+			}
+
+			associations := 0
+			for _, subnetIds := range env.network.associations {
+				for _, subnetId := range subnetIds {
+					if subnetId == "subnet-b" {
+						associations++
+					}
+				}
+			}
+			if associations != tc.expectedAssociations {
+				t.Errorf("expected %d route table associations of subnet-b, got %d", tc.expectedAssociations, associations)
+			}
+		})
+	}
+}
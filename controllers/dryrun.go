@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+)
+
+const (
+	// NoChangesPlanned is set in dry-run mode. It is true when the
+	// reconciliation would not change any AWS resources, otherwise its
+	// message lists the planned changes.
+	NoChangesPlanned capi.ConditionType = "NoChangesPlanned"
+
+	ChangesPlannedReason = "ChangesPlanned"
+
+	// maxOperationsInMessage limits the length of the condition and event
+	// messages when many changes are planned. All of them are served on the
+	// dryrun.PlansPath endpoint.
+	maxOperationsInMessage = 10
+)
+
+// reportPlan reports the changes that a reconciliation in dry-run mode would
+// make, in the NoChangesPlanned condition, in an event and on the
+// dryrun.PlansPath endpoint.
+func (r *AWSClusterReconciler) reportPlan(awsCluster *capa.AWSCluster, plan *dryrun.Plan) {
+	r.plans.Set(client.ObjectKeyFromObject(awsCluster), plan)
+
+	if len(plan.Operations()) == 0 {
+		conditions.MarkTrue(awsCluster, NoChangesPlanned)
+		return
+	}
+
+	summary := plan.Summary(maxOperationsInMessage)
+	conditions.MarkFalse(awsCluster, NoChangesPlanned, ChangesPlannedReason, capi.ConditionSeverityInfo, "%s", summary)
+	r.recorder.Event(awsCluster, corev1.EventTypeNormal, ChangesPlannedReason, summary)
+}
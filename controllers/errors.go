@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// errorHandling specifies how a reconciliation error of a specific class is
+// reported in a condition and when the reconciliation is retried.
+type errorHandling struct {
+	// Reason set in the condition of the failed resource.
+	Reason string
+
+	// Severity set in the condition of the failed resource.
+	Severity capi.ConditionSeverity
+
+	// RequeueAfter is the time after which the reconciliation is retried.
+	// When it is zero, the error is returned, so that the reconciliation is
+	// retried with controller-runtime's exponential backoff.
+	RequeueAfter time.Duration
+}
+
+var errorHandlingByClass = map[errors.Class]errorHandling{
+	errors.ClassTransient: {
+		// transient errors should go away quickly, so we just retry with
+		// exponential backoff
+		Reason:   "AWSTransientError",
+		Severity: capi.ConditionSeverityInfo,
+	},
+	errors.ClassThrottled: {
+		// retrying immediately would only make throttling worse
+		Reason:       "AWSRequestThrottled",
+		Severity:     capi.ConditionSeverityWarning,
+		RequeueAfter: 2 * time.Minute,
+	},
+	errors.ClassQuotaExceeded: {
+		// someone has to increase the quota or delete resources, which will
+		// take a while
+		Reason:       "AWSQuotaExceeded",
+		Severity:     capi.ConditionSeverityError,
+		RequeueAfter: 15 * time.Minute,
+	},
+	errors.ClassPermission: {
+		// someone has to fix the IAM role, which will take a while
+		Reason:       "AWSPermissionDenied",
+		Severity:     capi.ConditionSeverityError,
+		RequeueAfter: 10 * time.Minute,
+	},
+	errors.ClassNotFound: {
+		// resource was probably deleted in the meantime, the next
+		// reconciliation should see the new state
+		Reason:   "AWSResourceNotFound",
+		Severity: capi.ConditionSeverityWarning,
+	},
+	errors.ClassDependency: {
+		// other resources should be deleted first, e.g. by CAPA or other
+		// controllers, so we wait a bit
+		Reason:       "AWSDependencyViolation",
+		Severity:     capi.ConditionSeverityWarning,
+		RequeueAfter: time.Minute,
+	},
+	errors.ClassConflict: {
+		// clients retry with another client token, so this should only
+		// happen when all attempts conflicted, which needs a closer look
+		Reason:       "AWSRequestConflict",
+		Severity:     capi.ConditionSeverityError,
+		RequeueAfter: 10 * time.Minute,
+	},
+	errors.ClassUnknown: {
+		Reason:   "ReconciliationError",
+		Severity: capi.ConditionSeverityError,
+	},
+}
+
+// handleError classifies the reconciliation error, sets the specified
+// condition with the reason for the error class, and decides how the
+// reconciliation is retried.
+func handleError(ctx context.Context, awsCluster *capa.AWSCluster, condition capi.ConditionType, err error) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	class := errors.Classify(err)
+	handling := errorHandlingByClass[class]
+
+	conditions.MarkFalse(awsCluster, condition, handling.Reason, handling.Severity, "%s", err.Error())
+
+	if handling.RequeueAfter == 0 {
+		return ctrl.Result{}, microerror.Mask(err)
+	}
+
+	logger.Error(err, "Reconciliation failed, retrying later", "error-class", class, "requeue-after", handling.RequeueAfter)
+	return ctrl.Result{RequeueAfter: handling.RequeueAfter}, nil
+}
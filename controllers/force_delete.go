@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	IdentityUnavailableReason = "IdentityUnavailable"
+	DeletionForcedReason      = "DeletionForced"
+	ResourcesOrphanedEvent    = "ResourcesOrphaned"
+)
+
+// orphanResources removes our finalizer from the deleted AWSCluster without
+// deleting any AWS resources, see OrphanResourcesOnDeleteAnnotation. In
+// dry-run mode the AWSCluster is not changed.
+func (r *AWSClusterReconciler) orphanResources(ctx context.Context, awsCluster *capa.AWSCluster) error {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(awsCluster, AwsVpcOperatorFinalizer) {
+		return nil
+	}
+	if r.config.Get().DryRun {
+		logger.Info("Skipped removing finalizer without deleting AWS resources in dry-run mode", "annotation", OrphanResourcesOnDeleteAnnotation)
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(awsCluster, r.Client)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	controllerutil.RemoveFinalizer(awsCluster, AwsVpcOperatorFinalizer)
+	err = patchHelper.Patch(ctx, awsCluster)
+	if apierrors.IsNotFound(err) {
+		// AWSCluster is deleted as soon as the last finalizer is removed
+		err = nil
+	}
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	logger.Info("Removed finalizer without deleting AWS resources", "annotation", OrphanResourcesOnDeleteAnnotation)
+	r.recorder.Eventf(awsCluster, corev1.EventTypeWarning, ResourcesOrphanedEvent,
+		"Removed finalizer without deleting AWS resources, because annotation %s is set. VPC, subnets, route tables and VPC endpoints of the cluster are left in AWS", OrphanResourcesOnDeleteAnnotation)
+	return nil
+}
+
+// reportIdentityUnavailable is called when the deleted AWSCluster cannot be
+// reconciled, because its AWSClusterRoleIdentity is not set or not found. It
+// explains in the DeletionBlocked condition how to unblock deletion.
+//
+// Errors are only logged, as the original identity error is the one that is
+// handled by the caller.
+func (r *AWSClusterReconciler) reportIdentityUnavailable(ctx context.Context, awsCluster *capa.AWSCluster, identityErr error) {
+	logger := log.FromContext(ctx)
+
+	patchHelper, err := patch.NewHelper(awsCluster, r.Client)
+	if err != nil {
+		logger.Error(err, "Failed to report unavailable identity")
+		return
+	}
+
+	conditions.Set(awsCluster, &capi.Condition{
+		Type:     DeletionBlocked,
+		Status:   corev1.ConditionTrue,
+		Severity: capi.ConditionSeverityError,
+		Reason:   IdentityUnavailableReason,
+		Message: fmt.Sprintf("AWS resources cannot be deleted without the AWSClusterRoleIdentity: %s. Restore the identity, or set annotation %s to \"true\" to remove the finalizer and leave the resources in AWS",
+			identityErr.Error(), OrphanResourcesOnDeleteAnnotation),
+	})
+	err = patchHelper.Patch(ctx, awsCluster, patch.WithOwnedConditions{Conditions: []capi.ConditionType{DeletionBlocked}})
+	if err != nil {
+		logger.Error(err, "Failed to report unavailable identity")
+	}
+}
+
+// isDeletionForced checks if deletion of the AWSCluster continues after
+// failures, see ForceDeleteAnnotation.
+func isDeletionForced(awsCluster *capa.AWSCluster) bool {
+	return isAnnotationTrue(awsCluster.Annotations, ForceDeleteAnnotation)
+}
+
+// skipFailedDeletion reports that deletion of the resources of the specified
+// condition failed and that deletion continues, because it is forced.
+func (r *AWSClusterReconciler) skipFailedDeletion(ctx context.Context, awsCluster *capa.AWSCluster, condition capi.ConditionType, err error) {
+	log.FromContext(ctx).Error(err, "Deletion failed, continuing because deletion is forced", "condition", condition)
+
+	conditions.MarkFalse(awsCluster, condition, DeletionForcedReason, capi.ConditionSeverityWarning, "Deletion failed and was skipped: %s", err.Error())
+	r.recorder.Eventf(awsCluster, corev1.EventTypeWarning, DeletionForcedReason,
+		"Deletion of resources of condition %s failed, continuing because annotation %s is set: %s", condition, ForceDeleteAnnotation, err.Error())
+}
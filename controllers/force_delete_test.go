@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+func Test_Reconcile_StuckDeletion(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		identityExists        bool
+		expectedFinalizer     bool
+		expectedBlockedReason string
+		expectedEventReason   string
+		expectedNoAWSCalls    bool
+	}{
+		{
+			name:                  "missing identity blocks deletion",
+			expectedFinalizer:     true,
+			expectedBlockedReason: IdentityUnavailableReason,
+			expectedNoAWSCalls:    true,
+		},
+		{
+			name:                "orphan annotation removes finalizer without AWS calls",
+			annotations:         map[string]string{OrphanResourcesOnDeleteAnnotation: "true"},
+			expectedEventReason: ResourcesOrphanedEvent,
+			expectedNoAWSCalls:  true,
+		},
+		{
+			name:              "failed deletion keeps finalizer",
+			identityExists:    true,
+			expectedFinalizer: true,
+		},
+		{
+			name:                "force-delete annotation removes finalizer after failed deletion",
+			annotations:         map[string]string{ForceDeleteAnnotation: "true"},
+			identityExists:      true,
+			expectedEventReason: DeletionForcedReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			awsCluster := testAWSCluster("test", "test", map[string]string{annotation.AWSVPCMode: annotation.AWSVPCModePrivate})
+			for k, v := range tc.annotations {
+				awsCluster.Annotations[k] = v
+			}
+			awsCluster.Finalizers = []string{AwsVpcOperatorFinalizer}
+			awsCluster.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+			awsCluster.Spec.Region = awstest.Region
+			awsCluster.Spec.NetworkSpec.VPC.ID = "vpc-1"
+
+			objects := []client.Object{awsCluster}
+			if tc.identityExists {
+				identity := &capa.AWSClusterRoleIdentity{}
+				// the fake client treats all objects as namespaced
+				identity.Namespace = awsCluster.Namespace
+				identity.Name = "test"
+				identity.Spec.RoleArn = awstest.RoleARN
+				objects = append(objects, identity)
+			}
+			k8sClient := fake.NewClientBuilder().
+				WithScheme(testScheme(t)).
+				WithObjects(objects...).
+				WithStatusSubresource(&capa.AWSCluster{}).
+				Build()
+
+			operatorConfig, err := config.Parse(nil, config.Default())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// no handlers, so that all AWS calls fail like with a revoked role
+			fakeEC2 := awstest.NewEC2()
+			recorder := record.NewFakeRecorder(100)
+			r, err := NewAWSClusterReconciler(k8sClient, testScheme(t), recorder, fakeEC2.Client(), awstest.AssumeRoleClient{}, awstest.Limits{}, config.NewStore(operatorConfig), dryrun.NewPlans())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, _ = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(awsCluster)})
+
+			reconciled := &capa.AWSCluster{}
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(awsCluster), reconciled)
+			if apierrors.IsNotFound(err) {
+				// deleted after the last finalizer was removed
+				reconciled = nil
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			hasFinalizer := reconciled != nil && controllerutil.ContainsFinalizer(reconciled, AwsVpcOperatorFinalizer)
+			if hasFinalizer != tc.expectedFinalizer {
+				t.Errorf("expected finalizer %t, got %t", tc.expectedFinalizer, hasFinalizer)
+			}
+			if tc.expectedBlockedReason != "" {
+				if reconciled == nil || !conditions.IsTrue(reconciled, DeletionBlocked) || conditions.GetReason(reconciled, DeletionBlocked) != tc.expectedBlockedReason {
+					t.Errorf("expected DeletionBlocked condition with reason %s", tc.expectedBlockedReason)
+				} else if message := conditions.GetMessage(reconciled, DeletionBlocked); !strings.Contains(message, OrphanResourcesOnDeleteAnnotation) {
+					t.Errorf("expected DeletionBlocked message to explain how to unblock deletion, got %q", message)
+				}
+			}
+			if tc.expectedNoAWSCalls && len(fakeEC2.AllCalls()) > 0 {
+				t.Errorf("expected no AWS calls, got %v", fakeEC2.AllCalls())
+			}
+
+			if tc.expectedEventReason != "" {
+				found := false
+				for len(recorder.Events) > 0 {
+					event := <-recorder.Events
+					if strings.HasPrefix(event, "Warning "+tc.expectedEventReason+" ") {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("expected Warning event with reason %s", tc.expectedEventReason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// ResourceNotOwnedReason is set when AWS resources have not been created
+	// by aws-vpc-operator for the cluster, so they are not changed or deleted.
+	ResourceNotOwnedReason = "ResourceNotOwned"
+
+	// UnmanagedNetworkReason is set when AWS resources are not reconciled,
+	// because they are marked as unmanaged with NetworkManagementAnnotation.
+	UnmanagedNetworkReason = "UnmanagedNetwork"
+)
+
+// reportNotOwned reports that deletion of resources that are not owned by the
+// cluster has been skipped, in the specified condition and in an event.
+func (r *AWSClusterReconciler) reportNotOwned(ctx context.Context, awsCluster *capa.AWSCluster, condition capi.ConditionType, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Skipped deleting resources that are not owned by the cluster", "condition", condition, "reason", err.Error())
+
+	conditions.MarkFalse(awsCluster, condition, ResourceNotOwnedReason, capi.ConditionSeverityWarning, "Deletion skipped: %s", err.Error())
+	r.recorder.Eventf(awsCluster, corev1.EventTypeWarning, ResourceNotOwnedReason, "Deletion skipped: %s", err.Error())
+}
+
+// skipUnmanagedDeletion marks the specified condition when deletion of
+// unmanaged resources is skipped.
+func skipUnmanagedDeletion(ctx context.Context, awsCluster *capa.AWSCluster, condition capi.ConditionType) {
+	log.FromContext(ctx).Info("Skipped deleting unmanaged resources", "condition", condition)
+	conditions.MarkFalse(awsCluster, condition, UnmanagedNetworkReason, capi.ConditionSeverityInfo, "Deletion skipped, network is unmanaged")
+}
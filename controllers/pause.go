@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+)
+
+// isPaused checks if the reconciliation of the AWSCluster is paused, either
+// with the cluster.x-k8s.io/paused annotation on the AWSCluster, or with
+// Spec.Paused of the owner Cluster, e.g. during clusterctl move.
+func (r *AWSClusterReconciler) isPaused(ctx context.Context, awsCluster *capa.AWSCluster) (bool, error) {
+	if annotations.HasPaused(awsCluster) {
+		return true, nil
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, awsCluster.ObjectMeta)
+	if apierrors.IsNotFound(err) {
+		// the Cluster can already be gone when the AWSCluster is deleted
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+	if cluster == nil {
+		// owner reference is not set yet
+		return false, nil
+	}
+
+	return annotations.IsPaused(cluster, awsCluster), nil
+}
@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/requeue"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
+)
+
+const (
+	// NetworkReady summarizes the conditions of all reconciliation phases. It
+	// is true when all the network resources are ready.
+	NetworkReady capi.ConditionType = "NetworkReady"
+)
+
+// phase is a step in the reconciliation of an AWSCluster, which reconciles
+// one kind of AWS resources, e.g. subnets. Phases run in order, and a phase
+// runs only after all previous phases are done.
+type phase struct {
+	// Name of the phase, used in logs and traces.
+	Name string
+
+	// Condition that reports the state of the resources reconciled in the
+	// phase.
+	Condition capi.ConditionType
+
+	// Prerequisites are conditions that must be true before the phase runs,
+	// e.g. conditions set by CAPA.
+	Prerequisites []capi.ConditionType
+
+	// ManagedOnly is set for phases that create and change resources, which
+	// must not happen for unmanaged networks, see NetworkManagementAnnotation.
+	ManagedOnly bool
+
+	// Backoff is the requeue schedule used while the phase is waiting for
+	// its prerequisites or its resources. When nil, the requeue schedule of
+	// the operator is used. RequeueScheduleAnnotation overrides both.
+	Backoff *requeue.Schedule
+
+	// Reconcile reconciles the resources of the phase and updates AWSCluster
+	// spec with their current state.
+	Reconcile func(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string) (phaseResult, error)
+}
+
+// phaseResult is the outcome of a phase that did not fail.
+type phaseResult struct {
+	// Ready is true when all resources reconciled in the phase are ready.
+	Ready bool
+
+	// Reason and Message are set in the phase condition when the resources
+	// are not ready. When they are ready, Reason and Message are optional,
+	// e.g. to tell that the phase was skipped.
+	Reason  string
+	Message string
+
+	// Proceed allows the following phases to run, although the resources of
+	// this phase are not ready yet, e.g. subnets are waiting for route tables
+	// that are created in the next phase.
+	Proceed bool
+}
+
+// preflightBackoff is the requeue schedule of the preflight phases, whose
+// checks fail until the IAM policy or the service quotas are changed by a
+// user, and whose results are cached for 10 minutes anyway.
+var preflightBackoff = requeue.Schedule{
+	Steps: []requeue.Step{
+		{After: 0, RequeueAfter: 5 * time.Minute, Severity: capi.ConditionSeverityWarning},
+		{After: time.Hour, RequeueAfter: 15 * time.Minute, Severity: capi.ConditionSeverityError},
+	},
+}
+
+// phases returns all AWSCluster reconciliation phases in the order in which
+// they run.
+func (r *AWSClusterReconciler) phases() []phase {
+	return []phase{
+		{
+			Name:      "permissions",
+			Condition: PermissionsReady,
+			Backoff:   &preflightBackoff,
+			Reconcile: r.reconcilePermissionsPhase,
+		},
+		{
+			Name:          "quotas",
+			Condition:     QuotasReady,
+			Prerequisites: []capi.ConditionType{PermissionsReady},
+			Backoff:       &preflightBackoff,
+			Reconcile:     r.reconcileQuotasPhase,
+		},
+		{
+			Name:          "vpc",
+			Condition:     capa.VpcReadyCondition,
+			Prerequisites: []capi.ConditionType{PermissionsReady, QuotasReady},
+			Reconcile:     r.reconcileVpcPhase,
+		},
+		{
+			Name:          "subnets",
+			Condition:     capa.SubnetsReadyCondition,
+			Prerequisites: []capi.ConditionType{capa.VpcReadyCondition},
+			ManagedOnly:   true,
+			Reconcile:     r.reconcileSubnetsPhase,
+		},
+		{
+			Name:          "routetables",
+			Condition:     capa.RouteTablesReadyCondition,
+			Prerequisites: []capi.ConditionType{capa.VpcReadyCondition},
+			ManagedOnly:   true,
+			Reconcile:     r.reconcileRouteTablesPhase,
+		},
+		{
+			Name:      "endpoints",
+			Condition: VpcEndpointReady,
+			Prerequisites: []capi.ConditionType{
+				capa.VpcReadyCondition,
+				capa.RouteTablesReadyCondition,
+				capa.ClusterSecurityGroupsReadyCondition,
+			},
+			Reconcile: r.reconcileEndpointsPhase,
+		},
+	}
+}
+
+// runPhases runs the specified phases in order. It stops at the first phase
+// that failed, or that is waiting for its prerequisites or its resources, and
+// requeues the reconciliation according to the requeue schedule of the phase.
+func (r *AWSClusterReconciler) runPhases(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string, phases []phase) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	for _, p := range phases {
+		if p.ManagedOnly && isUnmanagedNetwork(awsCluster) {
+			// unmanaged resources are maintained by the user, so they are
+			// not checked, the following phases just assume that they are
+			// ready, and the reason tells that they were not checked
+			logger.Info("Skipped phase for unmanaged network", "phase", p.Name)
+			conditions.Set(awsCluster, &capi.Condition{
+				Type:    p.Condition,
+				Status:  corev1.ConditionTrue,
+				Reason:  UnmanagedNetworkReason,
+				Message: "Not reconciled, the network is managed by the user",
+			})
+			continue
+		}
+
+		schedule := r.phaseSchedule(ctx, awsCluster, p)
+
+		var unmetPrerequisite capi.ConditionType
+		for _, prerequisite := range p.Prerequisites {
+			if !conditions.IsTrue(awsCluster, prerequisite) {
+				unmetPrerequisite = prerequisite
+				break
+			}
+		}
+		if unmetPrerequisite != "" {
+			reason := strings.TrimSuffix(string(unmetPrerequisite), "Ready") + "NotReady"
+			message := fmt.Sprintf("Waiting for %s condition to be true", unmetPrerequisite)
+			decision := schedule.ForCondition(awsCluster, p.Condition)
+			conditions.MarkFalse(awsCluster, p.Condition, reason, decision.Severity, "%s%s", message, decision.MessageSuffix)
+			logger.Info("Phase is waiting for its prerequisites", "phase", p.Name, "prerequisite", unmetPrerequisite, "requeue-after", decision.RequeueAfter)
+			return ctrl.Result{RequeueAfter: decision.RequeueAfter}, nil
+		}
+
+		result, err := r.runPhase(ctx, awsCluster, roleArn, p)
+		if err != nil {
+			return handleError(ctx, awsCluster, p.Condition, err)
+		}
+
+		if result.Ready && result.Reason != "" {
+			conditions.Set(awsCluster, &capi.Condition{
+				Type:    p.Condition,
+				Status:  corev1.ConditionTrue,
+				Reason:  result.Reason,
+				Message: result.Message,
+			})
+			continue
+		} else if result.Ready {
+			conditions.MarkTrue(awsCluster, p.Condition)
+			continue
+		}
+
+		decision := schedule.ForCondition(awsCluster, p.Condition)
+		conditions.MarkFalse(awsCluster, p.Condition, result.Reason, decision.Severity, "%s%s", result.Message, decision.MessageSuffix)
+		if result.Proceed {
+			logger.Info("Phase resources are not ready yet, proceeding with the next phase", "phase", p.Name, "reason", result.Reason)
+			continue
+		}
+
+		logger.Info("Phase resources are not ready yet", "phase", p.Name, "reason", result.Reason, "requeue-after", decision.RequeueAfter)
+		return ctrl.Result{RequeueAfter: decision.RequeueAfter}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AWSClusterReconciler) runPhase(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string, p phase) (result phaseResult, err error) {
+	ctx, span := tracing.Start(ctx, "phase."+p.Name)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	return p.Reconcile(ctx, awsCluster, roleArn)
+}
+
+// phaseConditions returns conditions of the specified phases.
+func phaseConditions(phases []phase) []capi.ConditionType {
+	var phaseConditions []capi.ConditionType
+	for _, p := range phases {
+		phaseConditions = append(phaseConditions, p.Condition)
+	}
+	return phaseConditions
+}
+
+// allPhasesReady checks if the conditions of all phases are true.
+func allPhasesReady(awsCluster *capa.AWSCluster, phases []phase) bool {
+	for _, p := range phases {
+		if !conditions.IsTrue(awsCluster, p.Condition) {
+			return false
+		}
+	}
+	return true
+}
+
+// setNetworkReadyCondition sets the NetworkReady condition to true when the
+// conditions of all phases are true. Otherwise, it is set to false with the
+// reason, severity and message of the first phase that is not ready.
+func setNetworkReadyCondition(awsCluster *capa.AWSCluster, phases []phase) {
+	for i, p := range phases {
+		if conditions.IsTrue(awsCluster, p.Condition) {
+			continue
+		}
+
+		reason := conditions.GetReason(awsCluster, p.Condition)
+		severity := capi.ConditionSeverityInfo
+		if s := conditions.GetSeverity(awsCluster, p.Condition); s != nil {
+			severity = *s
+		}
+		message := conditions.GetMessage(awsCluster, p.Condition)
+		if reason == "" {
+			reason = "WaitingForPhase"
+			message = fmt.Sprintf("Phase %s has not run yet", p.Name)
+		}
+		conditions.MarkFalse(awsCluster, NetworkReady, reason, severity, "%d of %d completed: %s", i, len(phases), message)
+		return
+	}
+
+	conditions.MarkTrue(awsCluster, NetworkReady)
+}
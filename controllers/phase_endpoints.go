@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/types"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/routetables"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/subnets"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+)
+
+func (r *AWSClusterReconciler) reconcileEndpointsPhase(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string) (phaseResult, error) {
+	logger := log.FromContext(ctx)
+	operatorConfig := r.config.Get()
+
+	cluster := &capi.Cluster{}
+	clusterKey := types.NamespacedName{
+		Namespace: awsCluster.Namespace,
+		Name:      awsCluster.Name,
+	}
+	err := r.Get(ctx, clusterKey, cluster)
+	if err != nil {
+		return phaseResult{}, microerror.Mask(err)
+	}
+
+	reconcileRequest := aws.ReconcileRequest[vpcendpoint.Spec]{
+		Resource:    awsCluster,
+		ClusterName: awsCluster.Name,
+		ClusterUID:  string(awsCluster.UID),
+		CloudResourceRequest: aws.CloudResourceRequest[vpcendpoint.Spec]{
+			RoleARN: roleArn,
+			Region:  awsCluster.Spec.Region,
+			Spec: vpcendpoint.Spec{
+				VpcId:        awsCluster.Spec.NetworkSpec.VPC.ID,
+				Services:     operatorConfig.VpcEndpoints.GatewayServices,
+				NameTemplate: operatorConfig.Naming.VpcEndpoint,
+			},
+			AdditionalTags:     awsCluster.Spec.AdditionalTags,
+			LastAppliedTagKeys: lastAppliedTagKeys(ctx, awsCluster),
+		},
+	}
+
+	subnetIDs, err := r.subnetsClient.GetEndpointSubnets(ctx, subnets.GetEndpointSubnetsInput{
+		ClusterName:       awsCluster.Name,
+		RoleARN:           roleArn,
+		Region:            awsCluster.Spec.Region,
+		VpcId:             awsCluster.Spec.NetworkSpec.VPC.ID,
+		EndpointSubnetTag: operatorConfig.Tags.EndpointSubnet,
+	})
+	if err != nil {
+		logger.Error(err, "Failed to lookup subnets")
+		return phaseResult{}, microerror.Mask(err)
+	}
+	// If no specific subnets found we'll fallback to picking any from the cluster
+	if len(subnetIDs) == 0 {
+		logger.Info("No specific subnets found for VPC endpoints, falling back to using subnets from AWSCluster spec")
+		selectedAZs := map[string]bool{}
+		for _, subnet := range awsCluster.Spec.NetworkSpec.Subnets {
+			if selectedAZs[subnet.AvailabilityZone] {
+				// VPC endpoints can only have a single subnet per AZ so we'll skip any additional
+				continue
+			}
+			subnetIDs = append(subnetIDs, subnet.ID)
+			selectedAZs[subnet.AvailabilityZone] = true
+		}
+	}
+	reconcileRequest.Spec.SubnetIds = subnetIDs
+
+	for _, securityGroup := range awsCluster.Status.Network.SecurityGroups {
+		reconcileRequest.Spec.SecurityGroupIds = append(reconcileRequest.Spec.SecurityGroupIds, securityGroup.ID)
+	}
+
+	routeTablesListOutput, err := r.routeTablesClient.List(ctx, routetables.ListRouteTablesInput{
+		Region:  awsCluster.Spec.Region,
+		RoleARN: roleArn,
+		VpcId:   awsCluster.Spec.NetworkSpec.VPC.ID,
+	})
+	if err != nil {
+		logger.Error(err, "Failed to lookup route tables")
+		return phaseResult{}, microerror.Mask(err)
+	}
+
+	for _, rt := range routeTablesListOutput {
+		reconcileRequest.Spec.RouteTableIds = append(reconcileRequest.Spec.RouteTableIds, rt.RouteTableId)
+	}
+
+	result, err := r.vpcEndpointReconciler.Reconcile(ctx, reconcileRequest)
+	if err != nil {
+		return phaseResult{}, microerror.Mask(err)
+	}
+
+	for _, status := range result.Status {
+		if !strings.EqualFold(status.VpcEndpointState, vpcendpoint.StateAvailable) {
+			return phaseResult{
+				Reason:  fmt.Sprintf("VpcEndpointState%s", status.VpcEndpointState),
+				Message: fmt.Sprintf("VPC endpoint for service %s is not available", status.Service),
+			}, nil
+		}
+	}
+
+	return phaseResult{Ready: true}, nil
+}
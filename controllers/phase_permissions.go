@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/permissions"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+const (
+	// PermissionsReady is true when the IAM role of the AWSCluster is allowed
+	// to make all EC2 API calls that aws-vpc-operator needs. Otherwise, its
+	// message lists the missing actions.
+	PermissionsReady capi.ConditionType = "PermissionsReady"
+
+	MissingPermissionsReason = "MissingPermissions"
+)
+
+// reconcilePermissionsPhase checks the IAM permissions before any resources
+// are created, so that missing permissions are not found in the middle of the
+// reconciliation, when only some of the resources exist.
+func (r *AWSClusterReconciler) reconcilePermissionsPhase(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string) (phaseResult, error) {
+	if !r.config.Get().Enabled(config.PermissionsPreflight) {
+		return phaseResult{Ready: true}, nil
+	}
+
+	actions := permissions.ManagedNetworkActions
+	if isUnmanagedNetwork(awsCluster) {
+		actions = permissions.UnmanagedNetworkActions
+	}
+	output, err := r.permissionsClient.Check(ctx, permissions.CheckInput{
+		RoleARN: roleArn,
+		Region:  awsCluster.Spec.Region,
+		VpcId:   awsCluster.Spec.NetworkSpec.VPC.ID,
+		Actions: actions,
+	})
+	if err != nil {
+		return phaseResult{}, microerror.Mask(err)
+	}
+
+	if len(output.Missing) > 0 {
+		missing := make([]string, 0, len(output.Missing))
+		for _, action := range output.Missing {
+			missing = append(missing, string(action))
+		}
+		return phaseResult{
+			Reason:  MissingPermissionsReason,
+			Message: fmt.Sprintf("Role %s is not allowed to call %s", roleArn, strings.Join(missing, ", ")),
+		}, nil
+	}
+
+	return phaseResult{Ready: true}, nil
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/quotas"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	// QuotasReady is true when the service quotas leave enough headroom for
+	// the VPC, gateway VPC endpoints and Elastic IPs that will be created for
+	// the AWSCluster. Otherwise, its message lists the exceeded quotas.
+	QuotasReady capi.ConditionType = "QuotasReady"
+
+	QuotaExceededReason = "QuotaExceeded"
+
+	// QuotasNotCheckedReason is set in the true QuotasReady condition when
+	// the role of the AWSCluster is not allowed to get the service quotas.
+	// The check is advisory, so it does not block the following phases.
+	QuotasNotCheckedReason = "QuotasNotChecked"
+)
+
+// reconcileQuotasPhase checks the service quotas before any resources are
+// created, so that clusters do not fail halfway with VpcLimitExceeded or
+// similar errors, when only some of the resources exist.
+func (r *AWSClusterReconciler) reconcileQuotasPhase(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string) (phaseResult, error) {
+	operatorConfig := r.config.Get()
+	if !operatorConfig.Enabled(config.QuotaPreflight) {
+		return phaseResult{Ready: true}, nil
+	}
+
+	input := quotas.CheckInput{
+		RoleARN: roleArn,
+		Region:  awsCluster.Spec.Region,
+		VpcId:   awsCluster.Spec.NetworkSpec.VPC.ID,
+	}
+	if !isUnmanagedNetwork(awsCluster) {
+		if awsCluster.Spec.NetworkSpec.VPC.ID == "" {
+			input.Vpcs = 1
+		}
+		// CAPA allocates an Elastic IP for the NAT gateway of each public
+		// subnet
+		for _, subnet := range awsCluster.Spec.NetworkSpec.Subnets {
+			if subnet.IsPublic && subnet.ID == "" {
+				input.ElasticIPs++
+			}
+		}
+	}
+	// gateway VPC endpoints are not checked again once they are ready
+	if awsCluster.Annotations[annotation.VPCEndpointModeAnnotation] != annotation.VPCEndpointModeUserManaged && !conditions.IsTrue(awsCluster, VpcEndpointReady) {
+		services := operatorConfig.VpcEndpoints.GatewayServices
+		if len(services) == 0 {
+			services = vpcendpoint.DefaultServices
+		}
+		for _, service := range services {
+			input.GatewayVpcEndpointServices = append(input.GatewayVpcEndpointServices, vpcendpoint.ServiceName(awsCluster.Spec.Region, service))
+		}
+	}
+
+	if input.Vpcs == 0 && input.ElasticIPs == 0 && len(input.GatewayVpcEndpointServices) == 0 {
+		return phaseResult{Ready: true}, nil
+	}
+
+	output, err := r.quotasClient.Check(ctx, input)
+	if errors.IsPermissionDenied(err) {
+		// roles created before the check was added cannot get the service
+		// quotas, which must not block their clusters
+		if conditions.GetReason(awsCluster, QuotasReady) != QuotasNotCheckedReason {
+			r.recorder.Eventf(awsCluster, corev1.EventTypeWarning, QuotasNotCheckedReason, "Service quotas are not checked, the role is not allowed to get them")
+		}
+		return phaseResult{
+			Ready:   true,
+			Reason:  QuotasNotCheckedReason,
+			Message: fmt.Sprintf("Service quotas are not checked, the role is not allowed to get them: %s", err),
+		}, nil
+	} else if err != nil {
+		return phaseResult{}, microerror.Mask(err)
+	}
+
+	if len(output.Exceeded) > 0 {
+		messages := make([]string, 0, len(output.Exceeded))
+		for _, exceeded := range output.Exceeded {
+			messages = append(messages, fmt.Sprintf("%s is %d, %d are used and %d more are needed", exceeded.Quota, exceeded.Limit, exceeded.Usage, exceeded.Required))
+		}
+		return phaseResult{
+			Reason:  QuotaExceededReason,
+			Message: fmt.Sprintf("Service quota is exceeded: %s", strings.Join(messages, "; ")),
+		}, nil
+	}
+
+	return phaseResult{Ready: true}, nil
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/quotas"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+func Test_reconcileQuotasPhase(t *testing.T) {
+	testCases := []struct {
+		name               string
+		vpcId              string
+		endpointsReady     bool
+		featureGates       map[config.Feature]bool
+		accessDenied       bool
+		expectedReady      bool
+		expectedReason     string
+		expectedMessage    string
+		expectedNoEC2Calls bool
+	}{
+		{
+			name:            "VPC quota is reached for a new cluster",
+			expectedReason:  QuotaExceededReason,
+			expectedMessage: "VPCs per Region is 5, 5 are used and 1 more are needed",
+		},
+		{
+			name:            "role is not allowed to get service quotas",
+			accessDenied:    true,
+			expectedReady:   true,
+			expectedReason:  QuotasNotCheckedReason,
+			expectedMessage: "Service quotas are not checked",
+		},
+		{
+			name:          "existing VPC needs no headroom",
+			vpcId:         "vpc-1",
+			expectedReady: true,
+		},
+		{
+			name:               "nothing is created",
+			vpcId:              "vpc-1",
+			endpointsReady:     true,
+			expectedReady:      true,
+			expectedNoEC2Calls: true,
+		},
+		{
+			name:               "disabled feature gate",
+			featureGates:       map[config.Feature]bool{config.QuotaPreflight: false},
+			expectedReady:      true,
+			expectedNoEC2Calls: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			awsCluster := testAWSCluster("test", "test", map[string]string{annotation.AWSVPCMode: annotation.AWSVPCModePrivate})
+			awsCluster.Spec.Region = awstest.Region
+			awsCluster.Spec.NetworkSpec.VPC.ID = tc.vpcId
+			if tc.endpointsReady {
+				conditions.MarkTrue(awsCluster, VpcEndpointReady)
+			}
+
+			base := config.Default()
+			base.FeatureGates = tc.featureGates
+			operatorConfig, err := config.Parse(nil, base)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			fakeEC2 := awstest.NewEC2().
+				On("DescribeVpcs", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeVpcsOutput{Vpcs: make([]ec2Types.Vpc, 5)}, nil
+				}).
+				On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeVpcEndpointsOutput{}, nil
+				})
+			var limits quotas.LimitsAPI = awstest.Limits{
+				quotas.VpcsPerRegion.QuotaCode:                5,
+				quotas.GatewayVpcEndpointsPerRegion.QuotaCode: 20,
+			}
+			if tc.accessDenied {
+				limits = deniedLimits{}
+			}
+			k8sClient := fake.NewClientBuilder().WithScheme(testScheme(t)).Build()
+			recorder := record.NewFakeRecorder(10)
+			r, err := NewAWSClusterReconciler(k8sClient, testScheme(t), recorder, fakeEC2.Client(), awstest.AssumeRoleClient{}, limits, config.NewStore(operatorConfig), dryrun.NewPlans())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			result, err := r.reconcileQuotasPhase(context.Background(), awsCluster, awstest.RoleARN)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Ready != tc.expectedReady {
+				t.Errorf("expected ready %t, got %t", tc.expectedReady, result.Ready)
+			}
+			if result.Reason != tc.expectedReason {
+				t.Errorf("expected reason %q, got %q", tc.expectedReason, result.Reason)
+			}
+			if !strings.Contains(result.Message, tc.expectedMessage) {
+				t.Errorf("expected message to contain %q, got %q", tc.expectedMessage, result.Message)
+			}
+			if tc.accessDenied && len(recorder.Events) != 1 {
+				t.Errorf("expected a warning event, got %d events", len(recorder.Events))
+			}
+			if tc.expectedNoEC2Calls && len(fakeEC2.AllCalls()) > 0 {
+				t.Errorf("expected no EC2 calls, got %v", fakeEC2.AllCalls())
+			}
+		})
+	}
+}
+
+// deniedLimits is a quotas.LimitsAPI for a role that is not allowed to get
+// service quotas.
+type deniedLimits struct{}
+
+func (deniedLimits) Limit(context.Context, string, string, string, string) (int, error) {
+	return 0, &smithy.GenericAPIError{Code: "AccessDeniedException"}
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/microerror"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/routetables"
+)
+
+func (r *AWSClusterReconciler) reconcileRouteTablesPhase(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string) (phaseResult, error) {
+	reconcileRequest := aws.ReconcileRequest[routetables.Spec]{
+		Resource:    awsCluster,
+		ClusterName: awsCluster.Name,
+		ClusterUID:  string(awsCluster.UID),
+		CloudResourceRequest: aws.CloudResourceRequest[routetables.Spec]{
+			RoleARN:            roleArn,
+			Region:             awsCluster.Spec.Region,
+			AdditionalTags:     awsCluster.Spec.AdditionalTags,
+			LastAppliedTagKeys: lastAppliedTagKeys(ctx, awsCluster),
+			Spec: routetables.Spec{
+				VpcId:        awsCluster.Spec.NetworkSpec.VPC.ID,
+				NameTemplate: r.config.Get().Naming.RouteTable,
+			},
+		},
+	}
+	for _, awsSubnetSpec := range awsCluster.Spec.NetworkSpec.Subnets {
+		reconcileRequest.Spec.Subnets = append(reconcileRequest.Spec.Subnets, routetables.Subnet{
+			Id:               awsSubnetSpec.ID,
+			AvailabilityZone: awsSubnetSpec.AvailabilityZone,
+		})
+	}
+
+	result, err := r.routeTablesReconciler.Reconcile(ctx, reconcileRequest)
+	if err != nil {
+		return phaseResult{}, microerror.Mask(err)
+	}
+
+	for _, routeTableStatus := range result.Status {
+		for _, association := range routeTableStatus.RouteTableAssociation {
+			if association.AssociationStateCode != routetables.AssociationStateCodeAssociated {
+				// route tables are not ready, so we requeue
+				return phaseResult{
+					// e.g. RouteTableAssociationStateAssociating
+					Reason:  "RouteTableAssociationState" + cases.Title(language.English).String(string(association.AssociationStateCode)),
+					Message: fmt.Sprintf("Route table %s for subnet %s not ready", routeTableStatus.RouteTableId, association.SubnetId),
+				}, nil
+			}
+		}
+	}
+
+	return phaseResult{Ready: true}, nil
+}
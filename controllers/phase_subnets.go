@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/microerror"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/subnets"
+)
+
+func (r *AWSClusterReconciler) reconcileSubnetsPhase(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string) (phaseResult, error) {
+	operatorConfig := r.config.Get()
+	subnetsReconcileRequest := subnets.ReconcileRequest{
+		Resource: awsCluster,
+		Spec: subnets.Spec{
+			ClusterName:        awsCluster.Name,
+			ClusterUID:         string(awsCluster.UID),
+			RoleARN:            roleArn,
+			VpcId:              awsCluster.Spec.NetworkSpec.VPC.ID,
+			AdditionalTags:     awsCluster.Spec.AdditionalTags,
+			LastAppliedTagKeys: lastAppliedTagKeys(ctx, awsCluster),
+			Region:             awsCluster.Spec.Region,
+
+			NameTemplate:            operatorConfig.Naming.Subnet,
+			InternalLoadBalancerTag: operatorConfig.Tags.InternalLoadBalancer,
+		},
+	}
+	for _, awsSubnetSpec := range awsCluster.Spec.NetworkSpec.Subnets {
+		subnetSpec := subnets.SubnetSpec{
+			SubnetId:         awsSubnetSpec.ID,
+			CidrBlock:        awsSubnetSpec.CidrBlock,
+			AvailabilityZone: awsSubnetSpec.AvailabilityZone,
+			Tags:             awsSubnetSpec.Tags,
+		}
+		subnetsReconcileRequest.Spec.Subnets = append(subnetsReconcileRequest.Spec.Subnets, subnetSpec)
+	}
+	subnetsReconcileResult, err := r.subnetsReconciler.Reconcile(ctx, subnetsReconcileRequest)
+	if err != nil {
+		return phaseResult{}, microerror.Mask(err)
+	}
+
+	// Update AWSCluster subnets
+	allSubnetsAvailable := true
+	allRouteTablesReady := true
+	allRouteTablesNotReadyMessage := ""
+	allRouteTablesNotReadyReason := ""
+	for _, existingSubnet := range subnetsReconcileResult.Subnets {
+		for i := range awsCluster.Spec.NetworkSpec.Subnets {
+			desiredSubnetSpec := &awsCluster.Spec.NetworkSpec.Subnets[i]
+			if desiredSubnetSpec.ID == existingSubnet.SubnetId || desiredSubnetSpec.CidrBlock == existingSubnet.CidrBlock {
+				desiredSubnetSpec.ID = existingSubnet.SubnetId
+				desiredSubnetSpec.CidrBlock = existingSubnet.CidrBlock
+				desiredSubnetSpec.AvailabilityZone = existingSubnet.AvailabilityZone
+				desiredSubnetSpec.Tags = existingSubnet.Tags
+
+				// Update subnet route table ID in the subnet spec
+				if existingSubnet.RouteTableAssociation.RouteTableId != "" {
+					routeTableId := existingSubnet.RouteTableAssociation.RouteTableId
+					desiredSubnetSpec.RouteTableID = &routeTableId
+					if existingSubnet.RouteTableAssociation.AssociationStateCode != subnets.AssociationStateCodeAssociated {
+						allRouteTablesReady = false
+						allRouteTablesNotReadyMessage = fmt.Sprintf("Route table %s for subnet %s not ready", routeTableId, existingSubnet.SubnetId)
+						// e.g. RouteTableAssociationStateAssociating
+						allRouteTablesNotReadyReason = "RouteTableAssociationState" + cases.Title(language.English).String(string(existingSubnet.RouteTableAssociation.AssociationStateCode))
+					}
+				} else {
+					allRouteTablesReady = false
+					allRouteTablesNotReadyReason = "RouteTableNotCreated"
+					allRouteTablesNotReadyMessage = fmt.Sprintf("Route table not created for subnet %s", existingSubnet.SubnetId)
+				}
+
+				if existingSubnet.State != subnets.SubnetStateAvailable {
+					allSubnetsAvailable = false
+				}
+				break
+			}
+		}
+	}
+
+	if !allSubnetsAvailable {
+		// subnets are not available, so we wait for subnets to become
+		// available before proceeding
+		return phaseResult{
+			Reason:  "SubnetsNotAvailable",
+			Message: "One or more subnets is still not available",
+		}, nil
+	}
+
+	if !allRouteTablesReady {
+		// route tables are not ready (or not even created), we update
+		// condition and proceed with route tables reconciliation
+		return phaseResult{
+			Reason:  allRouteTablesNotReadyReason,
+			Message: allRouteTablesNotReadyMessage,
+			Proceed: true,
+		}, nil
+	}
+
+	return phaseResult{Ready: true}, nil
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/requeue"
+)
+
+func Test_runPhases(t *testing.T) {
+	const (
+		firstCondition  capi.ConditionType = "FirstReady"
+		secondCondition capi.ConditionType = "SecondReady"
+		thirdCondition  capi.ConditionType = "ThirdReady"
+	)
+
+	testPhase := func(name string, condition capi.ConditionType, result phaseResult, runs *[]string) phase {
+		return phase{
+			Name:      name,
+			Condition: condition,
+			Reconcile: func(context.Context, *capa.AWSCluster, string) (phaseResult, error) {
+				*runs = append(*runs, name)
+				return result, nil
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		results              []phaseResult
+		prerequisites        []capi.ConditionType
+		secondBackoff        *requeue.Schedule
+		unmanagedFirst       bool
+		expectedRuns         []string
+		expectedRequeueAfter time.Duration
+		expectedNetworkReady bool
+	}{
+		{
+			name:                 "all phases ready",
+			results:              []phaseResult{{Ready: true}, {Ready: true}, {Ready: true}},
+			expectedRuns:         []string{"first", "second", "third"},
+			expectedNetworkReady: true,
+		},
+		{
+			name:                 "phase not ready stops the pipeline",
+			results:              []phaseResult{{Ready: true}, {Reason: "Pending"}, {Ready: true}},
+			expectedRuns:         []string{"first", "second"},
+			expectedRequeueAfter: time.Minute,
+		},
+		{
+			name:         "phase not ready proceeds to the next phase",
+			results:      []phaseResult{{Reason: "Pending", Proceed: true}, {Ready: true}, {Ready: true}},
+			expectedRuns: []string{"first", "second", "third"},
+		},
+		{
+			name:                 "phase backoff is used",
+			results:              []phaseResult{{Ready: true}, {Reason: "Pending"}, {Ready: true}},
+			secondBackoff:        &requeue.Schedule{Steps: []requeue.Step{{After: 0, RequeueAfter: 3 * time.Minute}}},
+			expectedRuns:         []string{"first", "second"},
+			expectedRequeueAfter: 3 * time.Minute,
+		},
+		{
+			name:                 "managed-only phase is skipped for unmanaged network",
+			results:              []phaseResult{{Reason: "Pending"}, {Ready: true}, {Ready: true}},
+			unmanagedFirst:       true,
+			expectedRuns:         []string{"second", "third"},
+			expectedNetworkReady: true,
+		},
+		{
+			name:                 "unmet prerequisite stops the pipeline",
+			results:              []phaseResult{{Reason: "Pending", Proceed: true}, {Ready: true}, {Ready: true}},
+			prerequisites:        []capi.ConditionType{firstCondition},
+			expectedRuns:         []string{"first", "second"},
+			expectedRequeueAfter: time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var runs []string
+			phases := []phase{
+				testPhase("first", firstCondition, tc.results[0], &runs),
+				testPhase("second", secondCondition, tc.results[1], &runs),
+				testPhase("third", thirdCondition, tc.results[2], &runs),
+			}
+			phases[0].ManagedOnly = true
+			phases[1].Backoff = tc.secondBackoff
+			phases[2].Prerequisites = tc.prerequisites
+
+			awsCluster := &capa.AWSCluster{}
+			if tc.unmanagedFirst {
+				awsCluster.Annotations = map[string]string{NetworkManagementAnnotation: NetworkManagementUnmanaged}
+			}
+			base := config.Default()
+			base.Requeue = config.Requeue{Schedule: "0s:1m"}
+			operatorConfig, err := config.Load("", base)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			r := &AWSClusterReconciler{
+				config: config.NewStore(operatorConfig),
+			}
+			result, err := r.runPhases(context.Background(), awsCluster, "", phases)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			setNetworkReadyCondition(awsCluster, phases)
+
+			if len(runs) != len(tc.expectedRuns) {
+				t.Fatalf("expected phases %v to run, got %v", tc.expectedRuns, runs)
+			}
+			for j := range runs {
+				if runs[j] != tc.expectedRuns[j] {
+					t.Fatalf("expected phases %v to run, got %v", tc.expectedRuns, runs)
+				}
+			}
+			if result.RequeueAfter != tc.expectedRequeueAfter {
+				t.Errorf("expected requeue after %s, got %s", tc.expectedRequeueAfter, result.RequeueAfter)
+			}
+			if conditions.IsTrue(awsCluster, NetworkReady) != tc.expectedNetworkReady {
+				t.Errorf("expected NetworkReady to be %t", tc.expectedNetworkReady)
+			}
+			if tc.unmanagedFirst && conditions.GetReason(awsCluster, firstCondition) != UnmanagedNetworkReason {
+				t.Errorf("expected condition of skipped phase to have reason %s, got %q", UnmanagedNetworkReason, conditions.GetReason(awsCluster, firstCondition))
+			}
+		})
+	}
+}
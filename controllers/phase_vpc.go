@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/microerror"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpc"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+func (r *AWSClusterReconciler) reconcileVpcPhase(ctx context.Context, awsCluster *capa.AWSCluster, roleArn string) (phaseResult, error) {
+	vpcSpec := vpc.Spec{
+		ClusterName:        awsCluster.Name,
+		ClusterUID:         string(awsCluster.UID),
+		RoleARN:            roleArn,
+		Region:             awsCluster.Spec.Region,
+		VpcId:              awsCluster.Spec.NetworkSpec.VPC.ID,
+		CidrBlock:          awsCluster.Spec.NetworkSpec.VPC.CidrBlock,
+		AdditionalTags:     awsCluster.Spec.AdditionalTags,
+		LastAppliedTagKeys: lastAppliedTagKeys(ctx, awsCluster),
+		Unmanaged:          isUnmanagedNetwork(awsCluster),
+	}
+	status, err := r.vpcReconciler.Reconcile(ctx, vpcSpec)
+	if errors.IsResourceNotOwned(err) {
+		// we don't take over VPCs that we did not create
+		return phaseResult{
+			Reason:  "VpcNotOwned",
+			Message: fmt.Sprintf("%s, set annotation %s=%s to use it as an unmanaged VPC", err.Error(), NetworkManagementAnnotation, NetworkManagementUnmanaged),
+		}, nil
+	} else if err != nil {
+		return phaseResult{}, microerror.Mask(err)
+	}
+
+	// Update AWSCluster CR
+	awsCluster.Spec.NetworkSpec.VPC.ID = status.VpcId
+	awsCluster.Spec.NetworkSpec.VPC.CidrBlock = status.CidrBlock
+	awsCluster.Spec.NetworkSpec.VPC.Tags = status.Tags
+	switch status.State {
+	case vpc.VpcStateAvailable:
+		return phaseResult{Ready: true}, nil
+	case vpc.VpcStatePending:
+		return phaseResult{Reason: "VpcStatePending", Message: "VPC is in pending state"}, nil
+	case "":
+		return phaseResult{}, microerror.Maskf(errors.VpcStateNotSetError, "VPC state is not set '%s'", status.State)
+	default:
+		return phaseResult{}, microerror.Maskf(errors.VpcStateUnknownError, "VPC is in unknown state '%s'", status.State)
+	}
+}
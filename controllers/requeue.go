@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/requeue"
+)
+
+// requeueSchedule returns the schedule that is used while the AWSCluster is
+// waiting for AWS resources. The operator schedule can be overridden per
+// cluster with RequeueScheduleAnnotation. An invalid annotation is logged and
+// ignored.
+func (r *AWSClusterReconciler) requeueSchedule(ctx context.Context, awsCluster *capa.AWSCluster) requeue.Schedule {
+	defaultSchedule := r.config.Get().RequeueSchedule()
+	rawSchedule, ok := awsCluster.Annotations[RequeueScheduleAnnotation]
+	if !ok {
+		return defaultSchedule
+	}
+
+	schedule, err := requeue.Parse(rawSchedule, defaultSchedule.Jitter)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Invalid requeue schedule annotation, using the default schedule", "annotation", RequeueScheduleAnnotation)
+		return defaultSchedule
+	}
+
+	return schedule
+}
+
+// phaseSchedule returns the schedule that is used while the phase is waiting
+// for its prerequisites or its resources. It is the backoff of the phase with
+// the operator jitter, unless the operator schedule is overridden for the
+// AWSCluster with RequeueScheduleAnnotation.
+func (r *AWSClusterReconciler) phaseSchedule(ctx context.Context, awsCluster *capa.AWSCluster, p phase) requeue.Schedule {
+	_, overridden := awsCluster.Annotations[RequeueScheduleAnnotation]
+	if p.Backoff == nil || overridden {
+		return r.requeueSchedule(ctx, awsCluster)
+	}
+
+	schedule := *p.Backoff
+	schedule.Jitter = r.config.Get().Requeue.Jitter
+	return schedule
+}
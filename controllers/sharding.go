@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// ShardLabel is the label of the AWSClusters that operators with a shard
+// selector reconcile. The default shard, without a shard selector, reconciles
+// the AWSClusters without the label, so that a cluster is never reconciled by
+// the default shard and another shard at the same time.
+const ShardLabel = "aws-vpc-operator.giantswarm.io/shard"
+
+// Shard selects the AWSClusters that an operator replica reconciles, so that
+// several replicas can reconcile disjoint subsets of the clusters, e.g. a
+// canary version of aws-vpc-operator reconciles the clusters labelled for it.
+type Shard struct {
+	// Namespaces of the reconciled AWSClusters. AWSClusters in all
+	// namespaces are reconciled when it is empty.
+	Namespaces []string
+
+	// Selector selects the reconciled AWSClusters by their labels. It
+	// selects the AWSClusters without ShardLabel in the default shard.
+	Selector labels.Selector
+
+	// isDefault is set for the default shard, without a shard selector.
+	isDefault bool
+}
+
+// ParseShard parses a comma-separated list of namespaces and a label
+// selector, e.g. "aws-vpc-operator.giantswarm.io/shard=canary". Empty
+// namespaces select all namespaces. The selector must select AWSClusters
+// with specific values of ShardLabel, and an empty selector selects the
+// AWSClusters without ShardLabel.
+func ParseShard(namespaces, selector string) (Shard, error) {
+	shard := Shard{}
+	for _, namespace := range strings.Split(namespaces, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" {
+			shard.Namespaces = append(shard.Namespaces, namespace)
+		}
+	}
+	sort.Strings(shard.Namespaces)
+
+	if strings.TrimSpace(selector) == "" {
+		requirement, err := labels.NewRequirement(ShardLabel, selection.DoesNotExist, nil)
+		if err != nil {
+			return Shard{}, microerror.Mask(err)
+		}
+		shard.Selector = labels.NewSelector().Add(*requirement)
+		shard.isDefault = true
+		return shard, nil
+	}
+
+	var err error
+	shard.Selector, err = labels.Parse(selector)
+	if err != nil {
+		return Shard{}, microerror.Maskf(errors.InvalidConfigError, "invalid shard selector %q: %s", selector, err)
+	}
+	// other selectors could select AWSClusters of the default shard
+	requirements, _ := shard.Selector.Requirements()
+	selectsShard := false
+	for _, requirement := range requirements {
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+			selectsShard = selectsShard || requirement.Key() == ShardLabel
+		}
+	}
+	if !selectsShard {
+		return Shard{}, microerror.Maskf(errors.InvalidConfigError, "shard selector %q must select AWSClusters by the values of label %s, so that they are not reconciled by the default shard too", selector, ShardLabel)
+	}
+
+	return shard, nil
+}
+
+// IsDefault checks if the shard is the default shard in all namespaces, which
+// has all AWSClusters that are not labelled for another shard.
+func (s Shard) IsDefault() bool {
+	return len(s.Namespaces) == 0 && s.isDefault
+}
+
+// CacheOptions restricts the manager cache to the namespaces of the shard,
+// and its AWSClusters to the ones selected by the shard. AWSClusters of other
+// shards are therefore never reconciled, also not through watches of
+// Clusters and AWSClusterRoleIdentities.
+func (s Shard) CacheOptions() cache.Options {
+	options := cache.Options{}
+	if len(s.Namespaces) > 0 {
+		options.DefaultNamespaces = map[string]cache.Config{}
+		for _, namespace := range s.Namespaces {
+			options.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+	if s.Selector != nil {
+		options.ByObject = map[client.Object]cache.ByObject{
+			&capa.AWSCluster{}: {Label: s.Selector},
+		}
+	}
+	return options
+}
+
+// LeaderElectionID returns the leader election ID of the shard, so that one
+// replica of each shard is the leader. Replicas with the same namespaces and
+// selector are in the same shard. The base ID is used for the default shard.
+func (s Shard) LeaderElectionID(base string) string {
+	if s.IsDefault() {
+		return base
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(strings.Join(s.Namespaces, ",")))
+	_, _ = hash.Write([]byte{0})
+	if s.Selector != nil {
+		_, _ = hash.Write([]byte(s.Selector.String()))
+	}
+	return fmt.Sprintf("shard-%08x.%s", hash.Sum32(), base)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/labels"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+func Test_Shard(t *testing.T) {
+	const baseID = "73f833f4.giantswarm.io"
+
+	testCases := []struct {
+		name               string
+		namespaces         string
+		selector           string
+		expectError        bool
+		expectedNamespaces int
+		expectedSelector   bool
+		expectedBaseID     bool
+	}{
+		{
+			name:             "default shard",
+			expectedSelector: true,
+			expectedBaseID:   true,
+		},
+		{
+			name:               "namespaces",
+			namespaces:         "org-b, org-a,",
+			expectedNamespaces: 2,
+			expectedSelector:   true,
+		},
+		{
+			name:             "selector",
+			selector:         "aws-vpc-operator.giantswarm.io/shard=canary",
+			expectedSelector: true,
+		},
+		{
+			name:        "invalid selector",
+			selector:    "shard in (canary",
+			expectError: true,
+		},
+		{
+			name:        "selector without shard label",
+			selector:    "team=network",
+			expectError: true,
+		},
+		{
+			name:        "selector that excludes a shard",
+			selector:    "aws-vpc-operator.giantswarm.io/shard!=stable",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			shard, err := ParseShard(tc.namespaces, tc.selector)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got %v", shard)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			options := shard.CacheOptions()
+			if len(options.DefaultNamespaces) != tc.expectedNamespaces {
+				t.Errorf("expected %d cached namespaces, got %v", tc.expectedNamespaces, options.DefaultNamespaces)
+			}
+			hasSelector := false
+			for object, byObject := range options.ByObject {
+				if _, ok := object.(*capa.AWSCluster); ok && byObject.Label != nil {
+					hasSelector = true
+				}
+			}
+			if hasSelector != tc.expectedSelector {
+				t.Errorf("expected AWSCluster cache selector %t, got %v", tc.expectedSelector, options.ByObject)
+			}
+
+			id := shard.LeaderElectionID(baseID)
+			if (id == baseID) != tc.expectedBaseID {
+				t.Errorf("unexpected leader election ID %s", id)
+			}
+		})
+	}
+
+	// replicas with the same shard elect the same leader, in any order of
+	// namespaces
+	first, _ := ParseShard("org-a,org-b", ShardLabel+"=canary")
+	second, _ := ParseShard("org-b,org-a", ShardLabel+"=canary")
+	other, _ := ParseShard("org-a,org-b", ShardLabel+"=stable")
+	if first.LeaderElectionID(baseID) != second.LeaderElectionID(baseID) {
+		t.Errorf("expected equal leader election IDs of the same shard")
+	}
+	if first.LeaderElectionID(baseID) == other.LeaderElectionID(baseID) {
+		t.Errorf("expected different leader election IDs of different shards")
+	}
+	if !first.Selector.Matches(labels.Set{ShardLabel: "canary"}) || first.Selector.Matches(labels.Set{ShardLabel: "stable"}) {
+		t.Errorf("unexpected selector %s", first.Selector)
+	}
+
+	// the default shard does not have AWSClusters of other shards
+	defaultShard, _ := ParseShard("", "")
+	if !defaultShard.IsDefault() || !defaultShard.Selector.Matches(labels.Set{}) || defaultShard.Selector.Matches(labels.Set{ShardLabel: "canary"}) {
+		t.Errorf("unexpected default shard selector %s", defaultShard.Selector)
+	}
+}
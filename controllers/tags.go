@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// lastAppliedTagKeys returns the keys of AdditionalTags that have been applied
+// to the network resources before, see LastAppliedTagKeysAnnotation. An
+// invalid annotation is logged and ignored, in which case no tags are removed.
+func lastAppliedTagKeys(ctx context.Context, awsCluster *capa.AWSCluster) []string {
+	value, ok := awsCluster.Annotations[LastAppliedTagKeysAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+	err := json.Unmarshal([]byte(value), &keys)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Ignoring invalid annotation", "annotation", LastAppliedTagKeysAnnotation)
+		return nil
+	}
+
+	return keys
+}
+
+// setLastAppliedTagKeys records the keys of current AdditionalTags in
+// LastAppliedTagKeysAnnotation. It must be called only after the tags have
+// been applied to all network resources, otherwise removed tags would be left
+// behind on the resources that have not been updated yet.
+func setLastAppliedTagKeys(awsCluster *capa.AWSCluster) {
+	keys := make([]string, 0, len(awsCluster.Spec.AdditionalTags))
+	for key := range awsCluster.Spec.AdditionalTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	value, _ := json.Marshal(keys)
+	if awsCluster.Annotations == nil {
+		awsCluster.Annotations = map[string]string{}
+	}
+	awsCluster.Annotations[LastAppliedTagKeysAnnotation] = string(value)
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// isReconciledAWSCluster checks if the AWSCluster is reconciled by
+// aws-vpc-operator, i.e. it has the private VPC mode annotation, or it still
+// has our finalizer, so that it can be deleted.
+func isReconciledAWSCluster(object client.Object) bool {
+	if object.GetAnnotations()[annotation.AWSVPCMode] == annotation.AWSVPCModePrivate {
+		return true
+	}
+	return controllerutil.ContainsFinalizer(object, AwsVpcOperatorFinalizer)
+}
+
+// reconciledAWSClusters filters out events for AWSClusters that are not
+// reconciled by aws-vpc-operator.
+func reconciledAWSClusters() predicate.Predicate {
+	return predicate.NewPredicateFuncs(isReconciledAWSCluster)
+}
+
+// identityToAWSClusters returns reconciliation requests for all reconciled
+// AWSClusters that use the AWSClusterRoleIdentity, so that they are
+// reconciled with the new role as soon as the identity changes.
+func (r *AWSClusterReconciler) identityToAWSClusters(ctx context.Context, object client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	identity, ok := object.(*capa.AWSClusterRoleIdentity)
+	if !ok {
+		return nil
+	}
+
+	awsClusters := &capa.AWSClusterList{}
+	err := r.List(ctx, awsClusters)
+	if err != nil {
+		logger.Error(err, "Failed to list AWSClusters for AWSClusterRoleIdentity", "identity", identity.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range awsClusters.Items {
+		awsCluster := &awsClusters.Items[i]
+		if awsCluster.Spec.IdentityRef == nil ||
+			awsCluster.Spec.IdentityRef.Kind != capa.ClusterRoleIdentityKind ||
+			awsCluster.Spec.IdentityRef.Name != identity.Name ||
+			!isReconciledAWSCluster(awsCluster) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(awsCluster),
+		})
+	}
+
+	return requests
+}
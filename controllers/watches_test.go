@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := capa.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := capi.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func testAWSCluster(name, identity string, annotations map[string]string) *capa.AWSCluster {
+	return &capa.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "org-test",
+			Name:        name,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: capi.GroupVersion.String(), Kind: "Cluster", Name: name},
+			},
+		},
+		Spec: capa.AWSClusterSpec{
+			IdentityRef: &capa.AWSIdentityReference{Kind: capa.ClusterRoleIdentityKind, Name: identity},
+		},
+	}
+}
+
+func Test_isPaused(t *testing.T) {
+	private := map[string]string{annotation.AWSVPCMode: annotation.AWSVPCModePrivate}
+	paused := map[string]string{annotation.AWSVPCMode: annotation.AWSVPCModePrivate, capi.PausedAnnotation: "true"}
+
+	testCases := []struct {
+		name        string
+		awsCluster  *capa.AWSCluster
+		clusterSpec *capi.ClusterSpec
+		expected    bool
+	}{
+		{
+			name:        "not paused",
+			awsCluster:  testAWSCluster("test", "default", private),
+			clusterSpec: &capi.ClusterSpec{},
+			expected:    false,
+		},
+		{
+			name:        "AWSCluster paused with annotation",
+			awsCluster:  testAWSCluster("test", "default", paused),
+			clusterSpec: &capi.ClusterSpec{},
+			expected:    true,
+		},
+		{
+			name:        "Cluster paused",
+			awsCluster:  testAWSCluster("test", "default", private),
+			clusterSpec: &capi.ClusterSpec{Paused: true},
+			expected:    true,
+		},
+		{
+			name:       "Cluster not found",
+			awsCluster: testAWSCluster("test", "default", private),
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := []client.Object{tc.awsCluster}
+			if tc.clusterSpec != nil {
+				objects = append(objects, &capi.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: tc.awsCluster.Namespace, Name: tc.awsCluster.Name},
+					Spec:       *tc.clusterSpec,
+				})
+			}
+			r := &AWSClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(objects...).Build(),
+			}
+
+			result, err := r.isPaused(context.Background(), tc.awsCluster)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Fatalf("expected paused %t, got %t", tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_identityToAWSClusters(t *testing.T) {
+	private := map[string]string{annotation.AWSVPCMode: annotation.AWSVPCModePrivate}
+
+	objects := []client.Object{
+		testAWSCluster("uses-identity", "test-identity", private),
+		testAWSCluster("other-identity", "default", private),
+		testAWSCluster("not-private", "test-identity", nil),
+	}
+	r := &AWSClusterReconciler{
+		Client: fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(objects...).Build(),
+	}
+
+	identity := &capa.AWSClusterRoleIdentity{ObjectMeta: metav1.ObjectMeta{Name: "test-identity"}}
+	requests := r.identityToAWSClusters(context.Background(), identity)
+
+	if len(requests) != 1 || requests[0].Name != "uses-identity" {
+		t.Fatalf("expected request for AWSCluster uses-identity, got %v", requests)
+	}
+}
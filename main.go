@@ -19,11 +19,18 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap/zapcore"
 	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -33,9 +40,11 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -43,6 +52,15 @@ import (
 
 	"github.com/giantswarm/aws-vpc-operator/controllers"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/quotas"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/ratelimit"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/cli"
+	operatorconfig "github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/orphans"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
+	"github.com/giantswarm/aws-vpc-operator/pkg/webhooks"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -60,14 +78,86 @@ func init() {
 }
 
 func main() {
+	// Subcommands inspect and reconcile a single AWSCluster without running
+	// the operator.
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		err := cli.Run(context.Background(), os.Args[1:], os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var tracingConfig tracing.Config
+	var enableWebhooks bool
+	var configFile string
+	var defaultAdditionalTags string
+	var orphanScannerTargets string
+	var orphanScannerConfigMap string
+	var orphanScannerInterval time.Duration
+	var maxConcurrentReconciles int
+	var watchNamespaces string
+	var shardSelector string
+	baseConfig := operatorconfig.Default()
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&tracingConfig.Enabled, "tracing-enabled", false,
+		"Enable OpenTelemetry tracing of reconciliations and AWS API calls.")
+	flag.StringVar(&tracingConfig.Endpoint, "tracing-otlp-endpoint", "",
+		"The OTLP/HTTP endpoint (host:port) to which traces are exported.")
+	flag.BoolVar(&tracingConfig.Insecure, "tracing-otlp-insecure", false,
+		"Disable TLS when exporting traces to the OTLP endpoint.")
+	flag.Float64Var(&tracingConfig.SampleRatio, "tracing-sample-ratio", 1,
+		"The ratio of reconciliations that are traced, between 0 and 1.")
+	flag.StringVar(&configFile, "config-file", "",
+		"The operator configuration file. Its fields override the flags below, and it is reloaded when it changes.")
+	flag.StringVar(&baseConfig.Requeue.Schedule, "requeue-schedule", baseConfig.Requeue.Schedule,
+		"The schedule for retrying reconciliations that are waiting for AWS resources, "+
+			"as a comma-separated list of <after>:<requeue-after>[:<severity>] steps.")
+	flag.Float64Var(&baseConfig.Requeue.Jitter, "requeue-jitter", baseConfig.Requeue.Jitter,
+		"The max fraction of the requeue time that is randomly added to it, between 0 and 1.")
+	flag.DurationVar(&baseConfig.Drift.Interval.Duration, "drift-check-interval", baseConfig.Drift.Interval.Duration,
+		"How often ready AWSClusters are checked for changes of their AWS resources that were not made by the operator. Disabled when 0.")
+	flag.BoolVar(&baseConfig.Drift.AutoRepair, "drift-auto-repair", baseConfig.Drift.AutoRepair,
+		"Repair the drift found by drift checks. Otherwise drift is only reported in events and metrics.")
+	flag.BoolVar(&baseConfig.DryRun, "dry-run", false,
+		"Record the changes of AWS resources in a plan for each AWSCluster instead of making them. "+
+			"Plans are reported in the NoChangesPlanned condition, in events and on the "+dryrun.PlansPath+" endpoint of the metrics server.")
+	flag.BoolVar(&enableWebhooks, "webhooks-enabled", false,
+		"Enable admission webhooks for AWSClusters in private VPC mode. "+
+			"The webhook server serving certificate is read from /tmp/k8s-webhook-server/serving-certs.")
+	flag.StringVar(&baseConfig.Defaults.VpcCidr, "default-vpc-cidr", baseConfig.Defaults.VpcCidr,
+		"The VPC CIDR block set by the defaulting webhook in AWSClusters that do not specify one.")
+	flag.IntVar(&baseConfig.Defaults.AvailabilityZoneCount, "default-availability-zone-count", baseConfig.Defaults.AvailabilityZoneCount,
+		"The number of private subnets, one per availability zone, set by the defaulting webhook in AWSClusters without subnets.")
+	flag.StringVar(&baseConfig.Defaults.VpcEndpointMode, "default-vpc-endpoint-mode", baseConfig.Defaults.VpcEndpointMode,
+		"The VPC endpoint mode annotation set by the defaulting webhook in AWSClusters that do not have it. Not set when empty.")
+	flag.StringVar(&defaultAdditionalTags, "default-additional-tags", "",
+		"Tags added by the defaulting webhook to additional tags of new AWSClusters, as a comma-separated list of key=value pairs.")
+	flag.StringVar(&orphanScannerTargets, "orphan-scanner-targets", "",
+		"Comma-separated roles and regions in the format <role-arn>@<region>, which are scanned periodically for resources "+
+			"of clusters without an AWSCluster. The orphan scanner is disabled when empty.")
+	flag.StringVar(&orphanScannerConfigMap, "orphan-scanner-configmap", "",
+		"The ConfigMap, as <namespace>/<name>, to which the orphan scanner writes its report. Deletion of orphaned resources "+
+			"is approved with the "+orphans.ApproveDeletionAnnotation+" annotation on it.")
+	flag.DurationVar(&orphanScannerInterval, "orphan-scanner-interval", orphans.DefaultInterval,
+		"How often the orphan scanner scans for resources of clusters without an AWSCluster.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The max number of AWSClusters that are reconciled concurrently.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma-separated namespaces whose AWSClusters are reconciled. AWSClusters in all namespaces are reconciled when empty.")
+	flag.StringVar(&shardSelector, "shard-selector", "",
+		"Label selector of the AWSClusters that are reconciled, e.g. aws-vpc-operator.giantswarm.io/shard=canary, so that "+
+			"several operators can each reconcile a subset of the clusters. It must select values of the aws-vpc-operator.giantswarm.io/shard label, "+
+			"and operators without it reconcile the AWSClusters without that label. Each shard of namespaces and selector elects its own leader. "+
+			"The orphan scanner cannot be enabled together with a shard.")
 	opts := zap.Options{
 		Development: false,
 		TimeEncoder: zapcore.ISO8601TimeEncoder,
@@ -78,10 +168,55 @@ func main() {
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 	ctx := context.Background()
 
+	tracerProvider, shutdownTracerProvider, err := tracing.NewTracerProvider(ctx, tracingConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create tracer provider")
+		os.Exit(1)
+	}
+	otel.SetTracerProvider(tracerProvider)
+	defer func() {
+		if err := shutdownTracerProvider(context.Background()); err != nil {
+			setupLog.Error(err, "unable to shut down tracer provider")
+		}
+	}()
+
+	baseConfig.Defaults.AdditionalTags, err = tags.Parse(defaultAdditionalTags)
+	if err != nil {
+		setupLog.Error(err, "invalid default additional tags")
+		os.Exit(1)
+	}
+	operatorConfig, err := operatorconfig.Load(configFile, baseConfig)
+	if err != nil {
+		setupLog.Error(err, "invalid operator configuration", "path", configFile)
+		os.Exit(1)
+	}
+	configStore := operatorconfig.NewStore(operatorConfig)
+	plans := dryrun.NewPlans()
+
+	if maxConcurrentReconciles < 1 {
+		setupLog.Error(nil, "invalid max concurrent reconciles, must be at least 1", "max-concurrent-reconciles", maxConcurrentReconciles)
+		os.Exit(1)
+	}
+	shard, err := controllers.ParseShard(watchNamespaces, shardSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid shard")
+		os.Exit(1)
+	}
+	// the leader of every shard would run its own orphan scanner, and they
+	// would all write the same ConfigMap
+	if orphanScannerTargets != "" && !shard.IsDefault() {
+		setupLog.Error(nil, "the orphan scanner can only run in the default shard, unset --watch-namespaces and --shard-selector or --orphan-scanner-targets")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
+		Cache:  shard.CacheOptions(),
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
+			ExtraHandlers: map[string]http.Handler{
+				dryrun.PlansPath: plans,
+			},
 		},
 		WebhookServer: webhook.NewServer(
 			webhook.Options{
@@ -90,7 +225,7 @@ func main() {
 		),
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "73f833f4.giantswarm.io",
+		LeaderElectionID:       shard.LeaderElectionID("73f833f4.giantswarm.io"),
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -113,31 +248,105 @@ func main() {
 		setupLog.Error(err, "unable to create default AWS client config")
 		os.Exit(1)
 	}
+	if tracingConfig.Enabled {
+		tracing.AppendMiddlewares(&cfg.APIOptions)
+	}
+	cfg.Retryer = func() aws.Retryer {
+		return operatorconfig.NewRetryer(configStore)
+	}
 	ec2Client := ec2.NewFromConfig(cfg)
 	assumeRoleAPIClient := sts.NewFromConfig(cfg)
-	assumeRoleClient, err := assumerole.NewClient(assumeRoleAPIClient)
+	rateLimiter := ratelimit.NewLimiter(func() ratelimit.Settings {
+		return configStore.Get().AWS.RateLimit
+	})
+	assumeRoleClient, err := assumerole.NewClient(assumeRoleAPIClient, rateLimiter)
 	if err != nil {
 		setupLog.Error(err, "unable to create client for assuming roles")
 		os.Exit(1)
 	}
+	limits, err := quotas.NewLimitsClient(servicequotas.NewFromConfig(cfg), assumeRoleClient)
+	if err != nil {
+		setupLog.Error(err, "unable to create client for service quotas")
+		os.Exit(1)
+	}
 
 	awsReconciler, err := controllers.NewAWSClusterReconciler(
 		mgr.GetClient(),
 		mgr.GetScheme(),
+		mgr.GetEventRecorderFor("aws-vpc-operator"),
 		ec2Client,
 		assumeRoleClient,
+		limits,
+		configStore,
+		plans,
 	)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSCluster")
 		os.Exit(1)
 	}
 
-	if err = awsReconciler.SetupWithManager(mgr); err != nil {
+	if err = awsReconciler.SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}); err != nil {
 		setupLog.Error(err, "unable to setup controller", "controller", "AWSCluster")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		awsClusterDefaulter, err := webhooks.NewAWSClusterDefaulter(configStore)
+		if err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AWSClusterDefaulter")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupAWSClusterWebhooksWithManager(mgr, awsClusterDefaulter, &webhooks.AWSClusterValidator{}); err != nil {
+			setupLog.Error(err, "unable to setup webhooks", "webhook", "AWSCluster")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if orphanScannerTargets != "" {
+		targets, err := orphans.ParseTargets(orphanScannerTargets)
+		if err != nil {
+			setupLog.Error(err, "invalid orphan scanner targets")
+			os.Exit(1)
+		}
+		configMapNamespace, configMapName, _ := strings.Cut(orphanScannerConfigMap, "/")
+		// the cache only has the AWSClusters of the shard, but clusters of
+		// other shards are not orphaned
+		scanner, err := orphans.NewScanner(mgr.GetAPIReader(), ec2Client, assumeRoleClient)
+		if err != nil {
+			setupLog.Error(err, "unable to create orphan scanner")
+			os.Exit(1)
+		}
+		periodicScanner, err := orphans.NewPeriodicScanner(
+			scanner,
+			mgr.GetClient(),
+			mgr.GetAPIReader(),
+			targets,
+			types.NamespacedName{Namespace: configMapNamespace, Name: configMapName},
+			orphanScannerInterval,
+			configStore,
+		)
+		if err != nil {
+			setupLog.Error(err, "unable to create periodic orphan scanner")
+			os.Exit(1)
+		}
+		if err := mgr.Add(periodicScanner); err != nil {
+			setupLog.Error(err, "unable to add periodic orphan scanner")
+			os.Exit(1)
+		}
+	}
+
+	if configFile != "" {
+		configWatcher, err := operatorconfig.NewWatcher(configFile, baseConfig, configStore, operatorconfig.DefaultWatchInterval)
+		if err != nil {
+			setupLog.Error(err, "unable to create operator configuration watcher")
+			os.Exit(1)
+		}
+		if err := mgr.Add(configWatcher); err != nil {
+			setupLog.Error(err, "unable to add operator configuration watcher")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
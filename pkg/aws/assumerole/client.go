@@ -4,27 +4,37 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
 	"github.com/giantswarm/microerror"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/ratelimit"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
 type Client interface {
 	AssumeRoleFunc(roleArn, region string) func(o *ec2.Options)
+	AssumeRoleServiceQuotasFunc(roleArn, region string) func(o *servicequotas.Options)
 }
 
-func NewClient(stsCredsAssumeRoleAPIClient stscreds.AssumeRoleAPIClient) (Client, error) {
+// NewClient returns a client whose EC2 calls are limited by the rate limiter
+// of the AWS account of the role and the region.
+func NewClient(stsCredsAssumeRoleAPIClient stscreds.AssumeRoleAPIClient, rateLimiter *ratelimit.Limiter) (Client, error) {
 	if stsCredsAssumeRoleAPIClient == nil {
 		return nil, microerror.Maskf(errors.InvalidConfigError, "stsCredsAssumeRoleAPIClient must not be empty")
 	}
+	if rateLimiter == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "rateLimiter must not be empty")
+	}
 
 	return &client{
 		stsCredsAssumeRoleAPIClient: stsCredsAssumeRoleAPIClient,
+		rateLimiter:                 rateLimiter,
 	}, nil
 }
 
 type client struct {
 	stsCredsAssumeRoleAPIClient stscreds.AssumeRoleAPIClient
+	rateLimiter                 *ratelimit.Limiter
 }
 
 func (c *client) AssumeRoleFunc(roleArn, region string) func(o *ec2.Options) {
@@ -32,5 +42,14 @@ func (c *client) AssumeRoleFunc(roleArn, region string) func(o *ec2.Options) {
 		assumeRoleProvider := stscreds.NewAssumeRoleProvider(c.stsCredsAssumeRoleAPIClient, roleArn)
 		o.Credentials = aws.NewCredentialsCache(assumeRoleProvider)
 		o.Region = region
+		o.APIOptions = append(o.APIOptions, c.rateLimiter.APIOption(roleArn, region))
+	}
+}
+
+func (c *client) AssumeRoleServiceQuotasFunc(roleArn, region string) func(o *servicequotas.Options) {
+	return func(o *servicequotas.Options) {
+		assumeRoleProvider := stscreds.NewAssumeRoleProvider(c.stsCredsAssumeRoleAPIClient, roleArn)
+		o.Credentials = aws.NewCredentialsCache(assumeRoleProvider)
+		o.Region = region
 	}
 }
@@ -0,0 +1,136 @@
+// Package awstest contains fakes for testing code that calls the AWS EC2 and
+// Service Quotas APIs without sending any requests to AWS.
+package awstest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	Region  = "eu-west-1"
+	RoleARN = "arn:aws:iam::123456789012:role/test"
+
+	middlewareID = "AWSTestFakeEC2"
+)
+
+// Handler returns the output of a faked EC2 API call for the specified input.
+type Handler func(input interface{}) (output interface{}, err error)
+
+// Call is a recorded EC2 API call.
+type Call struct {
+	Operation string
+	Input     interface{}
+}
+
+// EC2 fakes EC2 API operations. Every API call made with a client returned by
+// EC2.Client is recorded and answered by the handler registered for the
+// operation, before the request is serialized and sent.
+type EC2 struct {
+	mutex    sync.Mutex
+	handlers map[string]Handler
+	calls    []Call
+}
+
+func NewEC2() *EC2 {
+	return &EC2{
+		handlers: map[string]Handler{},
+	}
+}
+
+// On registers a handler for the specified EC2 API operation, e.g.
+// "DescribeVpcs".
+func (f *EC2) On(operation string, handler Handler) *EC2 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.handlers[operation] = handler
+	return f
+}
+
+// Client returns an EC2 client that uses the fake. Additional API options are
+// added to the middleware stack before the fake, so that they are executed.
+func (f *EC2) Client(apiOptions ...func(*middleware.Stack) error) *ec2.Client {
+	apiOptions = append(apiOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(middlewareID, f.handleInitialize), middleware.After)
+	})
+
+	return ec2.New(ec2.Options{
+		Region:      Region,
+		Credentials: aws.AnonymousCredentials{},
+		APIOptions:  apiOptions,
+	})
+}
+
+// Calls returns the number of recorded calls for the specified operation.
+func (f *EC2) Calls(operation string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	count := 0
+	for _, call := range f.calls {
+		if call.Operation == operation {
+			count++
+		}
+	}
+	return count
+}
+
+// AllCalls returns all recorded calls in the order they were made.
+func (f *EC2) AllCalls() []Call {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]Call{}, f.calls...)
+}
+
+func (f *EC2) handleInitialize(ctx context.Context, in middleware.InitializeInput, _ middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	operation := awsmiddleware.GetOperationName(ctx)
+
+	f.mutex.Lock()
+	f.calls = append(f.calls, Call{Operation: operation, Input: in.Parameters})
+	handler, ok := f.handlers[operation]
+	f.mutex.Unlock()
+
+	var metadata middleware.Metadata
+	awsmiddleware.SetRequestIDMetadata(&metadata, fmt.Sprintf("request-%s", operation))
+
+	if !ok {
+		return middleware.InitializeOutput{}, metadata, fmt.Errorf("awstest: no handler registered for EC2 operation %s", operation)
+	}
+
+	output, err := handler(in.Parameters)
+	return middleware.InitializeOutput{Result: output}, metadata, err
+}
+
+// AssumeRoleClient is a fake assumerole.Client that only sets the region.
+type AssumeRoleClient struct{}
+
+func (AssumeRoleClient) AssumeRoleFunc(_, region string) func(o *ec2.Options) {
+	return func(o *ec2.Options) {
+		o.Region = region
+	}
+}
+
+func (AssumeRoleClient) AssumeRoleServiceQuotasFunc(_, region string) func(o *servicequotas.Options) {
+	return func(o *servicequotas.Options) {
+		o.Region = region
+	}
+}
+
+// Limits is a fake quotas.LimitsAPI that returns service quota values by
+// quota code, e.g. "L-F678F1CE".
+type Limits map[string]int
+
+func (l Limits) Limit(_ context.Context, _, _, serviceCode, quotaCode string) (int, error) {
+	limit, ok := l[quotaCode]
+	if !ok {
+		return 0, fmt.Errorf("awstest: no limit registered for service quota %s of service %s", quotaCode, serviceCode)
+	}
+	return limit, nil
+}
@@ -0,0 +1,119 @@
+package blockers
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+type DeleteOwnedInput struct {
+	RoleARN     string
+	Region      string
+	ClusterName string
+	ClusterUID  string
+
+	// Resources that are deleted if they are owned by the cluster. Other
+	// resources are skipped.
+	Resources []BlockingResource
+}
+
+type DeleteOwnedOutput struct {
+	// Deleted resources.
+	Deleted []BlockingResource
+
+	// Skipped resources, which have not been created by aws-vpc-operator for
+	// the cluster, or which are still in use, e.g. network interfaces that are
+	// attached to an instance.
+	Skipped []BlockingResource
+}
+
+func (c *client) DeleteOwned(ctx context.Context, input DeleteOwnedInput) (output DeleteOwnedOutput, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started deleting owned resources that block deletion")
+	defer func() {
+		if err == nil {
+			logger.Info("Finished deleting owned resources that block deletion", "deleted", len(output.Deleted), "skipped", len(output.Skipped))
+		} else {
+			logger.Error(err, "Failed to delete owned resources that block deletion")
+		}
+	}()
+
+	if input.RoleARN == "" {
+		return DeleteOwnedOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return DeleteOwnedOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.ClusterName == "" {
+		return DeleteOwnedOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", input)
+	}
+
+	for _, resource := range input.Resources {
+		if !resource.IsOwnedBy(input.ClusterName, input.ClusterUID) {
+			logger.Info("Skipped deleting resource that is not owned by the cluster", "resource", resource.String())
+			output.Skipped = append(output.Skipped, resource)
+			continue
+		}
+		if resource.InUse {
+			// detaching would break the instance that uses it, so it is
+			// left to its owner
+			logger.Info("Skipped deleting resource that is in use", "resource", resource.String())
+			output.Skipped = append(output.Skipped, resource)
+			continue
+		}
+
+		logger.Info("Deleting owned resource that blocks deletion", "resource", resource.String())
+		dryRun := dryrun.Enabled(ctx)
+		var deleteResource func() error
+		var action string
+		switch resource.Type {
+		case ResourceTypeNetworkInterface:
+			action = "DeleteNetworkInterface"
+			deleteResource = func() error {
+				ec2Input := ec2.DeleteNetworkInterfaceInput{
+					NetworkInterfaceId: aws.String(resource.Id),
+					DryRun:             aws.Bool(dryRun),
+				}
+				_, err := c.ec2Client.DeleteNetworkInterface(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+				return err
+			}
+		case ResourceTypeSecurityGroup:
+			action = "DeleteSecurityGroup"
+			deleteResource = func() error {
+				ec2Input := ec2.DeleteSecurityGroupInput{
+					GroupId: aws.String(resource.Id),
+					DryRun:  aws.Bool(dryRun),
+				}
+				_, err := c.ec2Client.DeleteSecurityGroup(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+				return err
+			}
+		}
+		if dryRun {
+			err = dryrun.Record(ctx, dryrun.Operation{Action: action, ResourceId: resource.Id}, deleteResource)
+		} else {
+			err = deleteResource()
+		}
+		if errors.Classify(err) == errors.ClassNotFound {
+			logger.Info("Resource not found, nothing to delete", "resource", resource.String())
+		} else if errors.Classify(err) == errors.ClassDependency {
+			// e.g. a network interface that was attached after it was
+			// listed, other resources are still deleted
+			logger.Info("Skipped deleting resource that is in use", "resource", resource.String(), "reason", err.Error())
+			output.Skipped = append(output.Skipped, resource)
+			continue
+		} else if err != nil {
+			return DeleteOwnedOutput{}, microerror.Mask(err)
+		}
+
+		output.Deleted = append(output.Deleted, resource)
+		logger.Info("Deleted owned resource that blocks deletion", "resource", resource.String())
+	}
+
+	return output, nil
+}
@@ -0,0 +1,125 @@
+package blockers
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const defaultSecurityGroupName = "default"
+
+type ListBlockingResourcesInput struct {
+	RoleARN string
+	Region  string
+
+	// VpcId is set when looking for resources that block deletion of the VPC.
+	VpcId string
+
+	// SubnetIds are set when looking for resources that block deletion of
+	// subnets.
+	SubnetIds []string
+}
+
+type ListBlockingResourcesOutput []BlockingResource
+
+func (c *client) List(ctx context.Context, input ListBlockingResourcesInput) (output ListBlockingResourcesOutput, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started listing resources that block deletion")
+	defer func() {
+		if err == nil {
+			logger.Info("Finished listing resources that block deletion", "count", len(output))
+		} else {
+			logger.Error(err, "Failed to list resources that block deletion")
+		}
+	}()
+
+	if input.RoleARN == "" {
+		return ListBlockingResourcesOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return ListBlockingResourcesOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.VpcId == "" && len(input.SubnetIds) == 0 {
+		return ListBlockingResourcesOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.VpcId or %T.SubnetIds must be set", input, input)
+	}
+
+	output = ListBlockingResourcesOutput{}
+
+	//
+	// Network interfaces block deletion of both subnets and VPCs
+	//
+	{
+		var filter ec2Types.Filter
+		if input.VpcId != "" {
+			filter = ec2Types.Filter{Name: aws.String("vpc-id"), Values: []string{input.VpcId}}
+		} else {
+			filter = ec2Types.Filter{Name: aws.String("subnet-id"), Values: input.SubnetIds}
+		}
+		ec2Input := ec2.DescribeNetworkInterfacesInput{
+			Filters: []ec2Types.Filter{filter},
+		}
+		paginator := ec2.NewDescribeNetworkInterfacesPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			if err != nil {
+				return ListBlockingResourcesOutput{}, microerror.Mask(err)
+			}
+
+			for _, networkInterface := range ec2Output.NetworkInterfaces {
+				output = append(output, BlockingResource{
+					Type:        ResourceTypeNetworkInterface,
+					Id:          aws.ToString(networkInterface.NetworkInterfaceId),
+					Owner:       networkInterfaceOwner(networkInterface),
+					Description: aws.ToString(networkInterface.Description),
+					Tags:        tags.ToMap(networkInterface.TagSet),
+					InUse:       networkInterfaceInUse(networkInterface),
+				})
+			}
+		}
+	}
+
+	//
+	// Security groups block deletion of VPCs only. Default security group is
+	// deleted together with the VPC, so it is not blocking anything.
+	//
+	if input.VpcId != "" {
+		ec2Input := ec2.DescribeSecurityGroupsInput{
+			Filters: []ec2Types.Filter{
+				{Name: aws.String("vpc-id"), Values: []string{input.VpcId}},
+			},
+		}
+		paginator := ec2.NewDescribeSecurityGroupsPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			if err != nil {
+				return ListBlockingResourcesOutput{}, microerror.Mask(err)
+			}
+
+			for _, securityGroup := range ec2Output.SecurityGroups {
+				if aws.ToString(securityGroup.GroupName) == defaultSecurityGroupName {
+					continue
+				}
+				output = append(output, BlockingResource{
+					Type:        ResourceTypeSecurityGroup,
+					Id:          aws.ToString(securityGroup.GroupId),
+					Owner:       aws.ToString(securityGroup.OwnerId),
+					Description: aws.ToString(securityGroup.Description),
+					Tags:        tags.ToMap(securityGroup.Tags),
+				})
+			}
+		}
+	}
+
+	for _, resource := range output {
+		logger.Info("Found resource that blocks deletion", "resource", resource.String())
+	}
+
+	return output, nil
+}
@@ -0,0 +1,115 @@
+package blockers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/blockers"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+)
+
+func Test_ListAndDeleteOwned(t *testing.T) {
+	const clusterName = "test"
+	ownedTags := []ec2Types.Tag{
+		{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")},
+	}
+
+	fakeEC2 := awstest.NewEC2().
+		On("DescribeNetworkInterfaces", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeNetworkInterfacesOutput{
+				NetworkInterfaces: []ec2Types.NetworkInterface{
+					{
+						NetworkInterfaceId: aws.String("eni-lambda"),
+						InterfaceType:      ec2Types.NetworkInterfaceTypeLambda,
+						OwnerId:            aws.String("123456789012"),
+						Description:        aws.String("AWS Lambda VPC ENI-test"),
+					},
+					{
+						NetworkInterfaceId: aws.String("eni-attached"),
+						InterfaceType:      ec2Types.NetworkInterfaceTypeInterface,
+						OwnerId:            aws.String("123456789012"),
+						Status:             ec2Types.NetworkInterfaceStatusInUse,
+						TagSet:             ownedTags,
+					},
+					{
+						// attached after it was listed
+						NetworkInterfaceId: aws.String("eni-racing"),
+						InterfaceType:      ec2Types.NetworkInterfaceTypeInterface,
+						OwnerId:            aws.String("123456789012"),
+						TagSet:             ownedTags,
+					},
+					{
+						NetworkInterfaceId: aws.String("eni-owned"),
+						InterfaceType:      ec2Types.NetworkInterfaceTypeInterface,
+						OwnerId:            aws.String("123456789012"),
+						TagSet:             ownedTags,
+					},
+				},
+			}, nil
+		}).
+		On("DescribeSecurityGroups", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeSecurityGroupsOutput{
+				SecurityGroups: []ec2Types.SecurityGroup{
+					{GroupId: aws.String("sg-default"), GroupName: aws.String("default")},
+					{GroupId: aws.String("sg-owned"), GroupName: aws.String("test"), Tags: ownedTags},
+				},
+			}, nil
+		}).
+		On("DeleteNetworkInterface", func(input interface{}) (interface{}, error) {
+			if aws.ToString(input.(*ec2.DeleteNetworkInterfaceInput).NetworkInterfaceId) == "eni-racing" {
+				return nil, &smithy.GenericAPIError{Code: "InvalidNetworkInterface.InUse"}
+			}
+			return &ec2.DeleteNetworkInterfaceOutput{}, nil
+		}).
+		On("DeleteSecurityGroup", func(interface{}) (interface{}, error) {
+			return &ec2.DeleteSecurityGroupOutput{}, nil
+		})
+
+	client, err := blockers.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	resources, err := client.List(ctx, blockers.ListBlockingResourcesInput{
+		RoleARN: awstest.RoleARN,
+		Region:  awstest.Region,
+		VpcId:   "vpc-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 5 {
+		t.Fatalf("expected 5 blocking resources, got %d: %v", len(resources), resources)
+	}
+	if resources[0].Owner != string(ec2Types.NetworkInterfaceTypeLambda) {
+		t.Errorf("expected owner of the first network interface to be lambda, got %s", resources[0].Owner)
+	}
+
+	output, err := client.DeleteOwned(ctx, blockers.DeleteOwnedInput{
+		RoleARN:     awstest.RoleARN,
+		Region:      awstest.Region,
+		ClusterName: clusterName,
+		Resources:   resources,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var skipped []string
+	for _, resource := range output.Skipped {
+		skipped = append(skipped, resource.Id)
+	}
+	if len(output.Deleted) != 2 || strings.Join(skipped, ",") != "eni-lambda,eni-attached,eni-racing" {
+		t.Errorf("expected owned resources to be deleted and lambda and in-use network interfaces to be skipped, got %+v", output)
+	}
+	if fakeEC2.Calls("DeleteNetworkInterface") != 2 || fakeEC2.Calls("DeleteSecurityGroup") != 1 {
+		t.Errorf("expected deletion of two network interfaces and one security group to be tried")
+	}
+}
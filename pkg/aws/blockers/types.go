@@ -0,0 +1,69 @@
+package blockers
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+)
+
+type ResourceType string
+
+// Enum values for ResourceType
+const (
+	ResourceTypeNetworkInterface ResourceType = "network-interface"
+	ResourceTypeSecurityGroup    ResourceType = "security-group"
+)
+
+// BlockingResource is an AWS resource that prevents deletion of a subnet or a
+// VPC.
+type BlockingResource struct {
+	Type ResourceType
+	Id   string
+
+	// Owner is the AWS account or the AWS service that created the resource,
+	// e.g. amazon-elb for network interfaces of load balancers.
+	Owner string
+
+	Description string
+	Tags        map[string]string
+
+	// InUse is set for network interfaces that are attached to an instance
+	// or another resource. They cannot be deleted until they are detached.
+	InUse bool
+}
+
+// String returns a short human-readable representation of the resource, which
+// is used in conditions and events.
+func (r BlockingResource) String() string {
+	s := fmt.Sprintf("%s %s (owner %s", r.Type, r.Id, r.Owner)
+	if r.Description != "" {
+		s += fmt.Sprintf(", %q", r.Description)
+	}
+	if r.InUse {
+		s += ", in use"
+	}
+	return s + ")"
+}
+
+// IsOwnedBy checks if the resource has been created by aws-vpc-operator for
+// the specified cluster, see tags.IsOwnedBy.
+func (r BlockingResource) IsOwnedBy(clusterName, clusterUID string) bool {
+	return tags.IsOwnedBy(r.Tags, clusterName, clusterUID)
+}
+
+func networkInterfaceOwner(networkInterface ec2Types.NetworkInterface) string {
+	if aws.ToBool(networkInterface.RequesterManaged) && networkInterface.RequesterId != nil {
+		return *networkInterface.RequesterId
+	}
+	if networkInterface.InterfaceType != "" && networkInterface.InterfaceType != ec2Types.NetworkInterfaceTypeInterface {
+		return string(networkInterface.InterfaceType)
+	}
+	return aws.ToString(networkInterface.OwnerId)
+}
+
+func networkInterfaceInUse(networkInterface ec2Types.NetworkInterface) bool {
+	return networkInterface.Status == ec2Types.NetworkInterfaceStatusInUse || networkInterface.Attachment != nil
+}
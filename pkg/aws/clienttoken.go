@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ClientToken returns a deterministic idempotency token for an EC2 create
+// call. The token is derived from the UID of the reconciled resource and the
+// purpose of the created cloud resource (e.g. "routetable" and the subnet ID),
+// so that a create call that is retried after a crash or a failed status
+// update returns the already created resource instead of a duplicate.
+//
+// EC2 accepts tokens with up to 64 ASCII characters, which is exactly the
+// length of a hex-encoded SHA-256 hash. An empty string is returned when the
+// UID is not set, in which case no token should be sent.
+func ClientToken(uid types.UID, purpose ...string) string {
+	if uid == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(string(uid) + "/" + strings.Join(purpose, "/")))
+	return hex.EncodeToString(hash[:])
+}
+
+// MaxClientTokenAttempts is the max number of create calls with different
+// client tokens, see NextClientToken.
+const MaxClientTokenAttempts = 5
+
+// InputClientToken returns the client token combined with a hash of the
+// create input, so that the same token is never sent with other parameters,
+// e.g. after the tags or the route tables of a VPC endpoint were changed. EC2
+// would reject such a request with IdempotentParameterMismatch. The input must
+// not contain the client token or the dry-run flag. An empty string is
+// returned when the token is empty.
+func InputClientToken(token string, input interface{}) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+
+	// map keys are sorted by json.Marshal, so equal inputs always have the
+	// same hash
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	hash := sha256.Sum256(append([]byte(token+"/"), encoded...))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// NextClientToken returns the client token that is used instead of the
+// specified one when the create call with it cannot be used anymore, i.e. it
+// returned a resource that has been deleted since, or EC2 rejected it with
+// IdempotentParameterMismatch. The reason is the ID of the deleted resource or
+// the error code. The next token is deterministic as well, so a retried
+// creation still returns the recreated resource.
+func NextClientToken(token, reason string) string {
+	hash := sha256.Sum256([]byte(token + "/" + reason))
+	return hex.EncodeToString(hash[:])
+}
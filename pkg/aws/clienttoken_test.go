@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"testing"
+)
+
+func Test_InputClientToken(t *testing.T) {
+	type input struct {
+		Tags map[string]string
+	}
+
+	token := ClientToken("uid", "routetable", "vpc-1", "subnet-1")
+	first, err := InputClientToken(token, input{Tags: map[string]string{"a": "1", "b": "2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	same, err := InputClientToken(token, input{Tags: map[string]string{"b": "2", "a": "1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := InputClientToken(token, input{Tags: map[string]string{"a": "1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(first) != 64 {
+		t.Errorf("expected token with 64 characters, got %d", len(first))
+	}
+	if first != same {
+		t.Errorf("expected same token for equal inputs, got %s and %s", first, same)
+	}
+	if first == other {
+		t.Errorf("expected different tokens for different inputs")
+	}
+	if next := NextClientToken(first, "rtb-1"); next == first || len(next) != 64 {
+		t.Errorf("expected next token with 64 characters that differs from %s, got %s", first, next)
+	}
+
+	empty, err := InputClientToken("", input{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if empty != "" {
+		t.Errorf("expected empty token without base token, got %s", empty)
+	}
+}
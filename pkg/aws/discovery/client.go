@@ -0,0 +1,38 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// Client finds network resources that are owned by a cluster by their
+// ownership tag, so that they can be deleted even when their IDs are missing
+// from the AWSCluster.
+type Client interface {
+	Discover(ctx context.Context, input DiscoverInput) (DiscoverOutput, error)
+	DiscoverAll(ctx context.Context, input DiscoverAllInput) (DiscoverOutput, error)
+}
+
+func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Client, error) {
+	if ec2Client == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "ec2Client must not be empty")
+	}
+	if assumeRoleClient == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "assumeRoleClient must not be empty")
+	}
+
+	return &client{
+		ec2Client:        ec2Client,
+		assumeRoleClient: assumeRoleClient,
+	}, nil
+}
+
+type client struct {
+	ec2Client        *ec2.Client
+	assumeRoleClient assumerole.Client
+}
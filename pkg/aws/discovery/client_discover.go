@@ -0,0 +1,215 @@
+package discovery
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+type DiscoverInput struct {
+	RoleARN     string
+	Region      string
+	ClusterName string
+
+	// ClusterUID excludes resources of other AWSClusters with the same name,
+	// see tags.IsOwnedBy.
+	ClusterUID string
+}
+
+type DiscoverAllInput struct {
+	RoleARN string
+	Region  string
+}
+
+// Discover returns all VPCs, subnets, route tables and VPC endpoints that are
+// owned by the specified cluster, see tags.IsOwnedBy. VPC endpoints that are already
+// deleted are not returned.
+func (c *client) Discover(ctx context.Context, input DiscoverInput) (output DiscoverOutput, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started discovering resources owned by the cluster")
+	defer func() {
+		if err == nil {
+			logger.Info("Finished discovering resources owned by the cluster", "count", len(output))
+		} else {
+			logger.Error(err, "Failed to discover resources owned by the cluster")
+		}
+	}()
+
+	if input.RoleARN == "" {
+		return DiscoverOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return DiscoverOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.ClusterName == "" {
+		return DiscoverOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", input)
+	}
+
+	clusterName := input.ClusterName
+	output, err = c.discover(ctx, input.RoleARN, input.Region, tags.OwnedByFilter(clusterName), func(resourceTags map[string]string) []string {
+		// the filter only matches the cluster name
+		if !tags.IsOwnedBy(resourceTags, clusterName, input.ClusterUID) {
+			return nil
+		}
+		return []string{clusterName}
+	})
+	if err != nil {
+		return DiscoverOutput{}, microerror.Mask(err)
+	}
+
+	return output, nil
+}
+
+// DiscoverAll returns all VPCs, subnets, route tables and VPC endpoints that
+// are owned by any cluster, e.g. to find resources of clusters that no longer
+// exist. A resource that is owned by several clusters is returned once for
+// each of them.
+func (c *client) DiscoverAll(ctx context.Context, input DiscoverAllInput) (output DiscoverOutput, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started discovering resources owned by any cluster")
+	defer func() {
+		if err == nil {
+			logger.Info("Finished discovering resources owned by any cluster", "count", len(output))
+		} else {
+			logger.Error(err, "Failed to discover resources owned by any cluster")
+		}
+	}()
+
+	if input.RoleARN == "" {
+		return DiscoverOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return DiscoverOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+
+	output, err = c.discover(ctx, input.RoleARN, input.Region, tags.OwnedByAnyFilter(), tags.OwnerClusterNames)
+	if err != nil {
+		return DiscoverOutput{}, microerror.Mask(err)
+	}
+
+	return output, nil
+}
+
+// discover returns the resources that match the filter, once for each of the
+// clusters that ownerClusterNames returns for the tags of the resource.
+func (c *client) discover(ctx context.Context, roleArn, region string, filter ec2Types.Filter, ownerClusterNames func(tags map[string]string) []string) (DiscoverOutput, error) {
+	output := DiscoverOutput{}
+	optFns := c.assumeRoleClient.AssumeRoleFunc(roleArn, region)
+	add := func(resource Resource, ec2Tags []ec2Types.Tag) {
+		for _, clusterName := range ownerClusterNames(tags.ToMap(ec2Tags)) {
+			resource.ClusterName = clusterName
+			output = append(output, resource)
+		}
+	}
+
+	//
+	// VPCs
+	//
+	{
+		ec2Input := ec2.DescribeVpcsInput{
+			Filters: []ec2Types.Filter{filter},
+		}
+		paginator := ec2.NewDescribeVpcsPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, optFns)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			for _, ec2Vpc := range ec2Output.Vpcs {
+				vpcId := aws.ToString(ec2Vpc.VpcId)
+				add(Resource{Type: ResourceTypeVpc, Id: vpcId, VpcId: vpcId}, ec2Vpc.Tags)
+			}
+		}
+	}
+
+	//
+	// Subnets
+	//
+	{
+		ec2Input := ec2.DescribeSubnetsInput{
+			Filters: []ec2Types.Filter{filter},
+		}
+		paginator := ec2.NewDescribeSubnetsPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, optFns)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			for _, ec2Subnet := range ec2Output.Subnets {
+				add(Resource{
+					Type:  ResourceTypeSubnet,
+					Id:    aws.ToString(ec2Subnet.SubnetId),
+					VpcId: aws.ToString(ec2Subnet.VpcId),
+				}, ec2Subnet.Tags)
+			}
+		}
+	}
+
+	//
+	// Route tables
+	//
+	{
+		ec2Input := ec2.DescribeRouteTablesInput{
+			Filters: []ec2Types.Filter{filter},
+		}
+		paginator := ec2.NewDescribeRouteTablesPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, optFns)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			for _, ec2RouteTable := range ec2Output.RouteTables {
+				add(Resource{
+					Type:  ResourceTypeRouteTable,
+					Id:    aws.ToString(ec2RouteTable.RouteTableId),
+					VpcId: aws.ToString(ec2RouteTable.VpcId),
+				}, ec2RouteTable.Tags)
+			}
+		}
+	}
+
+	//
+	// VPC endpoints
+	//
+	{
+		ec2Input := ec2.DescribeVpcEndpointsInput{
+			Filters: []ec2Types.Filter{filter},
+		}
+		paginator := ec2.NewDescribeVpcEndpointsPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, optFns)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			for _, ec2VpcEndpoint := range ec2Output.VpcEndpoints {
+				if isDeletedVpcEndpoint(ec2VpcEndpoint.State) {
+					continue
+				}
+				add(Resource{
+					Type:        ResourceTypeVpcEndpoint,
+					Id:          aws.ToString(ec2VpcEndpoint.VpcEndpointId),
+					VpcId:       aws.ToString(ec2VpcEndpoint.VpcId),
+					ServiceName: aws.ToString(ec2VpcEndpoint.ServiceName),
+				}, ec2VpcEndpoint.Tags)
+			}
+		}
+	}
+
+	return output, nil
+}
+
+// isDeletedVpcEndpoint checks if the VPC endpoint is deleted, as deleted VPC
+// endpoints are still returned for a while. VPC endpoints that are being
+// deleted are returned, so that their deletion is awaited.
+func isDeletedVpcEndpoint(state ec2Types.State) bool {
+	// EC2 returns VPC endpoint states in lower case
+	return strings.EqualFold(string(state), string(ec2Types.StateDeleted))
+}
@@ -0,0 +1,166 @@
+package discovery_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/discovery"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+)
+
+func Test_Discover(t *testing.T) {
+	const (
+		clusterName = "test"
+		clusterUID  = "uid-test"
+	)
+	ownedBy := func(uid string) []ec2Types.Tag {
+		ec2Tags := []ec2Types.Tag{{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")}}
+		if uid != "" {
+			ec2Tags = append(ec2Tags, ec2Types.Tag{Key: aws.String(tags.NameAWSClusterUID), Value: aws.String(uid)})
+		}
+		return ec2Tags
+	}
+
+	fakeEC2 := awstest.NewEC2().
+		On("DescribeVpcs", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcsOutput{
+				Vpcs: []ec2Types.Vpc{
+					{VpcId: aws.String("vpc-owned"), Tags: ownedBy(clusterUID)},
+					// a cluster with the same name in another namespace or
+					// management cluster
+					{VpcId: aws.String("vpc-same-name"), Tags: ownedBy("uid-other")},
+				},
+			}, nil
+		}).
+		On("DescribeSubnets", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeSubnetsOutput{
+				Subnets: []ec2Types.Subnet{
+					{SubnetId: aws.String("subnet-b"), VpcId: aws.String("vpc-owned"), Tags: ownedBy(clusterUID)},
+					// created before the UID tag was added
+					{SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-other"), Tags: ownedBy("")},
+					{SubnetId: aws.String("subnet-same-name"), VpcId: aws.String("vpc-same-name"), Tags: ownedBy("uid-other")},
+				},
+			}, nil
+		}).
+		On("DescribeRouteTables", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeRouteTablesOutput{
+				RouteTables: []ec2Types.RouteTable{{RouteTableId: aws.String("rtb-1"), VpcId: aws.String("vpc-owned"), Tags: ownedBy(clusterUID)}},
+			}, nil
+		}).
+		On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcEndpointsOutput{
+				VpcEndpoints: []ec2Types.VpcEndpoint{
+					{VpcEndpointId: aws.String("vpce-1"), VpcId: aws.String("vpc-owned"), State: ec2Types.StateAvailable, Tags: ownedBy(clusterUID)},
+					{VpcEndpointId: aws.String("vpce-deleted"), VpcId: aws.String("vpc-deleted"), State: "deleted", Tags: ownedBy(clusterUID)},
+				},
+			}, nil
+		})
+
+	client, err := discovery.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := client.Discover(context.Background(), discovery.DiscoverInput{
+		RoleARN:     awstest.RoleARN,
+		Region:      awstest.Region,
+		ClusterName: clusterName,
+		ClusterUID:  clusterUID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ids := output.Ids(discovery.ResourceTypeSubnet); !reflect.DeepEqual(ids, []string{"subnet-a", "subnet-b"}) {
+		t.Errorf("unexpected subnets %v", ids)
+	}
+	if ids := output.Ids(discovery.ResourceTypeVpcEndpoint); !reflect.DeepEqual(ids, []string{"vpce-1"}) {
+		t.Errorf("unexpected VPC endpoints %v", ids)
+	}
+	if ids := output.VpcIds(); !reflect.DeepEqual(ids, []string{"vpc-other", "vpc-owned"}) {
+		t.Errorf("unexpected VPCs %v", ids)
+	}
+
+	for _, call := range fakeEC2.AllCalls() {
+		if call.Operation != "DescribeSubnets" {
+			continue
+		}
+		filters := call.Input.(*ec2.DescribeSubnetsInput).Filters
+		if len(filters) != 1 || aws.ToString(filters[0].Name) != "tag:"+tags.NameAWSProviderPrefix+clusterName {
+			t.Errorf("expected subnets to be filtered by ownership tag, got %v", filters)
+		}
+	}
+}
+
+func Test_DiscoverAll(t *testing.T) {
+	ownedBy := func(clusterNames ...string) []ec2Types.Tag {
+		ec2Tags := []ec2Types.Tag{{Key: aws.String(tags.NameAWSRole), Value: aws.String("common")}}
+		for _, clusterName := range clusterNames {
+			ec2Tags = append(ec2Tags, ec2Types.Tag{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")})
+		}
+		return ec2Tags
+	}
+
+	fakeEC2 := awstest.NewEC2().
+		On("DescribeVpcs", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcsOutput{
+				Vpcs: []ec2Types.Vpc{{VpcId: aws.String("vpc-a"), Tags: ownedBy("a")}},
+			}, nil
+		}).
+		On("DescribeSubnets", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeSubnetsOutput{
+				Subnets: []ec2Types.Subnet{{SubnetId: aws.String("subnet-ab"), VpcId: aws.String("vpc-a"), Tags: ownedBy("a", "b")}},
+			}, nil
+		}).
+		On("DescribeRouteTables", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeRouteTablesOutput{
+				RouteTables: []ec2Types.RouteTable{{RouteTableId: aws.String("rtb-role-only"), VpcId: aws.String("vpc-a"), Tags: ownedBy()}},
+			}, nil
+		}).
+		On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcEndpointsOutput{
+				VpcEndpoints: []ec2Types.VpcEndpoint{
+					{VpcEndpointId: aws.String("vpce-b"), VpcId: aws.String("vpc-a"), ServiceName: aws.String("com.amazonaws.eu-west-1.s3"), State: ec2Types.StateAvailable, Tags: ownedBy("b")},
+				},
+			}, nil
+		})
+
+	client, err := discovery.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := client.DiscoverAll(context.Background(), discovery.DiscoverAllInput{
+		RoleARN: awstest.RoleARN,
+		Region:  awstest.Region,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := discovery.DiscoverOutput{
+		{Type: discovery.ResourceTypeVpc, Id: "vpc-a", VpcId: "vpc-a", ClusterName: "a"},
+		{Type: discovery.ResourceTypeSubnet, Id: "subnet-ab", VpcId: "vpc-a", ClusterName: "a"},
+		{Type: discovery.ResourceTypeSubnet, Id: "subnet-ab", VpcId: "vpc-a", ClusterName: "b"},
+		{Type: discovery.ResourceTypeVpcEndpoint, Id: "vpce-b", VpcId: "vpc-a", ClusterName: "b", ServiceName: "com.amazonaws.eu-west-1.s3"},
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("expected %v, got %v", expected, output)
+	}
+
+	for _, call := range fakeEC2.AllCalls() {
+		if call.Operation != "DescribeVpcs" {
+			continue
+		}
+		filters := call.Input.(*ec2.DescribeVpcsInput).Filters
+		if len(filters) != 1 || aws.ToString(filters[0].Name) != "tag-key" {
+			t.Errorf("expected VPCs to be filtered by tag key, got %v", filters)
+		}
+	}
+}
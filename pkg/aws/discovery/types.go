@@ -0,0 +1,76 @@
+package discovery
+
+import (
+	"sort"
+)
+
+type ResourceType string
+
+const (
+	ResourceTypeVpc         ResourceType = "vpc"
+	ResourceTypeSubnet      ResourceType = "subnet"
+	ResourceTypeRouteTable  ResourceType = "route-table"
+	ResourceTypeVpcEndpoint ResourceType = "vpc-endpoint"
+)
+
+// Resource is a network resource owned by the cluster.
+type Resource struct {
+	Type ResourceType
+	Id   string
+
+	// VpcId is the ID of the VPC in which the resource is. For VPCs it is
+	// the same as Id.
+	VpcId string
+
+	// ClusterName is the name of the cluster that owns the resource.
+	ClusterName string
+
+	// ServiceName is the name of the AWS service of a VPC endpoint, e.g.
+	// com.amazonaws.eu-west-1.s3.
+	ServiceName string
+}
+
+type DiscoverOutput []Resource
+
+// Ids returns sorted IDs of all discovered resources of the specified type.
+func (o DiscoverOutput) Ids(resourceType ResourceType) []string {
+	var ids []string
+	for _, resource := range o {
+		if resource.Type == resourceType {
+			ids = append(ids, resource.Id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// IdsByVpc returns sorted IDs of all discovered resources of the specified
+// type by the ID of the VPC in which they are.
+func (o DiscoverOutput) IdsByVpc(resourceType ResourceType) map[string][]string {
+	ids := map[string][]string{}
+	for _, resource := range o {
+		if resource.Type == resourceType {
+			ids[resource.VpcId] = append(ids[resource.VpcId], resource.Id)
+		}
+	}
+	for _, vpcIds := range ids {
+		sort.Strings(vpcIds)
+	}
+	return ids
+}
+
+// VpcIds returns sorted IDs of all VPCs that are owned by the cluster or that
+// contain any resource owned by the cluster.
+func (o DiscoverOutput) VpcIds() []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, resource := range o {
+		if resource.VpcId == "" || seen[resource.VpcId] {
+			continue
+		}
+		seen[resource.VpcId] = true
+		ids = append(ids, resource.VpcId)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,157 @@
+// Package dryrun records the AWS API calls that would change AWS resources,
+// instead of making them. Reconciliations in dry-run mode run with a Plan in
+// their context, and the mutating methods of the AWS clients add the
+// operations that they would execute to that plan.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// plannedIdSuffix is the suffix of IDs of resources that would be created.
+const plannedIdSuffix = "-planned"
+
+// Operation is an AWS API call that would change AWS resources.
+type Operation struct {
+	// Action is the name of the EC2 API operation, e.g. CreateVpc.
+	Action string `json:"action"`
+
+	// ResourceId is the ID of the changed resource. Resources that would be
+	// created have a placeholder ID, see PlannedId.
+	ResourceId string `json:"resourceId,omitempty"`
+
+	// Details describe the change, e.g. the CIDR block of a new subnet.
+	Details string `json:"details,omitempty"`
+
+	// PermissionDenied is set when the EC2 API call made with DryRun failed
+	// because of missing IAM permissions, so the operation would fail.
+	PermissionDenied bool `json:"permissionDenied,omitempty"`
+}
+
+func (o Operation) String() string {
+	var b strings.Builder
+	b.WriteString(o.Action)
+	if o.ResourceId != "" {
+		b.WriteString(" ")
+		b.WriteString(o.ResourceId)
+	}
+	if o.Details != "" {
+		fmt.Fprintf(&b, " (%s)", o.Details)
+	}
+	if o.PermissionDenied {
+		b.WriteString(" [permission denied]")
+	}
+	return b.String()
+}
+
+// Plan collects the operations of a reconciliation in dry-run mode. It is
+// safe for concurrent use.
+type Plan struct {
+	mutex      sync.Mutex
+	operations []Operation
+}
+
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Operations returns the recorded operations in the order in which they
+// would be executed.
+func (p *Plan) Operations() []Operation {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]Operation(nil), p.operations...)
+}
+
+// Summary returns the number of operations and the first maxOperations of
+// them, e.g. for condition messages and events.
+func (p *Plan) Summary(maxOperations int) string {
+	operations := p.Operations()
+	if len(operations) == 0 {
+		return "No changes planned"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d changes planned: ", len(operations))
+	for i, operation := range operations {
+		if i == maxOperations {
+			fmt.Fprintf(&b, ", and %d more", len(operations)-maxOperations)
+			break
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(operation.String())
+	}
+	return b.String()
+}
+
+func (p *Plan) add(operation Operation) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.operations = append(p.operations, operation)
+}
+
+type planContextKey struct{}
+
+// NewContext returns a context in which AWS clients record mutating
+// operations in the plan instead of executing them.
+func NewContext(ctx context.Context, plan *Plan) context.Context {
+	return context.WithValue(ctx, planContextKey{}, plan)
+}
+
+// FromContext returns the plan of the context, or nil when the context is not
+// in dry-run mode.
+func FromContext(ctx context.Context) *Plan {
+	plan, _ := ctx.Value(planContextKey{}).(*Plan)
+	return plan
+}
+
+// Enabled checks if the context is in dry-run mode.
+func Enabled(ctx context.Context) bool {
+	return FromContext(ctx) != nil
+}
+
+// Record adds the operation to the plan of the context. The check function,
+// when not nil, makes the EC2 API call with DryRun set, so that AWS verifies
+// the IAM permissions and the parameters of the call without executing it.
+// Operations that are denied are recorded with PermissionDenied set, other
+// errors of the check are returned.
+func Record(ctx context.Context, operation Operation, check func() error) error {
+	plan := FromContext(ctx)
+	if plan == nil {
+		return microerror.Maskf(errors.InvalidConfigError, "context is not in dry-run mode")
+	}
+
+	if check != nil {
+		err := check()
+		if errors.IsPermissionDenied(err) {
+			operation.PermissionDenied = true
+		} else if err != nil && !errors.IsDryRunOperation(err) {
+			return microerror.Mask(err)
+		}
+	}
+
+	plan.add(operation)
+	return nil
+}
+
+// PlannedId returns the placeholder ID of a resource that would be created,
+// e.g. vpc-planned.
+func PlannedId(prefix string) string {
+	return prefix + plannedIdSuffix
+}
+
+// IsPlannedId checks if the ID is a placeholder of a resource that would be
+// created. Such resources do not exist, so EC2 API calls for them cannot be
+// checked with DryRun.
+func IsPlannedId(id string) bool {
+	return strings.HasSuffix(id, plannedIdSuffix)
+}
@@ -0,0 +1,103 @@
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_Record(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		checkError               error
+		expectedPermissionDenied bool
+		expectError              bool
+	}{
+		{name: "case 0: operation without check"},
+		{name: "case 1: permitted operation", checkError: &smithy.GenericAPIError{Code: "DryRunOperation"}},
+		{name: "case 2: denied operation", checkError: &smithy.GenericAPIError{Code: "UnauthorizedOperation"}, expectedPermissionDenied: true},
+		{name: "case 3: invalid operation", checkError: &smithy.GenericAPIError{Code: "InvalidParameterValue"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := NewPlan()
+			ctx := NewContext(context.Background(), plan)
+
+			var check func() error
+			if tc.checkError != nil {
+				check = func() error { return tc.checkError }
+			}
+			err := Record(ctx, Operation{Action: "DeleteVpc", ResourceId: "vpc-1"}, check)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				if len(plan.Operations()) != 0 {
+					t.Fatalf("expected no operations, got %v", plan.Operations())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			operations := plan.Operations()
+			if len(operations) != 1 {
+				t.Fatalf("expected 1 operation, got %v", operations)
+			}
+			if operations[0].PermissionDenied != tc.expectedPermissionDenied {
+				t.Errorf("expected permission denied %t, got %t", tc.expectedPermissionDenied, operations[0].PermissionDenied)
+			}
+		})
+	}
+
+	if err := Record(context.Background(), Operation{Action: "DeleteVpc"}, nil); err == nil {
+		t.Errorf("expected error for context that is not in dry-run mode")
+	}
+}
+
+func Test_Plan_Summary(t *testing.T) {
+	plan := NewPlan()
+	if summary := plan.Summary(2); summary != "No changes planned" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	plan.add(Operation{Action: "CreateVpc", ResourceId: PlannedId("vpc"), Details: "cidr-block 10.0.0.0/16"})
+	plan.add(Operation{Action: "CreateTags", ResourceId: "subnet-1", PermissionDenied: true})
+	plan.add(Operation{Action: "DeleteTags", ResourceId: "subnet-2"})
+
+	expected := "3 changes planned: CreateVpc vpc-planned (cidr-block 10.0.0.0/16), CreateTags subnet-1 [permission denied], and 1 more"
+	if summary := plan.Summary(2); summary != expected {
+		t.Errorf("expected summary %q, got %q", expected, summary)
+	}
+}
+
+func Test_Plans_ServeHTTP(t *testing.T) {
+	plan := NewPlan()
+	plan.add(Operation{Action: "DeleteVpc", ResourceId: "vpc-1"})
+
+	plans := NewPlans()
+	plans.Set(types.NamespacedName{Namespace: "org-a", Name: "a"}, plan)
+	plans.Set(types.NamespacedName{Namespace: "org-b", Name: "b"}, NewPlan())
+
+	recorder := httptest.NewRecorder()
+	plans.ServeHTTP(recorder, httptest.NewRequest("GET", PlansPath+"?namespace=org-a", nil))
+
+	var served []ClusterPlan
+	if err := json.Unmarshal(recorder.Body.Bytes(), &served); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(served) != 1 || served[0].Name != "a" || len(served[0].Operations) != 1 {
+		t.Fatalf("expected plan of cluster org-a/a, got %v", served)
+	}
+
+	plans.Delete(types.NamespacedName{Namespace: "org-a", Name: "a"})
+	if len(plans.List()) != 1 {
+		t.Errorf("expected 1 plan after deletion, got %v", plans.List())
+	}
+}
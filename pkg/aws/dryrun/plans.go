@@ -0,0 +1,91 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PlansPath is the path of the HTTP endpoint that serves the latest plans.
+const PlansPath = "/debug/plan"
+
+// ClusterPlan is the latest plan of a cluster.
+type ClusterPlan struct {
+	Namespace  string      `json:"namespace"`
+	Name       string      `json:"name"`
+	Time       time.Time   `json:"time"`
+	Operations []Operation `json:"operations"`
+}
+
+// Plans keeps the latest plan of each cluster, and serves them over HTTP as
+// JSON. It is safe for concurrent use.
+type Plans struct {
+	mutex sync.RWMutex
+	plans map[types.NamespacedName]ClusterPlan
+}
+
+func NewPlans() *Plans {
+	return &Plans{
+		plans: map[types.NamespacedName]ClusterPlan{},
+	}
+}
+
+// Set replaces the plan of the cluster.
+func (p *Plans) Set(cluster types.NamespacedName, plan *Plan) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.plans[cluster] = ClusterPlan{
+		Namespace:  cluster.Namespace,
+		Name:       cluster.Name,
+		Time:       time.Now().UTC(),
+		Operations: plan.Operations(),
+	}
+}
+
+// Delete removes the plan of the cluster, e.g. when the cluster is gone.
+func (p *Plans) Delete(cluster types.NamespacedName) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.plans, cluster)
+}
+
+// List returns the plans of all clusters, sorted by namespace and name.
+func (p *Plans) List() []ClusterPlan {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	plans := make([]ClusterPlan, 0, len(p.plans))
+	for _, plan := range p.plans {
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].Namespace != plans[j].Namespace {
+			return plans[i].Namespace < plans[j].Namespace
+		}
+		return plans[i].Name < plans[j].Name
+	})
+	return plans
+}
+
+// ServeHTTP serves the plans as JSON. The namespace and name query parameters
+// select the plans of matching clusters.
+func (p *Plans) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	plans := []ClusterPlan{}
+	for _, plan := range p.List() {
+		if (namespace == "" || plan.Namespace == namespace) && (name == "" || plan.Name == name) {
+			plans = append(plans, plan)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(plans)
+}
@@ -0,0 +1,122 @@
+// Package naming renders Name tags of AWS resources from text/template
+// templates, e.g. "{{ .ClusterName }}-rt-private-{{ .AvailabilityZone }}".
+package naming
+
+import (
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	DefaultSubnet      = "{{ .ClusterName }}-subnet-{{ .Role }}-{{ .AvailabilityZone }}"
+	DefaultRouteTable  = "{{ .ClusterName }}-rt-private-{{ .AvailabilityZone }}"
+	DefaultVpcEndpoint = "{{ .ClusterName }}-vpc-endpoint-{{ .Service }}-{{ .Region }}"
+)
+
+// Params are the fields that can be used in name templates. Fields that do
+// not apply to the resource are empty.
+type Params struct {
+	ClusterName      string
+	Region           string
+	AvailabilityZone string
+	Role             string
+	Service          string
+}
+
+// Template is a parsed name template. The zero value renders an empty name,
+// so callers use Or to fall back to a default template.
+type Template struct {
+	text     string
+	template *template.Template
+}
+
+// Parse parses a name template. Templates that reference unknown fields are
+// rejected, so that invalid templates are detected when they are loaded, and
+// not when resources are created.
+func Parse(text string) (Template, error) {
+	t, err := template.New("name").Parse(text)
+	if err != nil {
+		return Template{}, microerror.Maskf(errors.InvalidConfigError, "invalid name template %q: %s", text, err)
+	}
+
+	tmpl := Template{text: text, template: t}
+	_, err = tmpl.Execute(Params{})
+	if err != nil {
+		return Template{}, microerror.Maskf(errors.InvalidConfigError, "invalid name template %q: %s", text, err)
+	}
+
+	return tmpl, nil
+}
+
+// MustParse is like Parse, but it panics when the template is invalid. It is
+// used for default templates.
+func MustParse(text string) Template {
+	tmpl, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// Or returns the template, or the fallback template when the template is
+// not set.
+func (t Template) Or(fallback Template) Template {
+	if t.template == nil {
+		return fallback
+	}
+	return t
+}
+
+// Execute renders the name for the specified params.
+func (t Template) Execute(params Params) (string, error) {
+	if t.template == nil {
+		return "", nil
+	}
+
+	var name strings.Builder
+	err := t.template.Execute(&name, params)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+	return name.String(), nil
+}
+
+// Name renders the name for the specified params. Templates are validated
+// when they are parsed, so rendering does not fail for valid params, and an
+// empty name is returned if it does.
+func (t Template) Name(params Params) string {
+	name, _ := t.Execute(params)
+	return name
+}
+
+// String returns the template text.
+func (t Template) String() string {
+	return t.text
+}
+
+// MarshalJSON encodes the template as its text.
+func (t Template) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.text)
+}
+
+// UnmarshalJSON parses the template from its text, so that templates in
+// configuration files are validated when they are loaded.
+func (t *Template) UnmarshalJSON(data []byte) error {
+	var text string
+	err := json.Unmarshal(data, &text)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	parsed, err := Parse(text)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	*t = parsed
+	return nil
+}
@@ -0,0 +1,69 @@
+package permissions
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// CacheTTL is how long the result of a check is reused for the same role and
+// region, so that permissions are not checked in every reconciliation. Fixed
+// IAM policies are therefore noticed after at most CacheTTL.
+const CacheTTL = 10 * time.Minute
+
+// Client checks if the IAM role that is assumed for a cluster is allowed to
+// make the EC2 API calls that aws-vpc-operator needs, with EC2 DryRun calls
+// that do not change any resources.
+type Client interface {
+	Check(ctx context.Context, input CheckInput) (CheckOutput, error)
+}
+
+func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Client, error) {
+	if ec2Client == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "ec2Client must not be empty")
+	}
+	if assumeRoleClient == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "assumeRoleClient must not be empty")
+	}
+
+	snapshotClient, err := snapshot.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return &client{
+		ec2Client:        ec2Client,
+		assumeRoleClient: assumeRoleClient,
+		snapshotClient:   snapshotClient,
+		cache:            map[cacheKey]cacheEntry{},
+		now:              time.Now,
+	}, nil
+}
+
+type client struct {
+	ec2Client        *ec2.Client
+	assumeRoleClient assumerole.Client
+	snapshotClient   snapshot.Client
+
+	mutex sync.Mutex
+	cache map[cacheKey]cacheEntry
+	now   func() time.Time
+}
+
+type cacheKey struct {
+	roleArn string
+	region  string
+}
+
+// cacheEntry has the results of checked actions, true for allowed actions.
+type cacheEntry struct {
+	allowed map[Action]bool
+	expires time.Time
+}
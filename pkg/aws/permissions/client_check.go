@@ -0,0 +1,313 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// Placeholder IDs of resources that do not exist, for actions that are not
+// restricted to resources of aws-vpc-operator. DryRun calls are authorized
+// before their parameters are validated, so the calls fail with
+// DryRunOperation or UnauthorizedOperation before EC2 finds out that the
+// resources do not exist.
+const (
+	placeholderVpcId           = "vpc-00000000000000000"
+	placeholderSubnetId        = "subnet-00000000000000000"
+	placeholderRouteTableId    = "rtb-00000000000000000"
+	placeholderAssociationId   = "rtbassoc-00000000000000000"
+	placeholderTagKey          = "aws-vpc-operator-permissions-check"
+	placeholderVpcCidrBlock    = "10.0.0.0/16"
+	placeholderSubnetCidrBlock = "10.0.0.0/24"
+)
+
+type CheckInput struct {
+	RoleARN string
+	Region  string
+
+	// VpcId is the VPC of the cluster. Actions that are only allowed on
+	// resources created by aws-vpc-operator are checked against resources in
+	// the VPC that have its role tag. It is empty when the VPC does not exist
+	// yet.
+	VpcId string
+
+	// Actions that are checked, e.g. ManagedNetworkActions.
+	Actions []Action
+}
+
+type CheckOutput struct {
+	// Missing are the sorted actions that the role is not allowed to make.
+	Missing []Action
+
+	// Unchecked are the sorted actions that are only allowed on resources
+	// created by aws-vpc-operator, and that were not checked because no such
+	// resource exists yet. They are checked in a later call, once the
+	// resources are created.
+	Unchecked []Action
+}
+
+// Check makes an EC2 DryRun call for each action with the assumed role. The
+// results are cached for CacheTTL per role and region.
+func (c *client) Check(ctx context.Context, input CheckInput) (output CheckOutput, err error) {
+	logger := log.FromContext(ctx)
+
+	if input.RoleARN == "" {
+		return CheckOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return CheckOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if len(input.Actions) == 0 {
+		return CheckOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Actions must not be empty", input)
+	}
+
+	key := cacheKey{roleArn: input.RoleARN, region: input.Region}
+	allowed := c.cached(key)
+
+	var unchecked []Action
+	for _, action := range input.Actions {
+		if _, ok := allowed[action]; !ok {
+			unchecked = append(unchecked, action)
+		}
+	}
+	if len(unchecked) > 0 {
+		logger.Info("Started checking IAM permissions", "actions", len(unchecked))
+		defer func() {
+			if err == nil {
+				logger.Info("Finished checking IAM permissions", "missing", output.Missing)
+			} else {
+				logger.Error(err, "Failed to check IAM permissions")
+			}
+		}()
+
+		resourceIds, err := c.ownedResourceIds(ctx, input, unchecked)
+		if err != nil {
+			return CheckOutput{}, microerror.Mask(err)
+		}
+
+		checked := map[Action]bool{}
+		for _, action := range unchecked {
+			var resourceId string
+			if resourceTypes, ok := ownedResourceActions[action]; ok {
+				resourceId = firstResourceId(resourceIds, resourceTypes)
+				if resourceId == "" {
+					// results are not cached, so the action is checked once
+					// the resource exists
+					output.Unchecked = append(output.Unchecked, action)
+					continue
+				}
+			}
+
+			checked[action], err = c.check(ctx, input.RoleARN, input.Region, action, resourceId)
+			if err != nil {
+				return CheckOutput{}, microerror.Mask(err)
+			}
+		}
+		allowed = c.store(key, checked)
+	}
+
+	for _, action := range input.Actions {
+		if checked, ok := allowed[action]; ok && !checked {
+			output.Missing = append(output.Missing, action)
+		}
+	}
+	sort.Slice(output.Missing, func(i, j int) bool { return output.Missing[i] < output.Missing[j] })
+	sort.Slice(output.Unchecked, func(i, j int) bool { return output.Unchecked[i] < output.Unchecked[j] })
+
+	return output, nil
+}
+
+// ownedResourceIds returns the ID of one resource of each type in the VPC that
+// has the role tag of aws-vpc-operator, when any of the actions is only
+// allowed on such resources.
+func (c *client) ownedResourceIds(ctx context.Context, input CheckInput, actions []Action) (map[ec2Types.ResourceType]string, error) {
+	resourceIds := map[ec2Types.ResourceType]string{}
+	needed := false
+	for _, action := range actions {
+		if _, ok := ownedResourceActions[action]; ok {
+			needed = true
+		}
+	}
+	if !needed || input.VpcId == "" {
+		return resourceIds, nil
+	}
+
+	snapshotInput := snapshot.Input{
+		RoleARN: input.RoleARN,
+		Region:  input.Region,
+		VpcId:   input.VpcId,
+	}
+	vpcs, err := c.snapshotClient.Vpcs(ctx, snapshotInput)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	for _, vpc := range vpcs {
+		if hasRoleTag(vpc.Tags) {
+			resourceIds[ec2Types.ResourceTypeVpc] = aws.ToString(vpc.VpcId)
+			break
+		}
+	}
+	subnets, err := c.snapshotClient.Subnets(ctx, snapshotInput)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	for _, subnet := range subnets {
+		if hasRoleTag(subnet.Tags) {
+			resourceIds[ec2Types.ResourceTypeSubnet] = aws.ToString(subnet.SubnetId)
+			break
+		}
+	}
+	routeTables, err := c.snapshotClient.RouteTables(ctx, snapshotInput)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	for _, routeTable := range routeTables {
+		if hasRoleTag(routeTable.Tags) {
+			resourceIds[ec2Types.ResourceTypeRouteTable] = aws.ToString(routeTable.RouteTableId)
+			break
+		}
+	}
+	vpcEndpoints, err := c.snapshotClient.VpcEndpoints(ctx, snapshotInput)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	for _, vpcEndpoint := range vpcEndpoints {
+		if hasRoleTag(vpcEndpoint.Tags) {
+			resourceIds[ec2Types.ResourceTypeVpcEndpoint] = aws.ToString(vpcEndpoint.VpcEndpointId)
+			break
+		}
+	}
+
+	return resourceIds, nil
+}
+
+func hasRoleTag(ec2Tags []ec2Types.Tag) bool {
+	_, ok := tags.ToMap(ec2Tags)[tags.NameAWSRole]
+	return ok
+}
+
+// firstResourceId returns the ID of the first resource type that has a
+// resource, or an empty string.
+func firstResourceId(resourceIds map[ec2Types.ResourceType]string, resourceTypes []ec2Types.ResourceType) string {
+	for _, resourceType := range resourceTypes {
+		if resourceId := resourceIds[resourceType]; resourceId != "" {
+			return resourceId
+		}
+	}
+	return ""
+}
+
+// check makes the DryRun call of the action, and checks if it is allowed.
+// Created resources are tagged with the role tag of aws-vpc-operator, like
+// the resources that it creates. Actions that are only allowed on resources
+// with that tag are made on the resource with the specified ID.
+func (c *client) check(ctx context.Context, roleArn, region string, action Action, resourceId string) (bool, error) {
+	optFns := c.assumeRoleClient.AssumeRoleFunc(roleArn, region)
+	dryRun := aws.Bool(true)
+	tagSpecifications := func(resourceType ec2Types.ResourceType) []ec2Types.TagSpecification {
+		return []ec2Types.TagSpecification{
+			tags.BuildParamsToTagSpecification(resourceType, map[string]string{tags.NameAWSRole: capa.CommonRoleTagValue}),
+		}
+	}
+
+	var err error
+	switch action {
+	case ActionCreateVpc:
+		_, err = c.ec2Client.CreateVpc(ctx, &ec2.CreateVpcInput{DryRun: dryRun, CidrBlock: aws.String(placeholderVpcCidrBlock), TagSpecifications: tagSpecifications(ec2Types.ResourceTypeVpc)}, optFns)
+	case ActionCreateSubnet:
+		_, err = c.ec2Client.CreateSubnet(ctx, &ec2.CreateSubnetInput{DryRun: dryRun, VpcId: aws.String(placeholderVpcId), CidrBlock: aws.String(placeholderSubnetCidrBlock), TagSpecifications: tagSpecifications(ec2Types.ResourceTypeSubnet)}, optFns)
+	case ActionCreateRouteTable:
+		_, err = c.ec2Client.CreateRouteTable(ctx, &ec2.CreateRouteTableInput{DryRun: dryRun, VpcId: aws.String(placeholderVpcId), TagSpecifications: tagSpecifications(ec2Types.ResourceTypeRouteTable)}, optFns)
+	case ActionAssociateRouteTable:
+		_, err = c.ec2Client.AssociateRouteTable(ctx, &ec2.AssociateRouteTableInput{DryRun: dryRun, RouteTableId: aws.String(placeholderRouteTableId), SubnetId: aws.String(placeholderSubnetId)}, optFns)
+	case ActionCreateVpcEndpoint:
+		_, err = c.ec2Client.CreateVpcEndpoint(ctx, &ec2.CreateVpcEndpointInput{
+			DryRun:            dryRun,
+			VpcId:             aws.String(placeholderVpcId),
+			ServiceName:       aws.String(fmt.Sprintf("com.amazonaws.%s.s3", region)),
+			VpcEndpointType:   ec2Types.VpcEndpointTypeGateway,
+			TagSpecifications: tagSpecifications(ec2Types.ResourceTypeVpcEndpoint),
+		}, optFns)
+	case ActionCreateTags:
+		_, err = c.ec2Client.CreateTags(ctx, &ec2.CreateTagsInput{DryRun: dryRun, Resources: []string{resourceId}, Tags: []ec2Types.Tag{{Key: aws.String(placeholderTagKey), Value: aws.String("")}}}, optFns)
+	case ActionDeleteVpc:
+		_, err = c.ec2Client.DeleteVpc(ctx, &ec2.DeleteVpcInput{DryRun: dryRun, VpcId: aws.String(resourceId)}, optFns)
+	case ActionDeleteSubnet:
+		_, err = c.ec2Client.DeleteSubnet(ctx, &ec2.DeleteSubnetInput{DryRun: dryRun, SubnetId: aws.String(resourceId)}, optFns)
+	case ActionDeleteRouteTable:
+		_, err = c.ec2Client.DeleteRouteTable(ctx, &ec2.DeleteRouteTableInput{DryRun: dryRun, RouteTableId: aws.String(resourceId)}, optFns)
+	case ActionDisassociateRouteTable:
+		_, err = c.ec2Client.DisassociateRouteTable(ctx, &ec2.DisassociateRouteTableInput{DryRun: dryRun, AssociationId: aws.String(placeholderAssociationId)}, optFns)
+	case ActionDeleteVpcEndpoints:
+		_, err = c.ec2Client.DeleteVpcEndpoints(ctx, &ec2.DeleteVpcEndpointsInput{DryRun: dryRun, VpcEndpointIds: []string{resourceId}}, optFns)
+	case ActionDeleteTags:
+		_, err = c.ec2Client.DeleteTags(ctx, &ec2.DeleteTagsInput{DryRun: dryRun, Resources: []string{resourceId}, Tags: []ec2Types.Tag{{Key: aws.String(placeholderTagKey)}}}, optFns)
+	default:
+		return false, microerror.Maskf(errors.InvalidConfigError, "unknown action %q", action)
+	}
+
+	switch {
+	case errors.IsDryRunOperation(err):
+		return true, nil
+	case errors.IsPermissionDenied(err):
+		return false, nil
+	case err == nil:
+		// not expected with DryRun, but the call was obviously allowed
+		return true, nil
+	}
+
+	// any other error does not tell if the action is allowed
+	return false, microerror.Mask(err)
+}
+
+// cached returns the results of the role and region that have not expired.
+func (c *client) cached(key cacheKey) map[Action]bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.cache[key]
+	if !ok || c.now().After(entry.expires) {
+		return map[Action]bool{}
+	}
+	return copyResults(entry.allowed)
+}
+
+// store adds the results to the cache of the role and region, and returns all
+// cached results of the role and region.
+func (c *client) store(key cacheKey, checked map[Action]bool) map[Action]bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.cache[key]
+	if !ok || c.now().After(entry.expires) {
+		entry = cacheEntry{
+			allowed: map[Action]bool{},
+			expires: c.now().Add(CacheTTL),
+		}
+	}
+	for action, allowed := range checked {
+		entry.allowed[action] = allowed
+	}
+	c.cache[key] = entry
+
+	return copyResults(entry.allowed)
+}
+
+func copyResults(results map[Action]bool) map[Action]bool {
+	copied := make(map[Action]bool, len(results))
+	for action, allowed := range results {
+		copied[action] = allowed
+	}
+	return copied
+}
@@ -0,0 +1,331 @@
+package permissions
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/iampolicy"
+)
+
+func Test_Check(t *testing.T) {
+	apiError := func(code string) func(interface{}) (interface{}, error) {
+		return func(interface{}) (interface{}, error) {
+			return nil, &smithy.GenericAPIError{Code: code}
+		}
+	}
+
+	fakeEC2 := awstest.NewEC2()
+	for _, operation := range []string{"CreateSubnet", "CreateRouteTable", "AssociateRouteTable", "CreateVpcEndpoint", "DeleteSubnet", "DeleteRouteTable", "DisassociateRouteTable", "DeleteVpcEndpoints", "DeleteTags"} {
+		fakeEC2.On(operation, apiError("DryRunOperation"))
+	}
+	fakeEC2.
+		On("CreateVpc", func(input interface{}) (interface{}, error) {
+			if !aws.ToBool(input.(*ec2.CreateVpcInput).DryRun) {
+				t.Fatalf("expected CreateVpc to be called with DryRun")
+			}
+			return nil, &smithy.GenericAPIError{Code: "UnauthorizedOperation"}
+		}).
+		On("CreateTags", apiError("UnauthorizedOperation")).
+		On("DeleteVpc", func(input interface{}) (interface{}, error) {
+			// actions on owned resources are checked against them
+			if vpcId := aws.ToString(input.(*ec2.DeleteVpcInput).VpcId); vpcId != "vpc-1" {
+				t.Fatalf("expected DeleteVpc to be called for owned VPC vpc-1, got %s", vpcId)
+			}
+			return nil, &smithy.GenericAPIError{Code: "DryRunOperation"}
+		})
+	onOwnedResources(fakeEC2)
+
+	c, err := NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	c.(*client).now = func() time.Time { return now }
+
+	input := CheckInput{RoleARN: awstest.RoleARN, Region: awstest.Region, VpcId: "vpc-1", Actions: ManagedNetworkActions}
+	expectedMissing := []Action{ActionCreateTags, ActionCreateVpc}
+
+	output, err := c.Check(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(output.Missing, expectedMissing) {
+		t.Fatalf("expected missing actions %v, got %v", expectedMissing, output.Missing)
+	}
+	calls := len(dryRunCalls(fakeEC2))
+	if calls != len(ManagedNetworkActions) {
+		t.Fatalf("expected %d EC2 DryRun calls, got %d", len(ManagedNetworkActions), calls)
+	}
+
+	// results are cached per role and region
+	output, err = c.Check(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(output.Missing, expectedMissing) {
+		t.Fatalf("expected cached missing actions %v, got %v", expectedMissing, output.Missing)
+	}
+	if len(dryRunCalls(fakeEC2)) != calls {
+		t.Fatalf("expected cached results to be used, got %d new EC2 calls", len(dryRunCalls(fakeEC2))-calls)
+	}
+
+	// and checked again when they expire
+	now = now.Add(CacheTTL + time.Second)
+	fakeEC2.On("CreateTags", apiError("DryRunOperation"))
+	output, err = c.Check(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(output.Missing, []Action{ActionCreateVpc}) {
+		t.Fatalf("expected missing actions %v after cache expired, got %v", []Action{ActionCreateVpc}, output.Missing)
+	}
+
+	// throttling does not tell anything about permissions
+	fakeEC2.On("CreateVpc", apiError("RequestLimitExceeded"))
+	_, err = c.Check(context.Background(), CheckInput{RoleARN: "arn:aws:iam::123456789012:role/other", Region: awstest.Region, Actions: ManagedNetworkActions})
+	if err == nil {
+		t.Fatalf("expected error when EC2 API is throttled")
+	}
+
+	// neither does any other error
+	fakeEC2.On("CreateVpc", apiError("InvalidParameterValue"))
+	_, err = c.Check(context.Background(), CheckInput{RoleARN: "arn:aws:iam::123456789012:role/another", Region: awstest.Region, Actions: ManagedNetworkActions})
+	if err == nil {
+		t.Fatalf("expected error when EC2 API returns an unexpected error")
+	}
+}
+
+// onOwnedResources registers handlers that return a VPC, subnet, route table
+// and VPC endpoint with the role tag of aws-vpc-operator.
+func onOwnedResources(fakeEC2 *awstest.EC2) {
+	roleTags := []ec2Types.Tag{{Key: aws.String(tags.NameAWSRole), Value: aws.String("common")}}
+	fakeEC2.
+		On("DescribeVpcs", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcsOutput{Vpcs: []ec2Types.Vpc{{VpcId: aws.String("vpc-1"), Tags: roleTags}}}, nil
+		}).
+		On("DescribeSubnets", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeSubnetsOutput{Subnets: []ec2Types.Subnet{{SubnetId: aws.String("subnet-1"), Tags: roleTags}}}, nil
+		}).
+		On("DescribeRouteTables", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeRouteTablesOutput{RouteTables: []ec2Types.RouteTable{{RouteTableId: aws.String("rtb-1"), Tags: roleTags}}}, nil
+		}).
+		On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcEndpointsOutput{VpcEndpoints: []ec2Types.VpcEndpoint{{VpcEndpointId: aws.String("vpce-1"), Tags: roleTags}}}, nil
+		})
+}
+
+// dryRunCalls returns the recorded calls without the calls that describe
+// resources.
+func dryRunCalls(fakeEC2 *awstest.EC2) []awstest.Call {
+	var calls []awstest.Call
+	for _, call := range fakeEC2.AllCalls() {
+		if !strings.HasPrefix(call.Operation, "Describe") {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Test_Check_GeneratedPolicy checks the DryRun calls against the IAM policy
+// of the iam-policy subcommand, so that a role with that policy passes the
+// check.
+func Test_Check_GeneratedPolicy(t *testing.T) {
+	testCases := []struct {
+		name string
+		// vpcId is empty before the VPC is created
+		vpcId                 string
+		actions               []Action
+		unmanagedNetworksOnly bool
+		expectedMissing       []Action
+		expectedUnchecked     []Action
+	}{
+		{
+			name:              "case 0: new cluster",
+			actions:           ManagedNetworkActions,
+			expectedUnchecked: []Action{ActionCreateTags, ActionDeleteRouteTable, ActionDeleteSubnet, ActionDeleteTags, ActionDeleteVpc, ActionDeleteVpcEndpoints},
+		},
+		{
+			name:    "case 1: cluster with owned resources",
+			vpcId:   "vpc-1",
+			actions: ManagedNetworkActions,
+		},
+		{
+			name:    "case 2: unmanaged network",
+			vpcId:   "vpc-1",
+			actions: UnmanagedNetworkActions,
+			// VPC endpoints are the only resources created for unmanaged
+			// networks
+			unmanagedNetworksOnly: true,
+		},
+		{
+			name:                  "case 3: policy for unmanaged networks only",
+			vpcId:                 "vpc-1",
+			actions:               ManagedNetworkActions,
+			unmanagedNetworksOnly: true,
+			expectedMissing:       []Action{ActionAssociateRouteTable, ActionCreateRouteTable, ActionCreateSubnet, ActionCreateVpc, ActionDeleteRouteTable, ActionDeleteSubnet, ActionDeleteVpc, ActionDisassociateRouteTable},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := config.Default()
+			policy, err := iampolicy.Generate(iampolicy.Options{Config: &operatorConfig, UnmanagedNetworksOnly: tc.unmanagedNetworksOnly})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fakeEC2 := awstest.NewEC2()
+			onOwnedResources(fakeEC2)
+			ownedResourceIds := map[string]bool{"vpc-1": true, "subnet-1": true, "rtb-1": true, "vpce-1": true}
+			for _, action := range ManagedNetworkActions {
+				action := action
+				fakeEC2.On(strings.TrimPrefix(string(action), "ec2:"), func(input interface{}) (interface{}, error) {
+					if allowedByPolicy(policy, string(action), input, ownedResourceIds) {
+						return nil, &smithy.GenericAPIError{Code: "DryRunOperation"}
+					}
+					return nil, &smithy.GenericAPIError{Code: "UnauthorizedOperation"}
+				})
+			}
+
+			c, err := NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			output, err := c.Check(context.Background(), CheckInput{RoleARN: awstest.RoleARN, Region: awstest.Region, VpcId: tc.vpcId, Actions: tc.actions})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(output.Missing, tc.expectedMissing) {
+				t.Errorf("expected missing actions %v, got %v", tc.expectedMissing, output.Missing)
+			}
+			if !reflect.DeepEqual(output.Unchecked, tc.expectedUnchecked) {
+				t.Errorf("expected unchecked actions %v, got %v", tc.expectedUnchecked, output.Unchecked)
+			}
+		})
+	}
+}
+
+// allowedByPolicy evaluates the statements of the policy for an EC2 call with
+// the specified input. The call is allowed when each of its resources is
+// allowed by a statement. Only the conditions used by iampolicy.Generate are
+// supported, the request tags are only set for the created resource, and the
+// role tag is assumed on all owned resources.
+func allowedByPolicy(policy iampolicy.Policy, action string, input interface{}, ownedResourceIds map[string]bool) bool {
+	type resource struct {
+		resourceType string
+		id           string
+		created      bool
+	}
+	var resources []resource
+	var tagSpecifications []ec2Types.TagSpecification
+	switch input := input.(type) {
+	case *ec2.CreateVpcInput:
+		tagSpecifications = input.TagSpecifications
+		resources = []resource{{resourceType: "vpc", created: true}}
+	case *ec2.CreateSubnetInput:
+		tagSpecifications = input.TagSpecifications
+		resources = []resource{{resourceType: "subnet", created: true}, {resourceType: "vpc", id: aws.ToString(input.VpcId)}}
+	case *ec2.CreateRouteTableInput:
+		tagSpecifications = input.TagSpecifications
+		resources = []resource{{resourceType: "route-table", created: true}, {resourceType: "vpc", id: aws.ToString(input.VpcId)}}
+	case *ec2.CreateVpcEndpointInput:
+		tagSpecifications = input.TagSpecifications
+		resources = []resource{{resourceType: "vpc-endpoint", created: true}, {resourceType: "vpc", id: aws.ToString(input.VpcId)}}
+		for _, routeTableId := range input.RouteTableIds {
+			resources = append(resources, resource{resourceType: "route-table", id: routeTableId})
+		}
+		for _, subnetId := range input.SubnetIds {
+			resources = append(resources, resource{resourceType: "subnet", id: subnetId})
+		}
+	case *ec2.CreateTagsInput:
+		resources = []resource{{resourceType: resourceTypeOf(input.Resources[0]), id: input.Resources[0]}}
+	case *ec2.DeleteTagsInput:
+		resources = []resource{{resourceType: resourceTypeOf(input.Resources[0]), id: input.Resources[0]}}
+	case *ec2.DeleteVpcInput:
+		resources = []resource{{resourceType: "vpc", id: aws.ToString(input.VpcId)}}
+	case *ec2.DeleteSubnetInput:
+		resources = []resource{{resourceType: "subnet", id: aws.ToString(input.SubnetId)}}
+	case *ec2.DeleteRouteTableInput:
+		resources = []resource{{resourceType: "route-table", id: aws.ToString(input.RouteTableId)}}
+	case *ec2.DeleteVpcEndpointsInput:
+		resources = []resource{{resourceType: "vpc-endpoint", id: input.VpcEndpointIds[0]}}
+	default:
+		resources = []resource{{}}
+	}
+	requestTags := map[string]bool{}
+	for _, tagSpecification := range tagSpecifications {
+		for _, tag := range tagSpecification.Tags {
+			requestTags[aws.ToString(tag.Key)] = true
+		}
+	}
+
+	allowedResource := func(r resource) bool {
+		for _, statement := range policy.Statement {
+			hasAction := false
+			for _, statementAction := range statement.Action {
+				hasAction = hasAction || statementAction == action
+			}
+			hasResource := false
+			for _, statementResource := range statement.Resource {
+				hasResource = hasResource || statementResource == "*" || statementResource == "arn:aws:ec2:*:*:"+r.resourceType+"/*"
+			}
+			if !hasAction || !hasResource {
+				continue
+			}
+
+			allowed := true
+			for operator, condition := range statement.Condition {
+				for key, value := range condition {
+					switch {
+					case operator == "Null" && strings.HasPrefix(key, "aws:RequestTag/"):
+						allowed = allowed && (r.created && requestTags[strings.TrimPrefix(key, "aws:RequestTag/")]) == (value == "false")
+					case operator == "Null" && strings.HasPrefix(key, "aws:ResourceTag/"):
+						allowed = allowed && ownedResourceIds[r.id] == (value == "false")
+					default:
+						// e.g. ec2:CreateAction, which is only set when tags are
+						// created by a create action
+						allowed = false
+					}
+				}
+			}
+			if allowed {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, r := range resources {
+		if !allowedResource(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceTypeOf returns the IAM resource type of the EC2 resource ID.
+func resourceTypeOf(id string) string {
+	prefix, _, _ := strings.Cut(id, "-")
+	switch prefix {
+	case "subnet":
+		return "subnet"
+	case "rtb":
+		return "route-table"
+	case "vpce":
+		return "vpc-endpoint"
+	default:
+		return prefix
+	}
+}
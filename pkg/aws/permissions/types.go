@@ -0,0 +1,63 @@
+package permissions
+
+import (
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Action is an IAM action that aws-vpc-operator needs, e.g. ec2:CreateVpc.
+type Action string
+
+// Enum values for Action
+const (
+	ActionCreateVpc              Action = "ec2:CreateVpc"
+	ActionCreateSubnet           Action = "ec2:CreateSubnet"
+	ActionCreateRouteTable       Action = "ec2:CreateRouteTable"
+	ActionAssociateRouteTable    Action = "ec2:AssociateRouteTable"
+	ActionCreateVpcEndpoint      Action = "ec2:CreateVpcEndpoint"
+	ActionCreateTags             Action = "ec2:CreateTags"
+	ActionDeleteVpc              Action = "ec2:DeleteVpc"
+	ActionDeleteSubnet           Action = "ec2:DeleteSubnet"
+	ActionDeleteRouteTable       Action = "ec2:DeleteRouteTable"
+	ActionDisassociateRouteTable Action = "ec2:DisassociateRouteTable"
+	ActionDeleteVpcEndpoints     Action = "ec2:DeleteVpcEndpoints"
+	ActionDeleteTags             Action = "ec2:DeleteTags"
+)
+
+// ManagedNetworkActions are needed to create, tag and delete the VPC,
+// subnets, route tables and VPC endpoints of a cluster.
+var ManagedNetworkActions = []Action{
+	ActionCreateVpc,
+	ActionCreateSubnet,
+	ActionCreateRouteTable,
+	ActionAssociateRouteTable,
+	ActionCreateVpcEndpoint,
+	ActionCreateTags,
+	ActionDeleteVpc,
+	ActionDeleteSubnet,
+	ActionDeleteRouteTable,
+	ActionDisassociateRouteTable,
+	ActionDeleteVpcEndpoints,
+	ActionDeleteTags,
+}
+
+// UnmanagedNetworkActions are needed for unmanaged networks, in which only VPC
+// endpoints are created, tagged and deleted.
+var UnmanagedNetworkActions = []Action{
+	ActionCreateVpcEndpoint,
+	ActionCreateTags,
+	ActionDeleteVpcEndpoints,
+	ActionDeleteTags,
+}
+
+// ownedResourceActions are only allowed on resources with the role tag of
+// aws-vpc-operator, tags.NameAWSRole, by the IAM policy of the iam-policy
+// subcommand. They are checked against such resources, of the resource types
+// listed for each action.
+var ownedResourceActions = map[Action][]ec2Types.ResourceType{
+	ActionCreateTags:         {ec2Types.ResourceTypeVpc, ec2Types.ResourceTypeSubnet, ec2Types.ResourceTypeRouteTable, ec2Types.ResourceTypeVpcEndpoint},
+	ActionDeleteVpc:          {ec2Types.ResourceTypeVpc},
+	ActionDeleteSubnet:       {ec2Types.ResourceTypeSubnet},
+	ActionDeleteRouteTable:   {ec2Types.ResourceTypeRouteTable},
+	ActionDeleteVpcEndpoints: {ec2Types.ResourceTypeVpcEndpoint},
+	ActionDeleteTags:         {ec2Types.ResourceTypeVpc, ec2Types.ResourceTypeSubnet, ec2Types.ResourceTypeRouteTable, ec2Types.ResourceTypeVpcEndpoint},
+}
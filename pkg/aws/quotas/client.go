@@ -0,0 +1,50 @@
+package quotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// LimitsAPI returns the value of a service quota, identified by its service
+// and quota code, that applies to the account of the role in the region. It
+// is implemented with the Service Quotas API by NewLimitsClient, and by fakes
+// in tests.
+type LimitsAPI interface {
+	Limit(ctx context.Context, roleArn, region, serviceCode, quotaCode string) (int, error)
+}
+
+// Client checks if the service quotas leave enough headroom for the resources
+// that will be created for a cluster, by comparing the current usage in the
+// region with the limits.
+type Client interface {
+	Check(ctx context.Context, input CheckInput) (CheckOutput, error)
+}
+
+func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client, limits LimitsAPI) (Client, error) {
+	if ec2Client == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "ec2Client must not be empty")
+	}
+	if assumeRoleClient == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "assumeRoleClient must not be empty")
+	}
+	if limits == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "limits must not be empty")
+	}
+
+	return &client{
+		ec2Client:        ec2Client,
+		assumeRoleClient: assumeRoleClient,
+		limits:           limits,
+	}, nil
+}
+
+type client struct {
+	ec2Client        *ec2.Client
+	assumeRoleClient assumerole.Client
+	limits           LimitsAPI
+}
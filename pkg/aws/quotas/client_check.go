@@ -0,0 +1,200 @@
+package quotas
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+type CheckInput struct {
+	RoleARN string
+	Region  string
+
+	// Vpcs is the number of VPCs that will be created.
+	Vpcs int
+
+	// VpcId is the VPC in which the gateway VPC endpoints are created. It is
+	// empty when the VPC does not exist yet.
+	VpcId string
+
+	// GatewayVpcEndpointServices are the service names of the gateway VPC
+	// endpoints of the VPC, e.g. com.amazonaws.eu-west-1.s3. Endpoints that
+	// already exist in the VPC are not created again, so they do not need
+	// headroom.
+	GatewayVpcEndpointServices []string
+
+	// ElasticIPs is the number of Elastic IP addresses that will be
+	// allocated.
+	ElasticIPs int
+}
+
+type CheckOutput struct {
+	// Exceeded are the quotas that do not leave enough headroom, in the order
+	// in which the resources are created.
+	Exceeded []Exceeded
+}
+
+// Exceeded is a quota whose limit is lower than the current usage plus the
+// resources that will be created.
+type Exceeded struct {
+	Quota    Quota
+	Usage    int
+	Required int
+	Limit    int
+}
+
+// Check compares the current usage in the region with the limits of the
+// quotas of the resources that will be created. It does not call any APIs
+// for quotas of which no resources will be created.
+func (c *client) Check(ctx context.Context, input CheckInput) (output CheckOutput, err error) {
+	logger := log.FromContext(ctx)
+
+	if input.RoleARN == "" {
+		return CheckOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return CheckOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+
+	logger.Info("Started checking service quotas")
+	defer func() {
+		if err == nil {
+			logger.Info("Finished checking service quotas", "exceeded", len(output.Exceeded))
+		} else {
+			logger.Error(err, "Failed to check service quotas")
+		}
+	}()
+
+	optFns := c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region)
+	output = CheckOutput{}
+
+	if input.Vpcs > 0 {
+		usage, err := c.countVpcs(ctx, optFns)
+		if err != nil {
+			return CheckOutput{}, microerror.Mask(err)
+		}
+		err = c.compare(ctx, input, VpcsPerRegion, usage, input.Vpcs, &output)
+		if err != nil {
+			return CheckOutput{}, microerror.Mask(err)
+		}
+	}
+
+	if len(input.GatewayVpcEndpointServices) > 0 {
+		usage, existing, err := c.countGatewayVpcEndpoints(ctx, input.VpcId, optFns)
+		if err != nil {
+			return CheckOutput{}, microerror.Mask(err)
+		}
+		required := 0
+		for _, service := range input.GatewayVpcEndpointServices {
+			if !existing[service] {
+				required++
+			}
+		}
+		if required > 0 {
+			err = c.compare(ctx, input, GatewayVpcEndpointsPerRegion, usage, required, &output)
+			if err != nil {
+				return CheckOutput{}, microerror.Mask(err)
+			}
+		}
+	}
+
+	if input.ElasticIPs > 0 {
+		usage, err := c.countElasticIPs(ctx, optFns)
+		if err != nil {
+			return CheckOutput{}, microerror.Mask(err)
+		}
+		err = c.compare(ctx, input, ElasticIPs, usage, input.ElasticIPs, &output)
+		if err != nil {
+			return CheckOutput{}, microerror.Mask(err)
+		}
+	}
+
+	return output, nil
+}
+
+// compare gets the limit of the quota, and adds the quota to the output when
+// the usage plus the required resources exceed it.
+func (c *client) compare(ctx context.Context, input CheckInput, quota Quota, usage, required int, output *CheckOutput) error {
+	limit, err := c.limits.Limit(ctx, input.RoleARN, input.Region, quota.ServiceCode, quota.QuotaCode)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if usage+required > limit {
+		output.Exceeded = append(output.Exceeded, Exceeded{
+			Quota:    quota,
+			Usage:    usage,
+			Required: required,
+			Limit:    limit,
+		})
+	}
+	return nil
+}
+
+func (c *client) countVpcs(ctx context.Context, optFns func(*ec2.Options)) (int, error) {
+	count := 0
+	paginator := ec2.NewDescribeVpcsPaginator(c.ec2Client, &ec2.DescribeVpcsInput{})
+	for paginator.HasMorePages() {
+		ec2Output, err := paginator.NextPage(ctx, optFns)
+		if err != nil {
+			return 0, microerror.Mask(err)
+		}
+		count += len(ec2Output.Vpcs)
+	}
+	return count, nil
+}
+
+// countGatewayVpcEndpoints returns the number of gateway VPC endpoints in the
+// region, and the service names of the endpoints that exist in the VPC.
+func (c *client) countGatewayVpcEndpoints(ctx context.Context, vpcId string, optFns func(*ec2.Options)) (int, map[string]bool, error) {
+	count := 0
+	existing := map[string]bool{}
+	ec2Input := ec2.DescribeVpcEndpointsInput{
+		Filters: []ec2Types.Filter{
+			{
+				Name:   aws.String("vpc-endpoint-type"),
+				Values: []string{string(ec2Types.VpcEndpointTypeGateway)},
+			},
+		},
+	}
+	paginator := ec2.NewDescribeVpcEndpointsPaginator(c.ec2Client, &ec2Input)
+	for paginator.HasMorePages() {
+		ec2Output, err := paginator.NextPage(ctx, optFns)
+		if err != nil {
+			return 0, nil, microerror.Mask(err)
+		}
+		for _, ec2VpcEndpoint := range ec2Output.VpcEndpoints {
+			// EC2 returns VPC endpoint states in lower case
+			if strings.EqualFold(string(ec2VpcEndpoint.State), string(ec2Types.StateDeleted)) {
+				continue
+			}
+			count++
+			if vpcId != "" && aws.ToString(ec2VpcEndpoint.VpcId) == vpcId {
+				existing[aws.ToString(ec2VpcEndpoint.ServiceName)] = true
+			}
+		}
+	}
+	return count, existing, nil
+}
+
+func (c *client) countElasticIPs(ctx context.Context, optFns func(*ec2.Options)) (int, error) {
+	ec2Output, err := c.ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{
+		Filters: []ec2Types.Filter{
+			{
+				Name:   aws.String("domain"),
+				Values: []string{string(ec2Types.DomainTypeVpc)},
+			},
+		},
+	}, optFns)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+	return len(ec2Output.Addresses), nil
+}
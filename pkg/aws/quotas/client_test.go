@@ -0,0 +1,126 @@
+package quotas_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/quotas"
+)
+
+func Test_Check(t *testing.T) {
+	const (
+		vpcId = "vpc-1"
+		s3    = "com.amazonaws.eu-west-1.s3"
+	)
+
+	testCases := []struct {
+		name             string
+		input            quotas.CheckInput
+		vpcs             int
+		vpcEndpoints     []ec2Types.VpcEndpoint
+		addresses        int
+		expectedExceeded []quotas.Exceeded
+		expectedCalls    int
+	}{
+		{
+			name:          "case 0: enough headroom for a new VPC",
+			input:         quotas.CheckInput{Vpcs: 1},
+			vpcs:          4,
+			expectedCalls: 1,
+		},
+		{
+			name:  "case 1: VPC quota is reached",
+			input: quotas.CheckInput{Vpcs: 1},
+			vpcs:  5,
+			expectedExceeded: []quotas.Exceeded{
+				{Quota: quotas.VpcsPerRegion, Usage: 5, Required: 1, Limit: 5},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:  "case 2: existing gateway VPC endpoints need no headroom",
+			input: quotas.CheckInput{VpcId: vpcId, GatewayVpcEndpointServices: []string{s3}},
+			vpcEndpoints: []ec2Types.VpcEndpoint{
+				{VpcId: aws.String(vpcId), ServiceName: aws.String(s3), State: ec2Types.StateAvailable},
+				{VpcId: aws.String("vpc-2"), ServiceName: aws.String(s3), State: ec2Types.StateAvailable},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:  "case 3: gateway VPC endpoint quota is reached, deleted endpoints are not counted",
+			input: quotas.CheckInput{GatewayVpcEndpointServices: []string{s3}},
+			vpcEndpoints: []ec2Types.VpcEndpoint{
+				{VpcId: aws.String("vpc-2"), ServiceName: aws.String(s3), State: ec2Types.StateAvailable},
+				{VpcId: aws.String("vpc-3"), ServiceName: aws.String(s3), State: ec2Types.StateAvailable},
+				{VpcId: aws.String("vpc-4"), ServiceName: aws.String(s3), State: ec2Types.StateDeleted},
+				{VpcId: aws.String("vpc-5"), ServiceName: aws.String(s3), State: "deleted"},
+			},
+			expectedExceeded: []quotas.Exceeded{
+				{Quota: quotas.GatewayVpcEndpointsPerRegion, Usage: 2, Required: 1, Limit: 2},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:      "case 4: VPC and Elastic IP quotas are reached",
+			input:     quotas.CheckInput{Vpcs: 1, ElasticIPs: 2},
+			vpcs:      5,
+			addresses: 4,
+			expectedExceeded: []quotas.Exceeded{
+				{Quota: quotas.VpcsPerRegion, Usage: 5, Required: 1, Limit: 5},
+				{Quota: quotas.ElasticIPs, Usage: 4, Required: 2, Limit: 5},
+			},
+			expectedCalls: 2,
+		},
+		{
+			name:          "case 5: nothing is created",
+			input:         quotas.CheckInput{VpcId: vpcId},
+			expectedCalls: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeEC2 := awstest.NewEC2().
+				On("DescribeVpcs", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeVpcsOutput{Vpcs: make([]ec2Types.Vpc, tc.vpcs)}, nil
+				}).
+				On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeVpcEndpointsOutput{VpcEndpoints: tc.vpcEndpoints}, nil
+				}).
+				On("DescribeAddresses", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeAddressesOutput{Addresses: make([]ec2Types.Address, tc.addresses)}, nil
+				})
+			limits := awstest.Limits{
+				quotas.VpcsPerRegion.QuotaCode:                5,
+				quotas.GatewayVpcEndpointsPerRegion.QuotaCode: 2,
+				quotas.ElasticIPs.QuotaCode:                   5,
+			}
+
+			client, err := quotas.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{}, limits)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			input := tc.input
+			input.RoleARN = awstest.RoleARN
+			input.Region = awstest.Region
+			output, err := client.Check(context.Background(), input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(output.Exceeded, tc.expectedExceeded) {
+				t.Errorf("expected exceeded quotas %v, got %v", tc.expectedExceeded, output.Exceeded)
+			}
+			if calls := len(fakeEC2.AllCalls()); calls != tc.expectedCalls {
+				t.Errorf("expected %d EC2 calls, got %d", tc.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package quotas
+
+import (
+	"context"
+	goerrors "errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	servicequotasTypes "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// LimitsCacheTTL is how long a limit is reused for the same role, region and
+// quota, so that the Service Quotas API is not called in every
+// reconciliation. Increased quotas are therefore noticed after at most
+// LimitsCacheTTL.
+const LimitsCacheTTL = 10 * time.Minute
+
+func NewLimitsClient(serviceQuotasClient *servicequotas.Client, assumeRoleClient assumerole.Client) (LimitsAPI, error) {
+	if serviceQuotasClient == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "serviceQuotasClient must not be empty")
+	}
+	if assumeRoleClient == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "assumeRoleClient must not be empty")
+	}
+
+	return &limitsClient{
+		serviceQuotasClient: serviceQuotasClient,
+		assumeRoleClient:    assumeRoleClient,
+		cache:               map[limitsCacheKey]limitsCacheEntry{},
+		now:                 time.Now,
+	}, nil
+}
+
+type limitsClient struct {
+	serviceQuotasClient *servicequotas.Client
+	assumeRoleClient    assumerole.Client
+
+	mutex sync.Mutex
+	cache map[limitsCacheKey]limitsCacheEntry
+	now   func() time.Time
+}
+
+type limitsCacheKey struct {
+	roleArn   string
+	region    string
+	quotaCode string
+}
+
+type limitsCacheEntry struct {
+	limit   int
+	expires time.Time
+}
+
+// Limit returns the applied value of the quota, or its default value when the
+// quota has never been increased in the account.
+func (c *limitsClient) Limit(ctx context.Context, roleArn, region, serviceCode, quotaCode string) (int, error) {
+	key := limitsCacheKey{roleArn: roleArn, region: region, quotaCode: quotaCode}
+
+	c.mutex.Lock()
+	entry, ok := c.cache[key]
+	c.mutex.Unlock()
+	if ok && !c.now().After(entry.expires) {
+		return entry.limit, nil
+	}
+
+	optFns := c.assumeRoleClient.AssumeRoleServiceQuotasFunc(roleArn, region)
+	var quota *servicequotasTypes.ServiceQuota
+	output, err := c.serviceQuotasClient.GetServiceQuota(ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	}, optFns)
+	var noSuchResource *servicequotasTypes.NoSuchResourceException
+	if goerrors.As(err, &noSuchResource) {
+		defaultOutput, err := c.serviceQuotasClient.GetAWSDefaultServiceQuota(ctx, &servicequotas.GetAWSDefaultServiceQuotaInput{
+			ServiceCode: aws.String(serviceCode),
+			QuotaCode:   aws.String(quotaCode),
+		}, optFns)
+		if err != nil {
+			return 0, microerror.Mask(err)
+		}
+		quota = defaultOutput.Quota
+	} else if err != nil {
+		return 0, microerror.Mask(err)
+	} else {
+		quota = output.Quota
+	}
+	if quota == nil || quota.Value == nil {
+		return 0, microerror.Maskf(errors.InvalidConfigError, "service quota %s of service %s has no value", quotaCode, serviceCode)
+	}
+
+	limit := int(math.Floor(aws.ToFloat64(quota.Value)))
+	c.mutex.Lock()
+	c.cache[key] = limitsCacheEntry{limit: limit, expires: c.now().Add(LimitsCacheTTL)}
+	c.mutex.Unlock()
+
+	return limit, nil
+}
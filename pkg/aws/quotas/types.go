@@ -0,0 +1,28 @@
+package quotas
+
+// Quota is an AWS service quota that limits the resources that
+// aws-vpc-operator creates for a cluster.
+type Quota struct {
+	// ServiceCode and QuotaCode identify the quota in the Service Quotas API.
+	ServiceCode string
+	QuotaCode   string
+
+	// Name is the name of the quota in the Service Quotas console.
+	Name string
+}
+
+func (q Quota) String() string {
+	return q.Name
+}
+
+var (
+	// VpcsPerRegion limits the VPCs in a region.
+	VpcsPerRegion = Quota{ServiceCode: "vpc", QuotaCode: "L-F678F1CE", Name: "VPCs per Region"}
+
+	// GatewayVpcEndpointsPerRegion limits the gateway VPC endpoints in a
+	// region.
+	GatewayVpcEndpointsPerRegion = Quota{ServiceCode: "vpc", QuotaCode: "L-1B52E74A", Name: "Gateway VPC endpoints per Region"}
+
+	// ElasticIPs limits the Elastic IP addresses in a region.
+	ElasticIPs = Quota{ServiceCode: "ec2", QuotaCode: "L-0263D0A3", Name: "EC2-VPC Elastic IPs"}
+)
@@ -0,0 +1,175 @@
+// Package ratelimit limits the AWS API calls that aws-vpc-operator makes for
+// all clusters, with a token bucket per AWS account and region, so that many
+// concurrent reconciliations, e.g. after a restart, do not exhaust the EC2 API
+// request limits of an account.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/giantswarm/microerror"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// minFactor is the lowest fraction of the configured rate to which the
+	// rate is reduced by throttling errors.
+	minFactor = 0.1
+
+	// recoveryStep is the fraction of the configured rate that is added back
+	// after each call that was not throttled.
+	recoveryStep = 0.01
+)
+
+// Bucket configures the token bucket of an AWS account and region.
+type Bucket struct {
+	// QPS is the rate at which calls are allowed, in calls per second. Calls
+	// are not limited when it is zero.
+	QPS float64 `json:"qps"`
+
+	// Burst is the number of calls that are allowed at once.
+	Burst int `json:"burst"`
+}
+
+// Settings configures the token buckets of all AWS accounts.
+type Settings struct {
+	// Default is the bucket of accounts that are not in Accounts.
+	Default Bucket `json:"default"`
+
+	// Accounts are the buckets of AWS account IDs with other limits, e.g.
+	// because their EC2 API request limits were increased.
+	Accounts map[string]Bucket `json:"accounts,omitempty"`
+}
+
+// Bucket returns the bucket of the account.
+func (s Settings) Bucket(account string) Bucket {
+	if bucket, ok := s.Accounts[account]; ok {
+		return bucket
+	}
+	return s.Default
+}
+
+// Limiter has a token bucket for each AWS account and region. The rate of a
+// bucket is reduced when calls are throttled by AWS anyway, e.g. because other
+// tools use the same account, and it recovers gradually with each call that is
+// not throttled.
+type Limiter struct {
+	settings func() Settings
+
+	mutex   sync.Mutex
+	buckets map[key]*bucket
+}
+
+type key struct {
+	account string
+	region  string
+}
+
+type bucket struct {
+	limiter *rate.Limiter
+	config  Bucket
+
+	// factor is the fraction of the configured rate that is currently
+	// allowed, between minFactor and 1.
+	factor float64
+}
+
+// NewLimiter returns a limiter that uses the current settings, so that they
+// can be changed without a restart, e.g. from the operator configuration.
+func NewLimiter(settings func() Settings) *Limiter {
+	return &Limiter{
+		settings: settings,
+		buckets:  map[key]*bucket{},
+	}
+}
+
+// Wait blocks until a call with the role in the region is allowed, or the
+// context is done.
+func (l *Limiter) Wait(ctx context.Context, roleArn, region string) error {
+	k := newKey(roleArn, region)
+	limiter := l.limiter(k)
+
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	waitSeconds.WithLabelValues(k.account, k.region).Observe(time.Since(start).Seconds())
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	return nil
+}
+
+// Done adapts the rate of the bucket of the role and region to the result of
+// a call. Throttling halves the rate, down to minFactor of the configured
+// rate.
+func (l *Limiter) Done(roleArn, region string, throttled bool) {
+	k := newKey(roleArn, region)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b, ok := l.buckets[k]
+	if !ok || b.config.QPS == 0 {
+		return
+	}
+
+	factor := b.factor
+	if throttled {
+		throttledCalls.WithLabelValues(k.account, k.region).Inc()
+		factor /= 2
+		if factor < minFactor {
+			factor = minFactor
+		}
+	} else if factor < 1 {
+		factor += recoveryStep
+		if factor > 1 {
+			factor = 1
+		}
+	}
+	if factor != b.factor {
+		b.factor = factor
+		b.limiter.SetLimit(rate.Limit(b.config.QPS * factor))
+		currentQPS.WithLabelValues(k.account, k.region).Set(b.config.QPS * factor)
+	}
+}
+
+// limiter returns the token bucket of the account and region, which is
+// created or reset when its settings change.
+func (l *Limiter) limiter(k key) *rate.Limiter {
+	config := l.settings().Bucket(k.account)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b, ok := l.buckets[k]
+	if ok && b.config == config {
+		return b.limiter
+	}
+
+	limit := rate.Inf
+	if config.QPS > 0 {
+		limit = rate.Limit(config.QPS)
+	}
+	b = &bucket{
+		limiter: rate.NewLimiter(limit, config.Burst),
+		config:  config,
+		factor:  1,
+	}
+	l.buckets[k] = b
+	currentQPS.WithLabelValues(k.account, k.region).Set(config.QPS)
+
+	return b.limiter
+}
+
+// newKey returns the bucket key of the role. Roles are grouped by their AWS
+// account, which AWS request limits apply to. The role ARN is used when it
+// has no account.
+func newKey(roleArn, region string) key {
+	account := roleArn
+	if parsed, err := arn.Parse(roleArn); err == nil && parsed.AccountID != "" {
+		account = parsed.AccountID
+	}
+	return key{account: account, region: region}
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"golang.org/x/time/rate"
+)
+
+const (
+	roleArn      = "arn:aws:iam::123456789012:role/test"
+	otherRoleArn = "arn:aws:iam::123456789012:role/other"
+	region       = "eu-west-1"
+)
+
+func Test_Limiter(t *testing.T) {
+	settings := Settings{
+		Default: Bucket{QPS: 1, Burst: 1},
+		Accounts: map[string]Bucket{
+			"210987654321": {},
+		},
+	}
+	l := NewLimiter(func() Settings { return settings })
+
+	// roles of the same account share the bucket
+	err := l.Wait(context.Background(), roleArn, region)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = l.Wait(ctx, otherRoleArn, region)
+	if err == nil {
+		t.Fatalf("expected role of the same account to wait for the bucket")
+	}
+
+	// other regions and accounts have their own buckets
+	err = l.Wait(context.Background(), roleArn, "us-east-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		err = l.Wait(context.Background(), "arn:aws:iam::210987654321:role/test", region)
+		if err != nil {
+			t.Fatalf("expected unlimited calls for account without QPS, got %v", err)
+		}
+	}
+
+	// throttling halves the rate, which recovers with calls that are not
+	// throttled
+	settings.Default = Bucket{QPS: 10, Burst: 10}
+	l.limiter(newKey(roleArn, region))
+	for i := 0; i < 5; i++ {
+		l.Done(roleArn, region, true)
+	}
+	if limit := l.limiter(newKey(roleArn, region)).Limit(); limit != rate.Limit(10*minFactor) {
+		t.Fatalf("expected rate %v after throttling, got %v", 10*minFactor, limit)
+	}
+	for i := 0; i < 200; i++ {
+		l.Done(roleArn, region, false)
+	}
+	if limit := l.limiter(newKey(roleArn, region)).Limit(); limit != 10 {
+		t.Fatalf("expected rate to recover to 10, got %v", limit)
+	}
+}
+
+func Test_APIOption(t *testing.T) {
+	l := NewLimiter(func() Settings { return Settings{Default: Bucket{QPS: 10, Burst: 10}} })
+
+	stack := middleware.NewStack("test", smithyhttp.NewStackRequest)
+	err := l.APIOption(roleArn, region)(stack)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	calls := 0
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(func(context.Context, interface{}) (interface{}, middleware.Metadata, error) {
+		calls++
+		return nil, middleware.Metadata{}, &smithy.GenericAPIError{Code: "RequestLimitExceeded"}
+	}), stack)
+
+	_, _, err = handler.Handle(context.Background(), struct{}{})
+	if err == nil {
+		t.Fatalf("expected error of the call")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+	if limit := l.limiter(newKey(roleArn, region)).Limit(); limit != 5 {
+		t.Fatalf("expected throttled call to halve the rate to 5, got %v", limit)
+	}
+}
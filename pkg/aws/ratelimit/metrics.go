@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "aws_vpc_operator"
+	metricsSubsystem = "aws_api_rate_limit"
+)
+
+var (
+	bucketLabels = []string{"account", "region"}
+
+	waitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "wait_seconds",
+		Help:      "Time that AWS API calls waited for the rate limiter of their account and region.",
+		Buckets:   []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30},
+	}, bucketLabels)
+
+	throttledCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "throttled_calls_total",
+		Help:      "Number of AWS API calls that were throttled by AWS, which reduce the rate of the limiter.",
+	}, bucketLabels)
+
+	currentQPS = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "qps",
+		Help:      "Current rate of the limiter of the account and region in calls per second, zero when calls are not limited.",
+	}, bucketLabels)
+)
+
+func init() {
+	metrics.Registry.MustRegister(waitSeconds, throttledCalls, currentQPS)
+}
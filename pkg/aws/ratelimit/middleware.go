@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	middlewareID = "AWSVPCOperatorRateLimit"
+
+	// retryMiddlewareID is the ID of the middleware of the AWS SDK that
+	// retries failed attempts.
+	retryMiddlewareID = "Retry"
+)
+
+// APIOption returns an AWS SDK API option that waits for the bucket of the
+// role and region before each attempt of a call, including retries, and
+// adapts the rate of the bucket to the result of the attempt.
+func (l *Limiter) APIOption(roleArn, region string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		m := middleware.FinalizeMiddlewareFunc(middlewareID, func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			err := l.Wait(ctx, roleArn, region)
+			if err != nil {
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+			}
+
+			out, metadata, err := next.HandleFinalize(ctx, in)
+			l.Done(roleArn, region, errors.IsThrottled(err))
+			return out, metadata, err
+		})
+
+		if _, ok := stack.Finalize.Get(retryMiddlewareID); ok {
+			return stack.Finalize.Insert(m, retryMiddlewareID, middleware.After)
+		}
+		return stack.Finalize.Add(m, middleware.After)
+	}
+}
@@ -11,6 +11,10 @@ type ReconcileRequest[TResourceSpec any] struct {
 	Resource conditions.Setter
 
 	ClusterName string
+
+	// ClusterUID is the UID of the AWSCluster, which tells apart clusters
+	// with the same name, see tags.NameAWSClusterUID.
+	ClusterUID string
 }
 
 type CloudResourceRequest[TResourceSpec any] struct {
@@ -18,6 +22,11 @@ type CloudResourceRequest[TResourceSpec any] struct {
 	Region         string
 	Spec           TResourceSpec
 	AdditionalTags map[string]string
+
+	// LastAppliedTagKeys are the keys of AdditionalTags that were applied to
+	// the resources before. Those that are not in AdditionalTags anymore are
+	// removed from the resources.
+	LastAppliedTagKeys []string
 }
 
 type DeletedCloudResourceSpec struct {
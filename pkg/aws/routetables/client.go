@@ -2,17 +2,21 @@ package routetables
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
 type Client interface {
 	Create(ctx context.Context, input CreateRouteTableInput) (CreateRouteTableOutput, error)
+	Associate(ctx context.Context, input AssociateRouteTableInput) (AssociationStateCode, error)
 	Update(ctx context.Context, input UpdateRouteTableInput) error
 	Get(ctx context.Context, input GetRouteTableInput) (RouteTableOutput, error)
 	List(ctx context.Context, input ListRouteTablesInput) (ListRouteTablesOutput, error)
@@ -33,10 +37,18 @@ func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Clien
 		return nil, microerror.Mask(err)
 	}
 
+	snapshotClient, err := snapshot.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	return &client{
 		ec2Client:        ec2Client,
 		assumeRoleClient: assumeRoleClient,
 		tagsClient:       tagsClient,
+		snapshotClient:   snapshotClient,
+		notFound:         map[string]time.Time{},
+		now:              time.Now,
 	}, nil
 }
 
@@ -44,4 +56,11 @@ type client struct {
 	ec2Client        *ec2.Client
 	tagsClient       tags.Client
 	assumeRoleClient assumerole.Client
+	snapshotClient   snapshot.Client
+
+	// notFound has the time when each created route table was first not
+	// found, see Create.
+	mutex    sync.Mutex
+	notFound map[string]time.Time
+	now      func() time.Time
 }
@@ -2,6 +2,8 @@ package routetables
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -9,6 +11,9 @@ import (
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	awsresource "github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -19,6 +24,10 @@ type CreateRouteTableInput struct {
 	VpcId    string
 	SubnetId string
 	Tags     map[string]string
+
+	// ClientToken makes the creation idempotent, see aws.ClientToken. It is
+	// combined with a hash of the create input, see aws.InputClientToken.
+	ClientToken string
 }
 
 type CreateRouteTableOutput struct {
@@ -50,50 +59,197 @@ func (c *client) Create(ctx context.Context, input CreateRouteTableInput) (outpu
 		return CreateRouteTableOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.SubnetId must not be empty", input)
 	}
 
-	//
-	// Create route table
-	//
-	var routeTableId string
-	{
-		ec2Input := ec2.CreateRouteTableInput{
-			VpcId: &input.VpcId,
-			TagSpecifications: []ec2Types.TagSpecification{
-				tags.BuildParamsToTagSpecification(ec2Types.ResourceTypeRouteTable, input.Tags),
-			},
+	ec2Input := ec2.CreateRouteTableInput{
+		VpcId: &input.VpcId,
+		TagSpecifications: []ec2Types.TagSpecification{
+			tags.BuildParamsToTagSpecification(ec2Types.ResourceTypeRouteTable, input.Tags),
+		},
+	}
+	clientToken, err := awsresource.InputClientToken(input.ClientToken, ec2Input)
+	if err != nil {
+		return CreateRouteTableOutput{}, microerror.Mask(err)
+	}
+	if clientToken != "" {
+		ec2Input.ClientToken = aws.String(clientToken)
+	}
+
+	if dryrun.Enabled(ctx) {
+		routeTableId := dryrun.PlannedId("rtb")
+		operation := dryrun.Operation{
+			Action:     "CreateRouteTable",
+			ResourceId: routeTableId,
+			Details:    fmt.Sprintf("vpc %s", input.VpcId),
 		}
-		ec2Output, err := c.ec2Client.CreateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		err = dryrun.Record(ctx, operation, func() error {
+			ec2Input.DryRun = aws.Bool(true)
+			_, err := c.ec2Client.CreateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			return err
+		})
 		if err != nil {
 			return CreateRouteTableOutput{}, microerror.Mask(err)
 		}
+
+		output = CreateRouteTableOutput{
+			RouteTableId: routeTableId,
+		}
+		output.AssociationStateCode, err = c.associate(ctx, input.RoleARN, input.Region, routeTableId, input.SubnetId)
+		if err != nil {
+			return CreateRouteTableOutput{}, microerror.Mask(err)
+		}
+		return output, nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		retry := ec2Input.ClientToken != nil && attempt < awsresource.MaxClientTokenAttempts
+
+		//
+		// Create route table
+		//
+		ec2Output, err := c.ec2Client.CreateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		snapshot.Invalidate(ctx, snapshot.KindRouteTables)
+		if retry && errors.IsIdempotentParameterMismatch(err) {
+			logger.Info("Client token was used with other parameters, retrying with next client token")
+			ec2Input.ClientToken = aws.String(awsresource.NextClientToken(*ec2Input.ClientToken, "IdempotentParameterMismatch"))
+			continue
+		} else if err != nil {
+			return CreateRouteTableOutput{}, microerror.Mask(err)
+		}
 		if ec2Output.RouteTable.RouteTableId == nil {
 			return CreateRouteTableOutput{}, microerror.Maskf(errors.RouteTableIdNotSetError, "Created route table for VPC %s, but route table ID is not set", input.VpcId)
 		}
+		routeTableId := *ec2Output.RouteTable.RouteTableId
 
-		routeTableId = *ec2Output.RouteTable.RouteTableId
-	}
-	output = CreateRouteTableOutput{
-		RouteTableId: routeTableId,
-	}
-
-	//
-	// Associate route table to a specified subnet
-	//
-	{
-		ec2Input := ec2.AssociateRouteTableInput{
-			RouteTableId: aws.String(routeTableId),
-			SubnetId:     aws.String(input.SubnetId),
+		//
+		// Associate route table to a specified subnet
+		//
+		stateCode, err := c.associate(ctx, input.RoleARN, input.Region, routeTableId, input.SubnetId)
+		if retry && errors.IsRouteTableNotFound(err) {
+			// a route table that was just created might not be visible yet,
+			// so the reconciliation is retried before we assume that the
+			// client token returned a route table that has been deleted since
+			if !c.isStale(routeTableId) {
+				return CreateRouteTableOutput{}, microerror.Maskf(errors.RouteTableNotFoundError, "created route table %s is not found yet", routeTableId)
+			}
+			logger.Info("Client token returned deleted route table, retrying with next client token", "route-table-id", routeTableId)
+			ec2Input.ClientToken = aws.String(awsresource.NextClientToken(*ec2Input.ClientToken, routeTableId))
+			continue
 		}
-		ec2Output, err := c.ec2Client.AssociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		c.found(routeTableId)
 		if err != nil {
 			return CreateRouteTableOutput{}, microerror.Mask(err)
 		}
 
-		if ec2Output.AssociationState != nil {
-			output.AssociationStateCode = AssociationStateCode(ec2Output.AssociationState.State)
+		output = CreateRouteTableOutput{
+			RouteTableId:         routeTableId,
+			AssociationStateCode: stateCode,
+		}
+		return output, nil
+	}
+}
+
+// staleRouteTableCheckDelay is the time for which a created route table may
+// not be found, before it is assumed to be deleted.
+const staleRouteTableCheckDelay = 5 * time.Second
+
+// isStale records that the created route table was not found, and checks if
+// it was not found already staleRouteTableCheckDelay ago.
+func (c *client) isStale(routeTableId string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	firstNotFound, ok := c.notFound[routeTableId]
+	if !ok {
+		c.notFound[routeTableId] = c.now()
+		return false
+	}
+	if c.now().Sub(firstNotFound) < staleRouteTableCheckDelay {
+		return false
+	}
+	delete(c.notFound, routeTableId)
+	return true
+}
+
+// found forgets that the created route table was not found before.
+func (c *client) found(routeTableId string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.notFound, routeTableId)
+}
+
+type AssociateRouteTableInput struct {
+	RoleARN      string
+	Region       string
+	RouteTableId string
+	SubnetId     string
+}
+
+// Associate associates an existing route table to the specified subnet.
+func (c *client) Associate(ctx context.Context, input AssociateRouteTableInput) (stateCode AssociationStateCode, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started associating route table", "route-table-id", input.RouteTableId, "subnet-id", input.SubnetId)
+	defer func() {
+		if err == nil {
+			logger.Info("Finished associating route table", "route-table-id", input.RouteTableId, "subnet-id", input.SubnetId, "association-state", stateCode)
 		} else {
-			output.AssociationStateCode = AssociationStateCodeUnknown
+			logger.Error(err, "Failed to associate route table", "route-table-id", input.RouteTableId, "subnet-id", input.SubnetId)
 		}
+	}()
+
+	if input.RoleARN == "" {
+		return "", microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return "", microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.RouteTableId == "" {
+		return "", microerror.Maskf(errors.InvalidConfigError, "%T.RouteTableId must not be empty", input)
+	}
+	if input.SubnetId == "" {
+		return "", microerror.Maskf(errors.InvalidConfigError, "%T.SubnetId must not be empty", input)
+	}
+
+	stateCode, err = c.associate(ctx, input.RoleARN, input.Region, input.RouteTableId, input.SubnetId)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return stateCode, nil
+}
+
+func (c *client) associate(ctx context.Context, roleArn, region, routeTableId, subnetId string) (AssociationStateCode, error) {
+	ec2Input := ec2.AssociateRouteTableInput{
+		RouteTableId: aws.String(routeTableId),
+		SubnetId:     aws.String(subnetId),
+	}
+	if dryrun.Enabled(ctx) {
+		operation := dryrun.Operation{
+			Action:     "AssociateRouteTable",
+			ResourceId: routeTableId,
+			Details:    fmt.Sprintf("subnet %s", subnetId),
+		}
+		var check func() error
+		if !dryrun.IsPlannedId(routeTableId) {
+			check = func() error {
+				ec2Input.DryRun = aws.Bool(true)
+				_, err := c.ec2Client.AssociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
+				return err
+			}
+		}
+		err := dryrun.Record(ctx, operation, check)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+		return AssociationStateCodeAssociating, nil
+	}
+
+	ec2Output, err := c.ec2Client.AssociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
+	snapshot.Invalidate(ctx, snapshot.KindRouteTables)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if ec2Output.AssociationState == nil {
+		return AssociationStateCodeUnknown, nil
 	}
 
-	return output, nil
+	return AssociationStateCode(ec2Output.AssociationState.State), nil
 }
@@ -0,0 +1,126 @@
+package routetables
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+func Test_Create_RetriesIdempotentParameterMismatch(t *testing.T) {
+	var tokens []string
+	fakeEC2 := awstest.NewEC2().
+		On("CreateRouteTable", func(input interface{}) (interface{}, error) {
+			tokens = append(tokens, aws.ToString(input.(*ec2.CreateRouteTableInput).ClientToken))
+			if len(tokens) == 1 {
+				return nil, &smithy.GenericAPIError{Code: "IdempotentParameterMismatch", Message: "test"}
+			}
+			return &ec2.CreateRouteTableOutput{
+				RouteTable: &ec2Types.RouteTable{RouteTableId: aws.String("rtb-1")},
+			}, nil
+		}).
+		On("AssociateRouteTable", func(interface{}) (interface{}, error) {
+			return &ec2.AssociateRouteTableOutput{
+				AssociationState: &ec2Types.RouteTableAssociationState{State: ec2Types.RouteTableAssociationStateCodeAssociated},
+			}, nil
+		})
+
+	client, err := NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := client.Create(context.Background(), CreateRouteTableInput{
+		RoleARN:     awstest.RoleARN,
+		Region:      awstest.Region,
+		VpcId:       "vpc-1",
+		SubnetId:    "subnet-1",
+		Tags:        map[string]string{"Name": "test"},
+		ClientToken: "token",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.RouteTableId != "rtb-1" {
+		t.Errorf("expected route table rtb-1, got %q", output.RouteTableId)
+	}
+	if len(tokens) != 2 || tokens[0] == tokens[1] {
+		t.Errorf("expected 2 CreateRouteTable calls with different client tokens, got %v", tokens)
+	}
+	if calls := fakeEC2.Calls("AssociateRouteTable"); calls != 1 {
+		t.Errorf("expected 1 AssociateRouteTable call, got %d", calls)
+	}
+}
+
+func Test_Create_RequeuesUntilCreatedRouteTableIsStale(t *testing.T) {
+	var tokens []string
+	fakeEC2 := awstest.NewEC2().
+		On("CreateRouteTable", func(input interface{}) (interface{}, error) {
+			tokens = append(tokens, aws.ToString(input.(*ec2.CreateRouteTableInput).ClientToken))
+			routeTableId := "rtb-deleted"
+			if tokens[len(tokens)-1] != tokens[0] {
+				routeTableId = "rtb-1"
+			}
+			return &ec2.CreateRouteTableOutput{
+				RouteTable: &ec2Types.RouteTable{RouteTableId: aws.String(routeTableId)},
+			}, nil
+		}).
+		On("AssociateRouteTable", func(input interface{}) (interface{}, error) {
+			if aws.ToString(input.(*ec2.AssociateRouteTableInput).RouteTableId) == "rtb-deleted" {
+				return nil, &smithy.GenericAPIError{Code: "InvalidRouteTableID.NotFound", Message: "test"}
+			}
+			return &ec2.AssociateRouteTableOutput{
+				AssociationState: &ec2Types.RouteTableAssociationState{State: ec2Types.RouteTableAssociationStateCodeAssociated},
+			}, nil
+		})
+
+	c, err := NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	c.(*client).now = func() time.Time { return now }
+
+	input := CreateRouteTableInput{
+		RoleARN:     awstest.RoleARN,
+		Region:      awstest.Region,
+		VpcId:       "vpc-1",
+		SubnetId:    "subnet-1",
+		Tags:        map[string]string{"Name": "test"},
+		ClientToken: "token",
+	}
+
+	// the route table might not be visible yet, so the reconciliation is
+	// retried without waiting
+	_, err = c.Create(context.Background(), input)
+	if !errors.IsRouteTableNotFound(err) {
+		t.Fatalf("expected route table not found error, got %v", microerror.Pretty(err, true))
+	}
+	now = now.Add(staleRouteTableCheckDelay / 2)
+	_, err = c.Create(context.Background(), input)
+	if !errors.IsRouteTableNotFound(err) {
+		t.Fatalf("expected route table not found error, got %v", microerror.Pretty(err, true))
+	}
+
+	// the route table is still not found after the delay, so the next client
+	// token is used
+	now = now.Add(staleRouteTableCheckDelay)
+	output, err := c.Create(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.RouteTableId != "rtb-1" {
+		t.Errorf("expected route table rtb-1, got %q", output.RouteTableId)
+	}
+	if len(tokens) != 4 || tokens[0] != tokens[1] || tokens[1] != tokens[2] || tokens[2] == tokens[3] {
+		t.Errorf("expected 4 CreateRouteTable calls with the next client token in the last call, got %v", tokens)
+	}
+}
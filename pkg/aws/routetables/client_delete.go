@@ -2,12 +2,17 @@ package routetables
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
@@ -15,12 +20,16 @@ type DeleteRouteTableInput struct {
 	RoleARN      string
 	Region       string
 	RouteTableId string
+	ClusterName  string
+	ClusterUID   string
 }
 
 type DeleteRouteTablesInput struct {
-	RoleARN string
-	Region  string
-	VpcId   string
+	RoleARN     string
+	Region      string
+	VpcId       string
+	ClusterName string
+	ClusterUID  string
 }
 
 func (c *client) Delete(ctx context.Context, input DeleteRouteTableInput) (err error) {
@@ -43,14 +52,27 @@ func (c *client) Delete(ctx context.Context, input DeleteRouteTableInput) (err e
 	if input.RouteTableId == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.RouteTableId must not be empty", input)
 	}
+	if input.ClusterName == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", input)
+	}
 
 	// Get existing route table associations, so we can delete them
-	getInput := GetRouteTableInput(input)
+	getInput := GetRouteTableInput{
+		RoleARN:      input.RoleARN,
+		Region:       input.Region,
+		RouteTableId: input.RouteTableId,
+	}
 	getOutput, err := c.Get(ctx, getInput)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	// We delete only route tables that we created
+	if !tags.IsOwnedBy(getOutput.Tags, input.ClusterName, input.ClusterUID) {
+		logger.Info("Skipped deleting route table that is not owned by the cluster", "route-table-id", input.RouteTableId)
+		return microerror.Maskf(errors.ResourceNotOwnedError, "route table %s is not owned by cluster %s", input.RouteTableId, input.ClusterName)
+	}
+
 	isMainRouteTable := false
 
 	// now delete all existing route table associations
@@ -99,16 +121,42 @@ func (c *client) DeleteAll(ctx context.Context, input DeleteRouteTablesInput) (e
 	if input.VpcId == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
 	}
+	if input.ClusterName == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", input)
+	}
 
-	listInput := ListRouteTablesInput(input)
+	listInput := ListRouteTablesInput{
+		RoleARN: input.RoleARN,
+		Region:  input.Region,
+		VpcId:   input.VpcId,
+	}
 	listOutput, err := c.List(ctx, listInput)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	var notOwnedRouteTableIds []string
+	var ownedSubnetIds map[string]bool
 	for _, routeTable := range listOutput {
 		isMainRouteTable := false
 		allAssociations := routeTable.GetAllAssociations()
+		for _, association := range allAssociations {
+			isMainRouteTable = isMainRouteTable || association.Main
+		}
+		if isMainRouteTable && ownedSubnetIds == nil {
+			ownedSubnetIds, err = c.ownedSubnetIds(ctx, input)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		// We delete only route tables that we created. Main route table is
+		// created together with the VPC, and it is never deleted here.
+		if !isMainRouteTable && !tags.IsOwnedBy(routeTable.Tags, input.ClusterName, input.ClusterUID) {
+			logger.Info("Skipped deleting route table that is not owned by the cluster", "route-table-id", routeTable.RouteTableId)
+			notOwnedRouteTableIds = append(notOwnedRouteTableIds, routeTable.RouteTableId)
+			continue
+		}
 
 		// then we delete all route table associations
 		if len(allAssociations) > 0 {
@@ -117,7 +165,13 @@ func (c *client) DeleteAll(ctx context.Context, input DeleteRouteTablesInput) (e
 		for _, association := range allAssociations {
 			if association.Main {
 				logger.Info("Skipped deleting route table association for main route table", "route-table-id", routeTable.RouteTableId, "association", association)
-				isMainRouteTable = true
+				continue
+			}
+			// The main route table is not owned by the cluster, e.g. in a VPC
+			// that the cluster shares, so only the associations of our own
+			// subnets are deleted.
+			if isMainRouteTable && !ownedSubnetIds[association.SubnetId] {
+				logger.Info("Skipped deleting main route table association of subnet that is not owned by the cluster", "route-table-id", routeTable.RouteTableId, "association", association)
 				continue
 			}
 			err = c.deleteRouteTableAssociation(ctx, input.RoleARN, input.Region, routeTable.RouteTableId, association.AssociationId)
@@ -137,17 +191,57 @@ func (c *client) DeleteAll(ctx context.Context, input DeleteRouteTablesInput) (e
 		}
 	}
 
+	if len(notOwnedRouteTableIds) > 0 {
+		return microerror.Maskf(errors.ResourceNotOwnedError, "route tables %s are not owned by cluster %s", strings.Join(notOwnedRouteTableIds, ", "), input.ClusterName)
+	}
+
 	return nil
 }
 
+// ownedSubnetIds returns the IDs of the subnets in the VPC that are owned by
+// the cluster.
+func (c *client) ownedSubnetIds(ctx context.Context, input DeleteRouteTablesInput) (map[string]bool, error) {
+	snapshotInput := snapshot.Input{
+		RoleARN: input.RoleARN,
+		Region:  input.Region,
+		VpcId:   input.VpcId,
+	}
+	subnets, err := c.snapshotClient.Subnets(ctx, snapshotInput)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	subnetIds := map[string]bool{}
+	for _, subnet := range subnets {
+		if tags.IsOwnedBy(tags.ToMap(subnet.Tags), input.ClusterName, input.ClusterUID) {
+			subnetIds[aws.ToString(subnet.SubnetId)] = true
+		}
+	}
+	return subnetIds, nil
+}
+
 func (c *client) deleteRouteTableAssociation(ctx context.Context, roleArn, region, routeTableId, associationId string) error {
 	logger := log.FromContext(ctx)
 	logger.Info("Deleting route table association", "route-table-id", routeTableId, "association", associationId)
 	ec2Input := ec2.DisassociateRouteTableInput{
 		AssociationId: aws.String(associationId),
 	}
+	if dryrun.Enabled(ctx) {
+		operation := dryrun.Operation{
+			Action:     "DisassociateRouteTable",
+			ResourceId: associationId,
+			Details:    fmt.Sprintf("route table %s", routeTableId),
+		}
+		err := dryrun.Record(ctx, operation, func() error {
+			ec2Input.DryRun = aws.Bool(true)
+			_, err := c.ec2Client.DisassociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
+			return err
+		})
+		return microerror.Mask(err)
+	}
 	_, err := c.ec2Client.DisassociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
-	if errors.IsAWSHTTPStatusNotFound(err) {
+	snapshot.Invalidate(ctx, snapshot.KindRouteTables)
+	if errors.IsRouteTableAssociationNotFound(err) {
 		logger.Info("Route table association not found, nothing to delete", "route-table-id", routeTableId, "association-id", associationId)
 		return nil
 	} else if err != nil {
@@ -164,8 +258,17 @@ func (c *client) deleteRouteTable(ctx context.Context, roleArn, region, routeTab
 	ec2Input := ec2.DeleteRouteTableInput{
 		RouteTableId: aws.String(routeTableId),
 	}
+	if dryrun.Enabled(ctx) {
+		err := dryrun.Record(ctx, dryrun.Operation{Action: "DeleteRouteTable", ResourceId: routeTableId}, func() error {
+			ec2Input.DryRun = aws.Bool(true)
+			_, err := c.ec2Client.DeleteRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
+			return err
+		})
+		return microerror.Mask(err)
+	}
 	_, err := c.ec2Client.DeleteRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
-	if errors.IsAWSHTTPStatusNotFound(err) {
+	snapshot.Invalidate(ctx, snapshot.KindRouteTables)
+	if errors.IsRouteTableNotFound(err) {
 		logger.Info("Route table not found, nothing to delete", "route-table-id", routeTableId)
 		return nil
 	} else if err != nil {
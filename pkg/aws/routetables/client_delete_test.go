@@ -0,0 +1,100 @@
+package routetables_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/routetables"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+func Test_DeleteAll_SkipsRouteTablesNotOwnedByCluster(t *testing.T) {
+	const clusterName = "test"
+
+	fakeEC2 := awstest.NewEC2().
+		On("DescribeRouteTables", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeRouteTablesOutput{
+				RouteTables: []ec2Types.RouteTable{
+					{
+						RouteTableId: aws.String("rtb-main"),
+						Associations: []ec2Types.RouteTableAssociation{
+							{RouteTableAssociationId: aws.String("rtbassoc-main"), Main: aws.Bool(true)},
+							{RouteTableAssociationId: aws.String("rtbassoc-owned-subnet"), SubnetId: aws.String("subnet-owned")},
+							{RouteTableAssociationId: aws.String("rtbassoc-other-subnet"), SubnetId: aws.String("subnet-other")},
+						},
+					},
+					{
+						RouteTableId: aws.String("rtb-owned"),
+						Tags: []ec2Types.Tag{
+							{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")},
+						},
+					},
+					{
+						RouteTableId: aws.String("rtb-other-team"),
+						Tags: []ec2Types.Tag{
+							{Key: aws.String("team"), Value: aws.String("other")},
+						},
+					},
+				},
+			}, nil
+		}).
+		On("DescribeSubnets", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeSubnetsOutput{
+				Subnets: []ec2Types.Subnet{
+					{
+						SubnetId: aws.String("subnet-owned"),
+						Tags: []ec2Types.Tag{
+							{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")},
+						},
+					},
+					{SubnetId: aws.String("subnet-other")},
+				},
+			}, nil
+		}).
+		On("DisassociateRouteTable", func(interface{}) (interface{}, error) {
+			return &ec2.DisassociateRouteTableOutput{}, nil
+		}).
+		On("DeleteRouteTable", func(interface{}) (interface{}, error) {
+			return &ec2.DeleteRouteTableOutput{}, nil
+		})
+
+	client, err := routetables.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = client.DeleteAll(context.Background(), routetables.DeleteRouteTablesInput{
+		RoleARN:     awstest.RoleARN,
+		Region:      awstest.Region,
+		VpcId:       "vpc-1",
+		ClusterName: clusterName,
+	})
+	if !errors.IsResourceNotOwned(err) {
+		t.Fatalf("expected ResourceNotOwnedError, got %v", err)
+	}
+
+	calls := fakeEC2.AllCalls()
+	var deleted, disassociated []string
+	for _, call := range calls {
+		switch call.Operation {
+		case "DeleteRouteTable":
+			deleted = append(deleted, aws.ToString(call.Input.(*ec2.DeleteRouteTableInput).RouteTableId))
+		case "DisassociateRouteTable":
+			disassociated = append(disassociated, aws.ToString(call.Input.(*ec2.DisassociateRouteTableInput).AssociationId))
+		}
+	}
+	if len(deleted) != 1 || deleted[0] != "rtb-owned" {
+		t.Errorf("expected only rtb-owned to be deleted, got %v", deleted)
+	}
+	// the main route table is not owned, so only the association of the
+	// owned subnet is deleted
+	if len(disassociated) != 1 || disassociated[0] != "rtbassoc-owned-subnet" {
+		t.Errorf("expected only rtbassoc-owned-subnet to be deleted, got %v", disassociated)
+	}
+}
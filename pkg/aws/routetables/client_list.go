@@ -9,6 +9,7 @@ import (
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -69,17 +70,20 @@ func (c *client) List(ctx context.Context, input ListRouteTablesInput) (output L
 		return ListRouteTablesOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
 	}
 
-	const vpcIdFilterName = "vpc-id"
-	output, err = c.listWithFilter(ctx, input.RoleARN, input.Region, vpcIdFilterName, input.VpcId)
+	snapshotInput := snapshot.Input{
+		RoleARN: input.RoleARN,
+		Region:  input.Region,
+		VpcId:   input.VpcId,
+	}
+	ec2RouteTables, err := c.snapshotClient.RouteTables(ctx, snapshotInput)
 	if err != nil {
 		return ListRouteTablesOutput{}, microerror.Mask(err)
 	}
 
-	return output, nil
+	return toOutput(ctx, ec2RouteTables), nil
 }
 
 func (c *client) listWithFilter(ctx context.Context, roleArn, region, filterName, filterValue string) (output ListRouteTablesOutput, err error) {
-	logger := log.FromContext(ctx)
 	ec2Input := ec2.DescribeRouteTablesInput{
 		Filters: []ec2Types.Filter{
 			{
@@ -93,8 +97,15 @@ func (c *client) listWithFilter(ctx context.Context, roleArn, region, filterName
 		return ListRouteTablesOutput{}, microerror.Mask(err)
 	}
 
-	output = ListRouteTablesOutput{}
-	for _, ec2RouteTable := range ec2Output.RouteTables {
+	return toOutput(ctx, ec2Output.RouteTables), nil
+}
+
+// toOutput converts EC2 route tables with their associations to the output
+// of List and Get.
+func toOutput(ctx context.Context, ec2RouteTables []ec2Types.RouteTable) ListRouteTablesOutput {
+	logger := log.FromContext(ctx)
+	output := ListRouteTablesOutput{}
+	for _, ec2RouteTable := range ec2RouteTables {
 		if ec2RouteTable.RouteTableId == nil || *ec2RouteTable.RouteTableId == "" {
 			logger.Info("Skipping route table without ID set")
 			continue
@@ -138,5 +149,5 @@ func (c *client) listWithFilter(ctx context.Context, roleArn, region, filterName
 		logger.Info("Found route table", "route-table-id", routeTableOutput.RouteTableId, "route-table-tags", routeTableOutput.Tags)
 	}
 
-	return output, nil
+	return output
 }
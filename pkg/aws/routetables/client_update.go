@@ -6,6 +6,7 @@ import (
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -15,6 +16,13 @@ type UpdateRouteTableInput struct {
 	Region       string
 	RouteTableId string
 	Tags         map[string]string
+
+	// CurrentTags are the tags that the route table currently has.
+	CurrentTags map[string]string
+
+	// ManagedTagKeys are the keys of the tags that were previously applied
+	// by the operator. They are deleted when they are not in Tags anymore.
+	ManagedTagKeys []string
 }
 
 func (c *client) Update(ctx context.Context, input UpdateRouteTableInput) (err error) {
@@ -39,13 +47,16 @@ func (c *client) Update(ctx context.Context, input UpdateRouteTableInput) (err e
 	}
 
 	// update route table tags
-	createTagsInput := tags.CreateTagsInput{
-		RoleARN:    input.RoleARN,
-		Region:     input.Region,
-		ResourceId: input.RouteTableId,
-		Tags:       input.Tags,
+	reconcileTagsInput := tags.ReconcileTagsInput{
+		RoleARN:     input.RoleARN,
+		Region:      input.Region,
+		ResourceId:  input.RouteTableId,
+		CurrentTags: input.CurrentTags,
+		WantedTags:  input.Tags,
+		ManagedKeys: input.ManagedTagKeys,
 	}
-	err = c.tagsClient.Create(ctx, createTagsInput)
+	err = c.tagsClient.Reconcile(ctx, reconcileTagsInput)
+	snapshot.Invalidate(ctx, snapshot.KindRouteTables)
 	if err != nil {
 		return microerror.Mask(err)
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/naming"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
@@ -21,6 +22,10 @@ type Spec struct {
 	// Subnets for which we want route tables. We create one route table per
 	// subnet.
 	Subnets []Subnet
+
+	// NameTemplate renders Name tags of route tables. naming.DefaultRouteTable
+	// is used when it is not set.
+	NameTemplate naming.Template
 }
 
 type Subnet struct {
@@ -28,6 +33,8 @@ type Subnet struct {
 	AvailabilityZone string
 }
 
+var defaultNameTemplate = naming.MustParse(naming.DefaultRouteTable)
+
 type Status struct {
 	RouteTableId          string
 	RouteTableAssociation []RouteTableAssociation
@@ -8,12 +8,18 @@ import (
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
 )
 
 func (r *reconciler) ReconcileDelete(ctx context.Context, request aws.ReconcileRequest[aws.DeletedCloudResourceSpec]) (err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Started reconciling route tables deletion")
+	ctx, span := tracing.Start(ctx, "routetables.ReconcileDelete",
+		tracing.ClusterNameKey.String(request.ClusterName),
+		tracing.RegionKey.String(request.Region),
+		tracing.VpcIdKey.String(request.Spec.Id))
 	defer func() {
+		tracing.End(span, err)
 		if err == nil {
 			logger.Info("Finished reconciling route tables deletion")
 		} else {
@@ -36,9 +42,11 @@ func (r *reconciler) ReconcileDelete(ctx context.Context, request aws.ReconcileR
 
 	// Delete all route tables for the specified VPC
 	input := DeleteRouteTablesInput{
-		RoleARN: request.RoleARN,
-		Region:  request.Region,
-		VpcId:   request.Spec.Id,
+		RoleARN:     request.RoleARN,
+		Region:      request.Region,
+		VpcId:       request.Spec.Id,
+		ClusterName: request.ClusterName,
+		ClusterUID:  request.ClusterUID,
 	}
 	err = r.client.DeleteAll(ctx, input)
 	if err != nil {
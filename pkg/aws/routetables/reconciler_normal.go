@@ -2,8 +2,6 @@ package routetables
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
 	"github.com/giantswarm/microerror"
 	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
@@ -11,13 +9,25 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/naming"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
 )
 
 func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest[Spec]) (result aws.ReconcileResult[[]Status], err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Started reconciling route tables")
+	ctx, span := tracing.Start(ctx, "routetables.Reconcile",
+		tracing.ClusterNameKey.String(request.ClusterName),
+		tracing.RegionKey.String(request.Region),
+		tracing.VpcIdKey.String(request.Spec.VpcId))
 	defer func() {
+		var routeTableIds []string
+		for _, status := range result.Status {
+			routeTableIds = append(routeTableIds, status.RouteTableId)
+		}
+		span.SetAttributes(tracing.RouteTableIdsKey.StringSlice(routeTableIds))
+		tracing.End(span, err)
 		if err == nil {
 			logger.Info("Finished reconciling route tables")
 		} else {
@@ -104,26 +114,60 @@ func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest
 		routeTablesWithoutSubnets[existingRouteTable.RouteTableId] = existingRouteTable
 	}
 
+	//
+	// Route tables that we created, but failed to associate, e.g. because the
+	// operator crashed right after creating them, are associated to the
+	// subnets in the same zone that still need a route table, instead of
+	// being deleted and created again.
+	//
+	for _, subnet := range request.Spec.Subnets {
+		if subnetToRouteTable[subnet.Id] != nil {
+			continue
+		}
+		wantedName := r.getRouteTableTags(request.ClusterName, request.ClusterUID, request.Spec.NameTemplate, "", subnet.AvailabilityZone, request.AdditionalTags)["Name"]
+		for routeTableId, routeTable := range routeTablesWithoutSubnets {
+			if !tags.IsOwnedBy(routeTable.Tags, request.ClusterName, request.ClusterUID) || routeTable.Tags["Name"] != wantedName {
+				continue
+			}
+
+			logger.Info("Adopting route table without associated subnet", "route-table-id", routeTableId, "subnet-id", subnet.Id)
+			input := AssociateRouteTableInput{
+				RoleARN:      request.RoleARN,
+				Region:       request.Region,
+				RouteTableId: routeTableId,
+				SubnetId:     subnet.Id,
+			}
+			stateCode, err := r.client.Associate(ctx, input)
+			if err != nil {
+				return aws.ReconcileResult[[]Status]{}, microerror.Mask(err)
+			}
+
+			routeTable.AssociationsToSubnets = []RouteTableAssociation{
+				{
+					SubnetId:             subnet.Id,
+					AssociationStateCode: stateCode,
+				},
+			}
+			subnetToRouteTable[subnet.Id] = &routeTable
+			delete(routeTablesWithoutSubnets, routeTableId)
+			break
+		}
+	}
+
 	//
 	// first, let's delete leftover route tables (just those that this operator
 	// created, see 2.c above)
 	//
 	for routeTableId, routeTable := range routeTablesWithoutSubnets {
 		logger.Info("Route table does not have an associated subnet", "route-table-id", routeTableId)
-		createdByThisOperator := false
-		for tagName := range routeTable.Tags {
-			if strings.HasPrefix(tagName, tags.NameAWSProviderPrefix) {
-				createdByThisOperator = true
-				break
-			}
-		}
-
-		if createdByThisOperator {
+		if tags.IsOwnedBy(routeTable.Tags, request.ClusterName, request.ClusterUID) {
 			logger.Info("Deleting route table without associated subnet", "route-table-id", routeTableId)
 			input := DeleteRouteTableInput{
 				RoleARN:      request.RoleARN,
 				Region:       request.Region,
 				RouteTableId: routeTableId,
+				ClusterName:  request.ClusterName,
+				ClusterUID:   request.ClusterUID,
 			}
 			err = r.client.Delete(ctx, input)
 			if err != nil {
@@ -149,16 +193,19 @@ func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest
 				break
 			}
 		}
-		wantedTags := r.getRouteTableTags(request.ClusterName, routeTable.RouteTableId, zone, request.AdditionalTags)
+		wantedTags := r.getRouteTableTags(request.ClusterName, request.ClusterUID, request.Spec.NameTemplate, routeTable.RouteTableId, zone, request.AdditionalTags)
 		currentTags := routeTable.Tags
-		changedOrNewTags := tags.Diff(wantedTags, currentTags)
+		changedOrNewTags, removedTagKeys := tags.Changes(currentTags, wantedTags, request.LastAppliedTagKeys)
 
-		if len(changedOrNewTags) > 0 {
+		if len(changedOrNewTags) > 0 || len(removedTagKeys) > 0 {
 			input := UpdateRouteTableInput{
 				RoleARN:      request.RoleARN,
 				Region:       request.Region,
 				RouteTableId: routeTable.RouteTableId,
 				Tags:         wantedTags,
+
+				CurrentTags:    currentTags,
+				ManagedTagKeys: request.LastAppliedTagKeys,
 			}
 			err = r.client.Update(ctx, input)
 			if err != nil {
@@ -193,7 +240,10 @@ func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest
 			Region:   request.Region,
 			VpcId:    request.Spec.VpcId,
 			SubnetId: subnet.Id,
-			Tags:     r.getRouteTableTags(request.ClusterName, "", subnet.AvailabilityZone, request.AdditionalTags),
+			Tags:     r.getRouteTableTags(request.ClusterName, request.ClusterUID, request.Spec.NameTemplate, "", subnet.AvailabilityZone, request.AdditionalTags),
+			// a retried creation for the same subnet returns the route table
+			// that was already created
+			ClientToken: aws.ClientToken(request.Resource.GetUID(), "routetable", request.Spec.VpcId, subnet.Id),
 		}
 		output, err := r.client.Create(ctx, input)
 		if err != nil {
@@ -216,14 +266,19 @@ func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest
 	return result, nil
 }
 
-func (r *reconciler) getRouteTableTags(clusterName, routeTableId, zone string, additionalTags map[string]string) map[string]string {
+func (r *reconciler) getRouteTableTags(clusterName, clusterUID string, nameTemplate naming.Template, routeTableId, zone string, additionalTags map[string]string) map[string]string {
 	if routeTableId == "" {
 		routeTableId = capaservices.TemporaryResourceID
 	}
-	name := fmt.Sprintf("%s-rt-private-%s", clusterName, zone)
+	name := nameTemplate.Or(defaultNameTemplate).Name(naming.Params{
+		ClusterName:      clusterName,
+		AvailabilityZone: zone,
+		Role:             capa.PrivateRoleTagValue,
+	})
 
 	params := tags.BuildParams{
 		ClusterName: clusterName,
+		ClusterUID:  clusterUID,
 		ResourceID:  routeTableId,
 		Name:        name,
 		Role:        capa.PrivateRoleTagValue,
@@ -0,0 +1,71 @@
+package snapshot
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// Client returns the resources of a VPC from the cache in the context, or
+// describes them. The returned resources are shared, so they must not be
+// changed.
+type Client interface {
+	// Vpcs returns the VPC with the specified ID. It returns more than one
+	// VPC only in the unlikely case that EC2 does.
+	Vpcs(ctx context.Context, input Input) ([]ec2Types.Vpc, error)
+	VpcAttributes(ctx context.Context, input Input) (VpcAttributes, error)
+	Subnets(ctx context.Context, input Input) ([]ec2Types.Subnet, error)
+	RouteTables(ctx context.Context, input Input) ([]ec2Types.RouteTable, error)
+	VpcEndpoints(ctx context.Context, input Input) ([]ec2Types.VpcEndpoint, error)
+}
+
+func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Client, error) {
+	if ec2Client == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "ec2Client must not be empty")
+	}
+	if assumeRoleClient == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "assumeRoleClient must not be empty")
+	}
+
+	return &client{
+		ec2Client:        ec2Client,
+		assumeRoleClient: assumeRoleClient,
+	}, nil
+}
+
+type client struct {
+	ec2Client        *ec2.Client
+	assumeRoleClient assumerole.Client
+}
+
+// Input specifies the VPC whose resources are returned. Resources are cached
+// separately for each input.
+type Input struct {
+	RoleARN string
+	Region  string
+	VpcId   string
+}
+
+// VpcAttributes are the DNS attributes of a VPC.
+type VpcAttributes struct {
+	EnableDnsHostnames bool
+	EnableDnsSupport   bool
+}
+
+func validate(input Input) error {
+	if input.RoleARN == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.VpcId == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package snapshot
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+)
+
+const filterNameVpcID = "vpc-id"
+
+func (c *client) Vpcs(ctx context.Context, input Input) ([]ec2Types.Vpc, error) {
+	err := validate(input)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return load(ctx, input, KindVpc, func() ([]ec2Types.Vpc, error) {
+		ec2Input := ec2.DescribeVpcsInput{
+			VpcIds: []string{input.VpcId},
+		}
+		var vpcs []ec2Types.Vpc
+		paginator := ec2.NewDescribeVpcsPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			vpcs = append(vpcs, ec2Output.Vpcs...)
+		}
+		return vpcs, nil
+	})
+}
+
+// VpcAttributes returns the DNS attributes of the VPC. EC2 returns a single
+// attribute per call, so they are described with two calls.
+func (c *client) VpcAttributes(ctx context.Context, input Input) (VpcAttributes, error) {
+	err := validate(input)
+	if err != nil {
+		return VpcAttributes{}, microerror.Mask(err)
+	}
+
+	return load(ctx, input, KindVpcAttributes, func() (VpcAttributes, error) {
+		result := VpcAttributes{}
+
+		ec2Input := ec2.DescribeVpcAttributeInput{
+			VpcId:     aws.String(input.VpcId),
+			Attribute: ec2Types.VpcAttributeNameEnableDnsHostnames,
+		}
+		ec2Output, err := c.ec2Client.DescribeVpcAttribute(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		if err != nil {
+			return VpcAttributes{}, microerror.Mask(err)
+		}
+		if ec2Output.EnableDnsHostnames != nil {
+			result.EnableDnsHostnames = aws.ToBool(ec2Output.EnableDnsHostnames.Value)
+		}
+
+		ec2Input = ec2.DescribeVpcAttributeInput{
+			VpcId:     aws.String(input.VpcId),
+			Attribute: ec2Types.VpcAttributeNameEnableDnsSupport,
+		}
+		ec2Output, err = c.ec2Client.DescribeVpcAttribute(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		if err != nil {
+			return VpcAttributes{}, microerror.Mask(err)
+		}
+		if ec2Output.EnableDnsSupport != nil {
+			result.EnableDnsSupport = aws.ToBool(ec2Output.EnableDnsSupport.Value)
+		}
+
+		return result, nil
+	})
+}
+
+// Subnets returns all subnets of the VPC, in any state.
+func (c *client) Subnets(ctx context.Context, input Input) ([]ec2Types.Subnet, error) {
+	err := validate(input)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return load(ctx, input, KindSubnets, func() ([]ec2Types.Subnet, error) {
+		ec2Input := ec2.DescribeSubnetsInput{
+			Filters: []ec2Types.Filter{vpcFilter(input.VpcId)},
+		}
+		var subnets []ec2Types.Subnet
+		paginator := ec2.NewDescribeSubnetsPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			subnets = append(subnets, ec2Output.Subnets...)
+		}
+		return subnets, nil
+	})
+}
+
+// RouteTables returns all route tables of the VPC with their associations.
+func (c *client) RouteTables(ctx context.Context, input Input) ([]ec2Types.RouteTable, error) {
+	err := validate(input)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return load(ctx, input, KindRouteTables, func() ([]ec2Types.RouteTable, error) {
+		ec2Input := ec2.DescribeRouteTablesInput{
+			Filters: []ec2Types.Filter{vpcFilter(input.VpcId)},
+		}
+		var routeTables []ec2Types.RouteTable
+		paginator := ec2.NewDescribeRouteTablesPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			routeTables = append(routeTables, ec2Output.RouteTables...)
+		}
+		return routeTables, nil
+	})
+}
+
+// VpcEndpoints returns all VPC endpoints of the VPC, of any type and in any
+// state, including VPC endpoints that were deleted recently.
+func (c *client) VpcEndpoints(ctx context.Context, input Input) ([]ec2Types.VpcEndpoint, error) {
+	err := validate(input)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return load(ctx, input, KindVpcEndpoints, func() ([]ec2Types.VpcEndpoint, error) {
+		ec2Input := ec2.DescribeVpcEndpointsInput{
+			Filters: []ec2Types.Filter{vpcFilter(input.VpcId)},
+		}
+		var vpcEndpoints []ec2Types.VpcEndpoint
+		paginator := ec2.NewDescribeVpcEndpointsPaginator(c.ec2Client, &ec2Input)
+		for paginator.HasMorePages() {
+			ec2Output, err := paginator.NextPage(ctx, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			vpcEndpoints = append(vpcEndpoints, ec2Output.VpcEndpoints...)
+		}
+		return vpcEndpoints, nil
+	})
+}
+
+func vpcFilter(vpcId string) ec2Types.Filter {
+	return ec2Types.Filter{
+		Name:   aws.String(filterNameVpcID),
+		Values: []string{vpcId},
+	}
+}
@@ -0,0 +1,89 @@
+package snapshot_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+)
+
+func Test_Subnets(t *testing.T) {
+	testCases := []struct {
+		name string
+		// withCache runs the calls with a cache in the context
+		withCache     bool
+		invalidate    bool
+		expectedCalls int
+	}{
+		{
+			name:          "case 0: subnets are described once with a cache",
+			withCache:     true,
+			expectedCalls: 2,
+		},
+		{
+			name:          "case 1: subnets are described on every use without a cache",
+			expectedCalls: 4,
+		},
+		{
+			name:          "case 2: subnets are described again after they are invalidated",
+			withCache:     true,
+			invalidate:    true,
+			expectedCalls: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// subnets are returned in two pages
+			fakeEC2 := awstest.NewEC2().
+				On("DescribeSubnets", func(input interface{}) (interface{}, error) {
+					if input.(*ec2.DescribeSubnetsInput).NextToken == nil {
+						return &ec2.DescribeSubnetsOutput{
+							Subnets:   []ec2Types.Subnet{{SubnetId: aws.String("subnet-a")}},
+							NextToken: aws.String("page-2"),
+						}, nil
+					}
+					return &ec2.DescribeSubnetsOutput{
+						Subnets: []ec2Types.Subnet{{SubnetId: aws.String("subnet-b")}},
+					}, nil
+				})
+
+			client, err := snapshot.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx := context.Background()
+			if tc.withCache {
+				ctx = snapshot.NewContext(ctx)
+			}
+			input := snapshot.Input{
+				RoleARN: awstest.RoleARN,
+				Region:  awstest.Region,
+				VpcId:   "vpc-1",
+			}
+
+			for i := 0; i < 2; i++ {
+				if tc.invalidate {
+					snapshot.Invalidate(ctx, snapshot.KindSubnets)
+				}
+				subnets, err := client.Subnets(ctx, input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(subnets) != 2 {
+					t.Fatalf("expected 2 subnets, got %d", len(subnets))
+				}
+			}
+
+			if calls := fakeEC2.Calls("DescribeSubnets"); calls != tc.expectedCalls {
+				t.Errorf("expected %d DescribeSubnets calls, got %d", tc.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package snapshot loads the VPC, subnets, route tables with their
+// associations, and VPC endpoints of a VPC, so that the client packages in
+// pkg/aws that reconcile them share the results of the Describe calls.
+//
+// Reconciliations run with a cache in their context, see NewContext. Every
+// kind of resource is then described once per reconciliation, when it is
+// first needed, and described again only after a mutating call invalidated
+// it, see Invalidate. Without a cache in the context, e.g. in CLI commands,
+// resources are described on every use.
+package snapshot
+
+import (
+	"context"
+	"sync"
+)
+
+// Kind is a kind of resources that are described together.
+type Kind string
+
+const (
+	KindVpc           Kind = "vpc"
+	KindVpcAttributes Kind = "vpc-attributes"
+	KindSubnets       Kind = "subnets"
+	KindRouteTables   Kind = "route-tables"
+	KindVpcEndpoints  Kind = "vpc-endpoints"
+)
+
+// AllKinds are all kinds of resources in a snapshot.
+var AllKinds = []Kind{KindVpc, KindVpcAttributes, KindSubnets, KindRouteTables, KindVpcEndpoints}
+
+type contextKey struct{}
+
+type entryKey struct {
+	input Input
+	kind  Kind
+}
+
+// cache has the loaded resources of a reconciliation. It is safe for
+// concurrent use.
+type cache struct {
+	mutex   sync.Mutex
+	entries map[entryKey]interface{}
+}
+
+// NewContext returns a context with an empty cache, in which the resources
+// described by the clients are kept until they are invalidated.
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, &cache{
+		entries: map[entryKey]interface{}{},
+	})
+}
+
+// Invalidate removes the specified kinds of resources of all VPCs from the
+// cache in the context, so that they are described again on their next use.
+// Mutating methods of the clients invalidate the kinds that they change.
+func Invalidate(ctx context.Context, kinds ...Kind) {
+	c := fromContext(ctx)
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key := range c.entries {
+		for _, kind := range kinds {
+			if key.kind == kind {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+func fromContext(ctx context.Context) *cache {
+	c, _ := ctx.Value(contextKey{}).(*cache)
+	return c
+}
+
+// load returns the cached resources of the specified kind, or loads them with
+// loadFn and caches them when the context has a cache. Errors are not cached.
+func load[T any](ctx context.Context, input Input, kind Kind, loadFn func() (T, error)) (T, error) {
+	c := fromContext(ctx)
+	if c == nil {
+		return loadFn()
+	}
+
+	key := entryKey{input: input, kind: kind}
+	c.mutex.Lock()
+	cached, ok := c.entries[key]
+	c.mutex.Unlock()
+	if ok {
+		return cached.(T), nil
+	}
+
+	loaded, err := loadFn()
+	if err != nil {
+		return loaded, err
+	}
+
+	c.mutex.Lock()
+	c.entries[key] = loaded
+	c.mutex.Unlock()
+	return loaded, nil
+}
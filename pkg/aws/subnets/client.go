@@ -8,6 +8,7 @@ import (
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -18,6 +19,7 @@ type Client interface {
 	Get(ctx context.Context, input GetSubnetsInput) (GetSubnetsOutput, error)
 	Delete(ctx context.Context, input DeleteSubnetsInput) error
 	GetEndpointSubnets(ctx context.Context, input GetEndpointSubnetsInput) ([]string, error)
+	GetAvailabilityZones(ctx context.Context, input GetAvailabilityZonesInput) ([]string, error)
 }
 
 func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Client, error) {
@@ -33,10 +35,16 @@ func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Clien
 		return nil, microerror.Mask(err)
 	}
 
+	snapshotClient, err := snapshot.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	return &client{
 		ec2Client:        ec2Client,
 		assumeRoleClient: assumeRoleClient,
 		tagsClient:       tagsClient,
+		snapshotClient:   snapshotClient,
 	}, nil
 }
 
@@ -44,6 +52,7 @@ type client struct {
 	ec2Client        *ec2.Client
 	tagsClient       tags.Client
 	assumeRoleClient assumerole.Client
+	snapshotClient   snapshot.Client
 }
 
 // TagsToMap converts EC2 tags to map[string]string.
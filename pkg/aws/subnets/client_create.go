@@ -2,12 +2,16 @@ package subnets
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -75,7 +79,33 @@ func (c *client) Create(ctx context.Context, input CreateSubnetInput) (output Cr
 		},
 	}
 
+	if dryrun.Enabled(ctx) {
+		output = CreateSubnetOutput{
+			SubnetId:         dryrun.PlannedId("subnet"),
+			VpcId:            input.VpcId,
+			CidrBlock:        input.CidrBlock,
+			AvailabilityZone: input.AvailabilityZone,
+			State:            SubnetStatePending,
+			Tags:             input.Tags,
+		}
+		operation := dryrun.Operation{
+			Action:     "CreateSubnet",
+			ResourceId: output.SubnetId,
+			Details:    fmt.Sprintf("cidr-block %s, availability-zone %s", input.CidrBlock, input.AvailabilityZone),
+		}
+		err = dryrun.Record(ctx, operation, func() error {
+			ec2Input.DryRun = aws.Bool(true)
+			_, err := c.ec2Client.CreateSubnet(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			return err
+		})
+		if err != nil {
+			return CreateSubnetOutput{}, microerror.Mask(err)
+		}
+		return output, nil
+	}
+
 	ec2Output, err := c.ec2Client.CreateSubnet(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	snapshot.Invalidate(ctx, snapshot.KindSubnets)
 	if err != nil {
 		return CreateSubnetOutput{}, microerror.Mask(err)
 	}
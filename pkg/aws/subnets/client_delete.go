@@ -2,19 +2,26 @@ package subnets
 
 import (
 	"context"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
 type DeleteSubnetsInput struct {
-	RoleARN   string
-	Region    string
-	SubnetIds []string
+	RoleARN     string
+	Region      string
+	SubnetIds   []string
+	ClusterName string
+	ClusterUID  string
 }
 
 func (c *client) Delete(ctx context.Context, input DeleteSubnetsInput) (err error) {
@@ -37,13 +44,63 @@ func (c *client) Delete(ctx context.Context, input DeleteSubnetsInput) (err erro
 	if len(input.SubnetIds) == 0 {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.SubnetIds must not be empty", input)
 	}
+	if input.ClusterName == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", input)
+	}
 
+	// We delete only subnets that we created, so we get their tags first.
+	// Filter is used instead of subnet IDs, so that the call does not fail
+	// when some subnets are already deleted.
+	subnetTags := map[string]map[string]string{}
+	{
+		ec2Input := ec2.DescribeSubnetsInput{
+			Filters: []ec2Types.Filter{
+				{
+					Name:   aws.String("subnet-id"),
+					Values: input.SubnetIds,
+				},
+			},
+		}
+		ec2Output, err := c.ec2Client.DescribeSubnets(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		for _, ec2Subnet := range ec2Output.Subnets {
+			subnetTags[aws.ToString(ec2Subnet.SubnetId)] = TagsToMap(ec2Subnet.Tags)
+		}
+	}
+
+	var notOwnedSubnetIds []string
 	for _, subnetId := range input.SubnetIds {
+		tagsMap, ok := subnetTags[subnetId]
+		if !ok {
+			logger.Info("Subnet not found, nothing to delete", "subnet-id", subnetId)
+			continue
+		}
+		if !tags.IsOwnedBy(tagsMap, input.ClusterName, input.ClusterUID) {
+			logger.Info("Skipped deleting subnet that is not owned by the cluster", "subnet-id", subnetId)
+			notOwnedSubnetIds = append(notOwnedSubnetIds, subnetId)
+			continue
+		}
+
 		logger.Info("Deleting subnet", "subnet-id", subnetId)
 		ec2Input := ec2.DeleteSubnetInput{
 			SubnetId: aws.String(subnetId),
 		}
+		if dryrun.Enabled(ctx) {
+			err = dryrun.Record(ctx, dryrun.Operation{Action: "DeleteSubnet", ResourceId: subnetId}, func() error {
+				ec2Input.DryRun = aws.Bool(true)
+				_, err := c.ec2Client.DeleteSubnet(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+				return err
+			})
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			continue
+		}
 		_, err = c.ec2Client.DeleteSubnet(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		// the route table associations of the subnet are removed with it
+		snapshot.Invalidate(ctx, snapshot.KindSubnets, snapshot.KindRouteTables)
 		if errors.IsSubnetNotFound(err) {
 			logger.Info("Subnet not found, nothing to delete", "subnet-id", subnetId)
 			continue
@@ -53,5 +110,9 @@ func (c *client) Delete(ctx context.Context, input DeleteSubnetsInput) (err erro
 		logger.Info("Deleted subnet", "subnet-id", subnetId)
 	}
 
+	if len(notOwnedSubnetIds) > 0 {
+		return microerror.Maskf(errors.ResourceNotOwnedError, "subnets %s are not owned by cluster %s", strings.Join(notOwnedSubnetIds, ", "), input.ClusterName)
+	}
+
 	return nil
 }
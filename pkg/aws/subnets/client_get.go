@@ -2,23 +2,16 @@ package subnets
 
 import (
 	"context"
-	"fmt"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/giantswarm/microerror"
 	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
-const (
-	filterNameVpcID = "vpc-id"
-	filterNameState = "state"
-)
-
 type GetSubnetsInput struct {
 	RoleARN     string
 	Region      string
@@ -41,7 +34,12 @@ type GetSubnetOutput struct {
 type GetEndpointSubnetsInput struct {
 	RoleARN     string
 	Region      string
+	VpcId       string
 	ClusterName string
+
+	// EndpointSubnetTag is the tag that marks subnets for VPC endpoints, when
+	// set to "true". DefaultEndpointSubnetTag is used when it is not set.
+	EndpointSubnetTag string
 }
 
 func (c *client) Get(ctx context.Context, input GetSubnetsInput) (output GetSubnetsOutput, err error) {
@@ -65,31 +63,24 @@ func (c *client) Get(ctx context.Context, input GetSubnetsInput) (output GetSubn
 		return GetSubnetsOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
 	}
 
+	snapshotInput := snapshot.Input{
+		RoleARN: input.RoleARN,
+		Region:  input.Region,
+		VpcId:   input.VpcId,
+	}
+
 	output = GetSubnetsOutput{}
 
 	//
 	// Get subnet details for all subnets in the VPC
 	//
 	{
-		ec2Input := ec2.DescribeSubnetsInput{
-			Filters: []ec2Types.Filter{
-				{
-					Name:   aws.String(filterNameState),
-					Values: []string{string(ec2Types.SubnetStatePending), string(ec2Types.SubnetStateAvailable)},
-				},
-				{
-					Name:   aws.String(filterNameVpcID),
-					Values: []string{input.VpcId},
-				},
-			},
-		}
-
-		ec2Output, err := c.ec2Client.DescribeSubnets(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		ec2Subnets, err := c.snapshotClient.Subnets(ctx, snapshotInput)
 		if err != nil {
 			return GetSubnetsOutput{}, microerror.Mask(err)
 		}
 
-		for _, ec2Subnet := range ec2Output.Subnets {
+		for _, ec2Subnet := range ec2Subnets {
 			var subnetState SubnetState
 			switch ec2Subnet.State {
 			case ec2Types.SubnetStatePending:
@@ -97,7 +88,8 @@ func (c *client) Get(ctx context.Context, input GetSubnetsInput) (output GetSubn
 			case ec2Types.SubnetStateAvailable:
 				subnetState = SubnetStateAvailable
 			default:
-				subnetState = SubnetStateUnknown
+				// subnets in other states, e.g. failed, are not returned
+				continue
 			}
 
 			subnetOutput := GetSubnetOutput{
@@ -120,21 +112,13 @@ func (c *client) Get(ctx context.Context, input GetSubnetsInput) (output GetSubn
 	// Get route table associations for all subnets
 	//
 	{
-		ec2Input := ec2.DescribeRouteTablesInput{
-			Filters: []ec2Types.Filter{
-				{
-					Name:   aws.String(filterNameVpcID),
-					Values: []string{input.VpcId},
-				},
-			},
-		}
-		ec2Output, err := c.ec2Client.DescribeRouteTables(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		ec2RouteTables, err := c.snapshotClient.RouteTables(ctx, snapshotInput)
 		if err != nil {
 			return GetSubnetsOutput{}, microerror.Mask(err)
 		}
 
 		// Now match route tables to subnets
-		for _, ec2RouteTable := range ec2Output.RouteTables {
+		for _, ec2RouteTable := range ec2RouteTables {
 			if ec2RouteTable.RouteTableId == nil {
 				continue
 			}
@@ -178,20 +162,35 @@ func (c *client) Get(ctx context.Context, input GetSubnetsInput) (output GetSubn
 	return output, nil
 }
 
+// GetEndpointSubnets returns the IDs of the subnets in the VPC that have the
+// cloud provider tag of the cluster and are marked for VPC endpoints.
 func (c *client) GetEndpointSubnets(ctx context.Context, input GetEndpointSubnetsInput) ([]string, error) {
 	subnetIDs := []string{}
-	output, err := c.ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
-		Filters: []ec2Types.Filter{
-			{Name: aws.String(fmt.Sprintf("tag:%s", capa.NameKubernetesAWSCloudProviderPrefix+input.ClusterName)), Values: []string{"owned", "shared"}},
-			{Name: aws.String("tag:subnet.giantswarm.io/endpoints"), Values: []string{"true"}},
-		},
-	}, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	endpointSubnetTag := input.EndpointSubnetTag
+	if endpointSubnetTag == "" {
+		endpointSubnetTag = DefaultEndpointSubnetTag
+	}
+
+	snapshotInput := snapshot.Input{
+		RoleARN: input.RoleARN,
+		Region:  input.Region,
+		VpcId:   input.VpcId,
+	}
+	ec2Subnets, err := c.snapshotClient.Subnets(ctx, snapshotInput)
 	if err != nil {
-		return subnetIDs, err
+		return subnetIDs, microerror.Mask(err)
 	}
 
-	for _, subnet := range output.Subnets {
-		subnetIDs = append(subnetIDs, *subnet.SubnetId)
+	for _, ec2Subnet := range ec2Subnets {
+		subnetTags := TagsToMap(ec2Subnet.Tags)
+		lifecycle := capa.ResourceLifecycle(subnetTags[capa.NameKubernetesAWSCloudProviderPrefix+input.ClusterName])
+		if lifecycle != capa.ResourceLifecycleOwned && lifecycle != capa.ResourceLifecycleShared {
+			continue
+		}
+		if subnetTags[endpointSubnetTag] != "true" {
+			continue
+		}
+		subnetIDs = append(subnetIDs, *ec2Subnet.SubnetId)
 	}
 
 	return subnetIDs, nil
@@ -2,6 +2,7 @@ package subnets
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -9,6 +10,8 @@ import (
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -19,6 +22,13 @@ type UpdateSubnetInput struct {
 	SubnetId     string
 	RouteTableId *string
 	Tags         map[string]string
+
+	// CurrentTags are the tags that the subnet currently has.
+	CurrentTags map[string]string
+
+	// ManagedTagKeys are the keys of the tags that were previously applied
+	// by the operator. They are deleted when they are not in Tags anymore.
+	ManagedTagKeys []string
 }
 
 type UpdateSubnetOutput struct {
@@ -47,13 +57,16 @@ func (c *client) Update(ctx context.Context, input UpdateSubnetInput) (output Up
 	}
 
 	// update subnet tags
-	createTagsInput := tags.CreateTagsInput{
-		RoleARN:    input.RoleARN,
-		Region:     input.Region,
-		ResourceId: input.SubnetId,
-		Tags:       input.Tags,
+	reconcileTagsInput := tags.ReconcileTagsInput{
+		RoleARN:     input.RoleARN,
+		Region:      input.Region,
+		ResourceId:  input.SubnetId,
+		CurrentTags: input.CurrentTags,
+		WantedTags:  input.Tags,
+		ManagedKeys: input.ManagedTagKeys,
 	}
-	err = c.tagsClient.Create(ctx, createTagsInput)
+	err = c.tagsClient.Reconcile(ctx, reconcileTagsInput)
+	snapshot.Invalidate(ctx, snapshot.KindSubnets)
 	if err != nil {
 		return UpdateSubnetOutput{}, microerror.Mask(err)
 	}
@@ -140,7 +153,17 @@ func (c *client) updateAssociatedRouteTable(ctx context.Context, input UpdateSub
 		ec2Input := ec2.DisassociateRouteTableInput{
 			AssociationId: aws.String(existingAssociationId),
 		}
-		_, err := c.ec2Client.DisassociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		var err error
+		if dryrun.Enabled(ctx) {
+			err = dryrun.Record(ctx, dryrun.Operation{Action: "DisassociateRouteTable", ResourceId: existingAssociationId}, func() error {
+				ec2Input.DryRun = aws.Bool(true)
+				_, err := c.ec2Client.DisassociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+				return err
+			})
+		} else {
+			_, err = c.ec2Client.DisassociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			snapshot.Invalidate(ctx, snapshot.KindRouteTables)
+		}
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -153,7 +176,32 @@ func (c *client) updateAssociatedRouteTable(ctx context.Context, input UpdateSub
 		RouteTableId: input.RouteTableId,
 		SubnetId:     aws.String(input.SubnetId),
 	}
+	if dryrun.Enabled(ctx) {
+		operation := dryrun.Operation{
+			Action:     "AssociateRouteTable",
+			ResourceId: *input.RouteTableId,
+			Details:    fmt.Sprintf("subnet %s", input.SubnetId),
+		}
+		var check func() error
+		if !dryrun.IsPlannedId(*input.RouteTableId) {
+			check = func() error {
+				ec2Input.DryRun = aws.Bool(true)
+				_, err := c.ec2Client.AssociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+				return err
+			}
+		}
+		err := dryrun.Record(ctx, operation, check)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		return &RouteTableAssociation{
+			RouteTableId:         *input.RouteTableId,
+			AssociationStateCode: AssociationStateCodeAssociating,
+		}, nil
+	}
+
 	ec2Output, err := c.ec2Client.AssociateRouteTable(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	snapshot.Invalidate(ctx, snapshot.KindRouteTables)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
@@ -0,0 +1,65 @@
+package subnets
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+type GetAvailabilityZonesInput struct {
+	RoleARN string
+	Region  string
+}
+
+// GetAvailabilityZones returns the sorted names of the availability zones of
+// the region that are available to the account, without Local Zones and
+// Wavelength Zones.
+func (c *client) GetAvailabilityZones(ctx context.Context, input GetAvailabilityZonesInput) (zones []string, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started getting availability zones")
+	defer func() {
+		if err == nil {
+			logger.Info("Finished getting availability zones", "availability-zones", zones)
+		} else {
+			logger.Error(err, "Failed to get availability zones")
+		}
+	}()
+
+	if input.RoleARN == "" {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+
+	ec2Input := ec2.DescribeAvailabilityZonesInput{
+		Filters: []ec2Types.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: []string{string(ec2Types.AvailabilityZoneStateAvailable)},
+			},
+			{
+				Name:   aws.String("zone-type"),
+				Values: []string{"availability-zone"},
+			},
+		},
+	}
+	ec2Output, err := c.ec2Client.DescribeAvailabilityZones(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	for _, zone := range ec2Output.AvailabilityZones {
+		zones = append(zones, aws.ToString(zone.ZoneName))
+	}
+	sort.Strings(zones)
+
+	return zones, nil
+}
@@ -2,17 +2,24 @@ package subnets
 
 import (
 	"context"
-	"strings"
 
 	"github.com/giantswarm/microerror"
 	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	capaservices "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/naming"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
+const (
+	DefaultEndpointSubnetTag       = "subnet.giantswarm.io/endpoints"
+	DefaultInternalLoadBalancerTag = "kubernetes.io/role/internal-elb"
+)
+
+var defaultNameTemplate = naming.MustParse(naming.DefaultSubnet)
+
 type Reconciler interface {
 	Reconcile(ctx context.Context, request ReconcileRequest) (ReconcileResult, error)
 	ReconcileDelete(ctx context.Context, request aws.ReconcileRequest[[]aws.DeletedCloudResourceSpec]) error
@@ -45,17 +52,21 @@ func findExistingSubnet(existingSubnets GetSubnetsOutput, desiredSubnet SubnetSp
 	return GetSubnetOutput{}, false
 }
 
-func (r *reconciler) getSubnetTags(clusterName string, clusterTags map[string]string, spec SubnetSpec) map[string]string {
+func (r *reconciler) getSubnetTags(clusterSpec Spec, spec SubnetSpec) map[string]string {
 	var role string
+	clusterName := clusterSpec.ClusterName
 
 	// 1. set cluster-wide tags (coming from AWSCluster.AdditionalTags)
 	allSubnetTags := make(map[string]string)
-	for k, v := range clusterTags {
+	for k, v := range clusterSpec.AdditionalTags {
 		allSubnetTags[k] = v
 	}
 
 	// 2. set load balancer tags
-	const internalLoadBalancerTag = "kubernetes.io/role/internal-elb"
+	internalLoadBalancerTag := clusterSpec.InternalLoadBalancerTag
+	if internalLoadBalancerTag == "" {
+		internalLoadBalancerTag = DefaultInternalLoadBalancerTag
+	}
 	role = capa.PrivateRoleTagValue
 	allSubnetTags[internalLoadBalancerTag] = "1"
 	// Add tag needed for Service type=LoadBalancer
@@ -69,15 +80,13 @@ func (r *reconciler) getSubnetTags(clusterName string, clusterTags map[string]st
 	// 4. finally, build all tags with tag builder which also sets predefined/fixed tags
 
 	// Prefer `Name` tag if given, else generate a name
-	var name strings.Builder
-	if manualTagName, ok := spec.Tags["Name"]; ok {
-		name.WriteString(manualTagName)
-	} else {
-		name.WriteString(clusterName)
-		name.WriteString("-subnet-")
-		name.WriteString(role)
-		name.WriteString("-")
-		name.WriteString(spec.AvailabilityZone)
+	name, ok := spec.Tags["Name"]
+	if !ok {
+		name = clusterSpec.NameTemplate.Or(defaultNameTemplate).Name(naming.Params{
+			ClusterName:      clusterName,
+			AvailabilityZone: spec.AvailabilityZone,
+			Role:             role,
+		})
 	}
 
 	id := spec.SubnetId
@@ -87,8 +96,9 @@ func (r *reconciler) getSubnetTags(clusterName string, clusterTags map[string]st
 
 	params := tags.BuildParams{
 		ClusterName: clusterName,
+		ClusterUID:  clusterSpec.ClusterUID,
 		ResourceID:  id,
-		Name:        name.String(),
+		Name:        name,
 		Role:        role,
 		Additional:  allSubnetTags,
 	}
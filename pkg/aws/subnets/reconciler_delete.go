@@ -8,12 +8,22 @@ import (
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
 )
 
 func (r *reconciler) ReconcileDelete(ctx context.Context, request aws.ReconcileRequest[[]aws.DeletedCloudResourceSpec]) (err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Started reconciling subnets deletion")
+	var subnetIds []string
+	for _, spec := range request.Spec {
+		subnetIds = append(subnetIds, spec.Id)
+	}
+	ctx, span := tracing.Start(ctx, "subnets.ReconcileDelete",
+		tracing.ClusterNameKey.String(request.ClusterName),
+		tracing.RegionKey.String(request.Region),
+		tracing.SubnetIdsKey.StringSlice(subnetIds))
 	defer func() {
+		tracing.End(span, err)
 		if err == nil {
 			logger.Info("Finished reconciling subnets deletion")
 		} else {
@@ -35,8 +45,10 @@ func (r *reconciler) ReconcileDelete(ctx context.Context, request aws.ReconcileR
 	}
 
 	deleteSubnetsInput := DeleteSubnetsInput{
-		RoleARN: request.RoleARN,
-		Region:  request.Region,
+		RoleARN:     request.RoleARN,
+		Region:      request.Region,
+		ClusterName: request.ClusterName,
+		ClusterUID:  request.ClusterUID,
 	}
 	for _, spec := range request.Spec {
 		deleteSubnetsInput.SubnetIds = append(deleteSubnetsInput.SubnetIds, spec.Id)
@@ -7,14 +7,26 @@ import (
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/naming"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
 )
 
 func (r *reconciler) Reconcile(ctx context.Context, request ReconcileRequest) (result ReconcileResult, err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Started reconciling subnets")
+	ctx, span := tracing.Start(ctx, "subnets.Reconcile",
+		tracing.ClusterNameKey.String(request.Spec.ClusterName),
+		tracing.RegionKey.String(request.Spec.Region),
+		tracing.VpcIdKey.String(request.Spec.VpcId))
 	defer func() {
+		var subnetIds []string
+		for _, subnet := range result.Subnets {
+			subnetIds = append(subnetIds, subnet.SubnetId)
+		}
+		span.SetAttributes(tracing.SubnetIdsKey.StringSlice(subnetIds))
+		tracing.End(span, err)
 		if err == nil {
 			logger.Info("Finished reconciling subnets")
 		} else {
@@ -59,11 +71,16 @@ func (r *reconciler) Reconcile(ctx context.Context, request ReconcileRequest) (r
 		return ReconcileResult{}, microerror.Mask(err)
 	}
 
+	desiredSubnets, err := r.resolveAvailabilityZones(ctx, spec, existingSubnets)
+	if err != nil {
+		return ReconcileResult{}, microerror.Mask(err)
+	}
+
 	//
 	// Now when we know the desired and existing (actual) state, let's reconcile
 	// those two sets of subnets.
 	//
-	for _, desiredSubnet := range request.Spec.Subnets {
+	for _, desiredSubnet := range desiredSubnets {
 		if existingSubnet, found := findExistingSubnet(existingSubnets, desiredSubnet); found {
 			//
 			// Existing subnet found
@@ -75,10 +92,15 @@ func (r *reconciler) Reconcile(ctx context.Context, request ReconcileRequest) (r
 				desiredSubnet.SubnetId = existingSubnet.SubnetId
 			}
 			// ... check tags
-			desiredSubnetTags := r.getSubnetTags(request.Spec.ClusterName, request.Spec.AdditionalTags, desiredSubnet)
+			desiredSubnetTags := r.getSubnetTags(request.Spec, desiredSubnet)
 
-			changedOrNewTags := tags.Diff(desiredSubnetTags, existingSubnet.Tags)
-			if len(changedOrNewTags) > 0 {
+			changedOrNewTags, removedTagKeys := tags.Changes(existingSubnet.Tags, desiredSubnetTags, spec.LastAppliedTagKeys)
+			if !tags.IsOwnedBy(existingSubnet.Tags, spec.ClusterName, spec.ClusterUID) {
+				// Subnets that we did not create are never changed, as
+				// setting our tags would take over their ownership.
+				logger.Info("Skipped updating tags of subnet that is not owned by the cluster", "subnet-id", existingSubnet.SubnetId)
+				desiredSubnetTags = existingSubnet.Tags
+			} else if len(changedOrNewTags) > 0 || len(removedTagKeys) > 0 {
 				//
 				// Update existing subnet with new tags.
 				//
@@ -87,6 +109,9 @@ func (r *reconciler) Reconcile(ctx context.Context, request ReconcileRequest) (r
 					Region:   spec.Region,
 					SubnetId: existingSubnet.SubnetId,
 					Tags:     desiredSubnetTags,
+
+					CurrentTags:    existingSubnet.Tags,
+					ManagedTagKeys: spec.LastAppliedTagKeys,
 				}
 				_, err = r.client.Update(ctx, updateSubnetInput)
 				if err != nil {
@@ -108,13 +133,17 @@ func (r *reconciler) Reconcile(ctx context.Context, request ReconcileRequest) (r
 			//
 			// Desired subnet not found, let's create it.
 			//
+			// CreateSubnet does not support client tokens. A subnet that was
+			// created, but whose ID was never written to the AWSCluster, is
+			// still found above by its CIDR block, so it is not created twice.
+			//
 			createSubnetInput := CreateSubnetInput{
 				RoleARN:          spec.RoleARN,
 				Region:           spec.Region,
 				VpcId:            spec.VpcId,
 				CidrBlock:        desiredSubnet.CidrBlock,
 				AvailabilityZone: desiredSubnet.AvailabilityZone,
-				Tags:             r.getSubnetTags(request.Spec.ClusterName, request.Spec.AdditionalTags, desiredSubnet),
+				Tags:             r.getSubnetTags(request.Spec, desiredSubnet),
 			}
 			output, err := r.client.Create(ctx, createSubnetInput)
 			if err != nil {
@@ -143,6 +172,58 @@ func (r *reconciler) Reconcile(ctx context.Context, request ReconcileRequest) (r
 	return result, nil
 }
 
+// resolveAvailabilityZones returns the desired subnets, where each new subnet
+// without an availability zone, e.g. one defaulted by the webhook, gets the
+// zone of the region with the fewest subnets of the cluster, so that subnets
+// are spread over the zones. Zones are only described when there are such
+// subnets.
+func (r *reconciler) resolveAvailabilityZones(ctx context.Context, spec Spec, existingSubnets GetSubnetsOutput) ([]SubnetSpec, error) {
+	logger := log.FromContext(ctx)
+	desiredSubnets := append([]SubnetSpec{}, spec.Subnets...)
+
+	var unresolved []int
+	subnetsPerZone := map[string]int{}
+	for i, desiredSubnet := range desiredSubnets {
+		if existingSubnet, found := findExistingSubnet(existingSubnets, desiredSubnet); found {
+			subnetsPerZone[existingSubnet.AvailabilityZone]++
+		} else if desiredSubnet.AvailabilityZone != "" {
+			subnetsPerZone[desiredSubnet.AvailabilityZone]++
+		} else {
+			unresolved = append(unresolved, i)
+		}
+	}
+	if len(unresolved) == 0 {
+		return desiredSubnets, nil
+	}
+
+	zonesInput := GetAvailabilityZonesInput{
+		RoleARN: spec.RoleARN,
+		Region:  spec.Region,
+	}
+	zones, err := r.client.GetAvailabilityZones(ctx, zonesInput)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if len(zones) == 0 {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "region %s has no available availability zones", spec.Region)
+	}
+
+	for _, i := range unresolved {
+		// zones are sorted, so ties go to the first zone by name
+		zone := zones[0]
+		for _, candidate := range zones[1:] {
+			if subnetsPerZone[candidate] < subnetsPerZone[zone] {
+				zone = candidate
+			}
+		}
+		subnetsPerZone[zone]++
+		desiredSubnets[i].AvailabilityZone = zone
+		logger.Info("Selected availability zone for new subnet", "cidr-block", desiredSubnets[i].CidrBlock, "availability-zone", zone)
+	}
+
+	return desiredSubnets, nil
+}
+
 // ReconcileRequest specified which resource is being reconciled and what is
 // the specification of the desired subnets.
 type ReconcileRequest struct {
@@ -155,11 +236,25 @@ type ReconcileRequest struct {
 
 type Spec struct {
 	ClusterName    string
+	ClusterUID     string
 	RoleARN        string
 	Region         string
 	VpcId          string
 	Subnets        []SubnetSpec
 	AdditionalTags map[string]string
+
+	// LastAppliedTagKeys are the keys of AdditionalTags that were applied to
+	// the subnets before. Those that are not in AdditionalTags anymore are
+	// removed from the subnets.
+	LastAppliedTagKeys []string
+
+	// NameTemplate renders Name tags of subnets that do not have a Name tag
+	// in their spec. naming.DefaultSubnet is used when it is not set.
+	NameTemplate naming.Template
+
+	// InternalLoadBalancerTag is the tag that marks subnets for internal load
+	// balancers. DefaultInternalLoadBalancerTag is used when it is not set.
+	InternalLoadBalancerTag string
 }
 
 type SubnetSpec struct {
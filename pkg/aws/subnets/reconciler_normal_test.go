@@ -0,0 +1,128 @@
+package subnets_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/subnets"
+)
+
+func Test_Reconcile_SelectsAvailabilityZones(t *testing.T) {
+	testCases := []struct {
+		name string
+		// zones of the new subnets 10.0.64.0/18 and 10.0.128.0/18
+		specZones             []string
+		expectedZones         []string
+		expectedDescribeCalls int
+	}{
+		{
+			name:                  "case 0: new subnets without zones are spread over the zones",
+			specZones:             []string{"", ""},
+			expectedZones:         []string{"eu-west-1b", "eu-west-1c"},
+			expectedDescribeCalls: 1,
+		},
+		{
+			name:                  "case 1: zones from the spec are counted",
+			specZones:             []string{"eu-west-1b", ""},
+			expectedZones:         []string{"eu-west-1b", "eu-west-1c"},
+			expectedDescribeCalls: 1,
+		},
+		{
+			name:          "case 2: zones are not described when all subnets have zones",
+			specZones:     []string{"eu-west-1a", "eu-west-1a"},
+			expectedZones: []string{"eu-west-1a", "eu-west-1a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var createdZones []string
+			fakeEC2 := awstest.NewEC2().
+				On("DescribeSubnets", func(interface{}) (interface{}, error) {
+					// the first subnet was already created
+					return &ec2.DescribeSubnetsOutput{
+						Subnets: []ec2Types.Subnet{
+							{
+								SubnetId:         aws.String("subnet-1"),
+								VpcId:            aws.String("vpc-1"),
+								CidrBlock:        aws.String("10.0.0.0/18"),
+								AvailabilityZone: aws.String("eu-west-1a"),
+								State:            ec2Types.SubnetStateAvailable,
+							},
+						},
+					}, nil
+				}).
+				On("DescribeRouteTables", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeRouteTablesOutput{}, nil
+				}).
+				On("DescribeAvailabilityZones", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeAvailabilityZonesOutput{
+						AvailabilityZones: []ec2Types.AvailabilityZone{
+							{ZoneName: aws.String("eu-west-1c")},
+							{ZoneName: aws.String("eu-west-1a")},
+							{ZoneName: aws.String("eu-west-1b")},
+						},
+					}, nil
+				}).
+				On("CreateSubnet", func(input interface{}) (interface{}, error) {
+					createInput := input.(*ec2.CreateSubnetInput)
+					createdZones = append(createdZones, aws.ToString(createInput.AvailabilityZone))
+					return &ec2.CreateSubnetOutput{
+						Subnet: &ec2Types.Subnet{
+							SubnetId:         aws.String("subnet-new"),
+							VpcId:            createInput.VpcId,
+							CidrBlock:        createInput.CidrBlock,
+							AvailabilityZone: createInput.AvailabilityZone,
+							State:            ec2Types.SubnetStatePending,
+						},
+					}, nil
+				})
+
+			client, err := subnets.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			reconciler, err := subnets.NewReconciler(client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request := subnets.ReconcileRequest{
+				Resource: &capa.AWSCluster{},
+				Spec: subnets.Spec{
+					ClusterName: "test",
+					RoleARN:     awstest.RoleARN,
+					Region:      awstest.Region,
+					VpcId:       "vpc-1",
+					Subnets: []subnets.SubnetSpec{
+						{SubnetId: "subnet-1", CidrBlock: "10.0.0.0/18", AvailabilityZone: "eu-west-1a"},
+						{CidrBlock: "10.0.64.0/18", AvailabilityZone: tc.specZones[0]},
+						{CidrBlock: "10.0.128.0/18", AvailabilityZone: tc.specZones[1]},
+					},
+				},
+			}
+			_, err = reconciler.Reconcile(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(createdZones) != len(tc.expectedZones) {
+				t.Fatalf("expected subnets in zones %v to be created, got %v", tc.expectedZones, createdZones)
+			}
+			for i := range createdZones {
+				if createdZones[i] != tc.expectedZones[i] {
+					t.Fatalf("expected subnets in zones %v to be created, got %v", tc.expectedZones, createdZones)
+				}
+			}
+			if calls := fakeEC2.Calls("DescribeAvailabilityZones"); calls != tc.expectedDescribeCalls {
+				t.Errorf("expected %d DescribeAvailabilityZones calls, got %d", tc.expectedDescribeCalls, calls)
+			}
+		})
+	}
+}
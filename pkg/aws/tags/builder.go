@@ -1,12 +1,20 @@
 package tags
 
 import (
+	"sort"
+	"strings"
+
 	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 )
 
 const (
 	NameAWSProviderPrefix = "github.com/giantswarm/aws-vpc-operator/"
 	NameAWSRole           = NameAWSProviderPrefix + "role"
+
+	// NameAWSClusterUID is the UID of the AWSCluster that owns the resource.
+	// The ownership tag only has the cluster name, which is not unique across
+	// namespaces and management clusters that share an AWS account.
+	NameAWSClusterUID = NameAWSProviderPrefix + "cluster-uid"
 )
 
 // BuildParams is used to build tags around an aws resource.
@@ -16,6 +24,9 @@ type BuildParams struct {
 	// ClusterName is the cluster associated with the resource.
 	ClusterName string
 
+	// ClusterUID is the UID of the AWSCluster associated with the resource.
+	ClusterUID string
+
 	// ResourceID is the unique identifier of the resource to be tagged.
 	ResourceID string
 
@@ -51,6 +62,37 @@ func (p BuildParams) Build() map[string]string {
 	}
 
 	tags[NameAWSProviderPrefix+p.ClusterName] = "owned"
+	if p.ClusterUID != "" {
+		tags[NameAWSClusterUID] = p.ClusterUID
+	}
 
 	return tags
 }
+
+// IsOwnedBy checks if the specified tags mark the resource as created and owned
+// by aws-vpc-operator for the specified cluster. A resource with the UID tag
+// of another AWSCluster with the same name is not owned. Resources created
+// before the UID tag was added are owned by name only.
+func IsOwnedBy(tags map[string]string, clusterName, clusterUID string) bool {
+	if tags[NameAWSProviderPrefix+clusterName] != string(capa.ResourceLifecycleOwned) {
+		return false
+	}
+	ownerUID := tags[NameAWSClusterUID]
+	return clusterUID == "" || ownerUID == "" || ownerUID == clusterUID
+}
+
+// OwnerClusterNames returns the sorted names of all clusters for which the
+// specified tags mark the resource as created and owned by aws-vpc-operator.
+func OwnerClusterNames(tags map[string]string) []string {
+	var clusterNames []string
+	for key, value := range tags {
+		if key == NameAWSRole || value != string(capa.ResourceLifecycleOwned) {
+			continue
+		}
+		if clusterName, ok := strings.CutPrefix(key, NameAWSProviderPrefix); ok && clusterName != "" {
+			clusterNames = append(clusterNames, clusterName)
+		}
+	}
+	sort.Strings(clusterNames)
+	return clusterNames
+}
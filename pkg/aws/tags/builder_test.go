@@ -0,0 +1,54 @@
+package tags_test
+
+import (
+	"testing"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+)
+
+func Test_IsOwnedBy(t *testing.T) {
+	built := tags.BuildParams{ClusterName: "test", ClusterUID: "uid-1"}.Build()
+
+	testCases := []struct {
+		name          string
+		tags          map[string]string
+		clusterName   string
+		clusterUID    string
+		expectedOwned bool
+	}{
+		{
+			name:          "case 0: owned by the cluster",
+			tags:          built,
+			clusterName:   "test",
+			clusterUID:    "uid-1",
+			expectedOwned: true,
+		},
+		{
+			name:        "case 1: owned by another cluster with the same name",
+			tags:        built,
+			clusterName: "test",
+			clusterUID:  "uid-2",
+		},
+		{
+			name:        "case 2: owned by another cluster",
+			tags:        built,
+			clusterName: "other",
+			clusterUID:  "uid-1",
+		},
+		{
+			name:          "case 3: created before the UID tag was added",
+			tags:          tags.BuildParams{ClusterName: "test"}.Build(),
+			clusterName:   "test",
+			clusterUID:    "uid-1",
+			expectedOwned: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if owned := tags.IsOwnedBy(tc.tags, tc.clusterName, tc.clusterUID); owned != tc.expectedOwned {
+				t.Errorf("expected owned %t, got %t", tc.expectedOwned, owned)
+			}
+		})
+	}
+}
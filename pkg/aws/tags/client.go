@@ -12,6 +12,8 @@ import (
 
 type Client interface {
 	Create(ctx context.Context, input CreateTagsInput) error
+	Delete(ctx context.Context, input DeleteTagsInput) error
+	Reconcile(ctx context.Context, input ReconcileTagsInput) error
 }
 
 func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Client, error) {
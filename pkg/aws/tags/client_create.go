@@ -3,6 +3,7 @@ package tags
 import (
 	"context"
 	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -10,6 +11,7 @@ import (
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
@@ -34,6 +36,10 @@ func (c *client) Create(ctx context.Context, input CreateTagsInput) error {
 	if input.ResourceId == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.ResourceId must not be empty", input)
 	}
+	err := Validate(input.Tags)
+	if err != nil {
+		return microerror.Mask(err)
+	}
 
 	// For testing, we need sorted keys
 	sortedKeys := make([]string, 0, len(input.Tags))
@@ -42,7 +48,7 @@ func (c *client) Create(ctx context.Context, input CreateTagsInput) error {
 	}
 	sort.Strings(sortedKeys)
 
-	tags := make([]ec2Types.Tag, len(input.Tags))
+	tags := make([]ec2Types.Tag, 0, len(input.Tags))
 	for _, key := range sortedKeys {
 		tags = append(tags,
 			ec2Types.Tag{
@@ -56,7 +62,23 @@ func (c *client) Create(ctx context.Context, input CreateTagsInput) error {
 		Tags:      tags,
 	}
 
-	_, err := c.ec2Client.CreateTags(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	if dryrun.Enabled(ctx) {
+		operation := dryrun.Operation{
+			Action:     "CreateTags",
+			ResourceId: input.ResourceId,
+			Details:    strings.Join(sortedKeys, ", "),
+		}
+		var check func() error
+		if !dryrun.IsPlannedId(input.ResourceId) {
+			check = func() error {
+				ec2Input.DryRun = aws.Bool(true)
+				_, err := c.ec2Client.CreateTags(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+				return err
+			}
+		}
+		return microerror.Mask(dryrun.Record(ctx, operation, check))
+	}
+	_, err = c.ec2Client.CreateTags(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
 	if err != nil {
 		return microerror.Mask(err)
 	}
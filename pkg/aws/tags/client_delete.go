@@ -0,0 +1,82 @@
+package tags
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+type DeleteTagsInput struct {
+	RoleARN    string
+	Region     string
+	ResourceId string
+	Keys       []string
+}
+
+func (c *client) Delete(ctx context.Context, input DeleteTagsInput) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Started deleting tags", "keys", input.Keys)
+	defer logger.Info("Finished deleting tags")
+
+	if input.RoleARN == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.ResourceId == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.ResourceId must not be empty", input)
+	}
+	if len(input.Keys) == 0 {
+		return nil
+	}
+
+	// For testing, we need sorted keys
+	sortedKeys := append([]string{}, input.Keys...)
+	sort.Strings(sortedKeys)
+
+	tags := make([]ec2Types.Tag, 0, len(sortedKeys))
+	for _, key := range sortedKeys {
+		// without a value, the tag is deleted regardless of its value
+		tags = append(tags, ec2Types.Tag{
+			Key: aws.String(key),
+		})
+	}
+
+	ec2Input := ec2.DeleteTagsInput{
+		Resources: []string{input.ResourceId},
+		Tags:      tags,
+	}
+
+	if dryrun.Enabled(ctx) {
+		operation := dryrun.Operation{
+			Action:     "DeleteTags",
+			ResourceId: input.ResourceId,
+			Details:    strings.Join(sortedKeys, ", "),
+		}
+		var check func() error
+		if !dryrun.IsPlannedId(input.ResourceId) {
+			check = func() error {
+				ec2Input.DryRun = aws.Bool(true)
+				_, err := c.ec2Client.DeleteTags(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+				return err
+			}
+		}
+		return microerror.Mask(dryrun.Record(ctx, operation, check))
+	}
+	_, err := c.ec2Client.DeleteTags(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package tags
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+type ReconcileTagsInput struct {
+	RoleARN    string
+	Region     string
+	ResourceId string
+
+	// CurrentTags are the tags that the resource currently has.
+	CurrentTags map[string]string
+
+	// WantedTags are the tags that the resource should have. Tags that the
+	// resource has, but that are not wanted, are kept unless they are in
+	// ManagedKeys.
+	WantedTags map[string]string
+
+	// ManagedKeys are the keys of the tags that were previously applied by
+	// the operator, and that are deleted when they are not wanted anymore.
+	ManagedKeys []string
+}
+
+// Reconcile creates, updates and deletes tags of the resource, so that it has
+// the wanted tags. The resulting tags are validated against AWS limits before
+// any change is made.
+func (c *client) Reconcile(ctx context.Context, input ReconcileTagsInput) error {
+	logger := log.FromContext(ctx)
+
+	if input.RoleARN == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.ResourceId == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.ResourceId must not be empty", input)
+	}
+
+	createOrUpdate, deleteKeys := Changes(input.CurrentTags, input.WantedTags, input.ManagedKeys)
+	if len(createOrUpdate) == 0 && len(deleteKeys) == 0 {
+		logger.Info("Tags are already up-to-date", "resource-id", input.ResourceId)
+		return nil
+	}
+
+	err := Validate(createOrUpdate)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// the limit applies to all tags of the resource, including those that
+	// were not added by the operator
+	err = validateCount(Apply(input.CurrentTags, createOrUpdate, deleteKeys))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// tags are deleted first, so that the limit is not exceeded while
+	// creating the new tags
+	if len(deleteKeys) > 0 {
+		deleteTagsInput := DeleteTagsInput{
+			RoleARN:    input.RoleARN,
+			Region:     input.Region,
+			ResourceId: input.ResourceId,
+			Keys:       deleteKeys,
+		}
+		err = c.Delete(ctx, deleteTagsInput)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	if len(createOrUpdate) > 0 {
+		createTagsInput := CreateTagsInput{
+			RoleARN:    input.RoleARN,
+			Region:     input.Region,
+			ResourceId: input.ResourceId,
+			Tags:       createOrUpdate,
+		}
+		err = c.Create(ctx, createTagsInput)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
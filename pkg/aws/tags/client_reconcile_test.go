@@ -0,0 +1,120 @@
+package tags_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+func Test_Reconcile(t *testing.T) {
+	manyTags := map[string]string{}
+	for i := 0; i < tags.MaxTagsPerResource; i++ {
+		manyTags[fmt.Sprintf("user-%d", i)] = "value"
+	}
+
+	testCases := []struct {
+		name            string
+		currentTags     map[string]string
+		wantedTags      map[string]string
+		managedKeys     []string
+		expectedCreated []string
+		expectedDeleted []string
+		errorMatcher    func(error) bool
+	}{
+		{
+			name:        "case 0: up-to-date tags are not changed",
+			currentTags: map[string]string{"team": "a", "user": "tag"},
+			wantedTags:  map[string]string{"team": "a"},
+			managedKeys: []string{"team"},
+		},
+		{
+			name:            "case 1: changed tags are updated and removed managed tags are deleted",
+			currentTags:     map[string]string{"team": "a", "env": "prod", "user": "tag"},
+			wantedTags:      map[string]string{"team": "b"},
+			managedKeys:     []string{"team", "env"},
+			expectedCreated: []string{"team"},
+			expectedDeleted: []string{"env"},
+		},
+		{
+			name:         "case 2: reserved prefix is rejected",
+			wantedTags:   map[string]string{"aws:team": "a"},
+			errorMatcher: errors.IsInvalidTags,
+		},
+		{
+			name:         "case 3: too long value is rejected",
+			wantedTags:   map[string]string{"team": strings.Repeat("a", tags.MaxValueLength+1)},
+			errorMatcher: errors.IsInvalidTags,
+		},
+		{
+			name:         "case 4: too many tags including user tags are rejected",
+			currentTags:  manyTags,
+			wantedTags:   map[string]string{"team": "a"},
+			errorMatcher: errors.IsInvalidTags,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeEC2 := awstest.NewEC2().
+				On("CreateTags", func(interface{}) (interface{}, error) {
+					return &ec2.CreateTagsOutput{}, nil
+				}).
+				On("DeleteTags", func(interface{}) (interface{}, error) {
+					return &ec2.DeleteTagsOutput{}, nil
+				})
+
+			client, err := tags.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = client.Reconcile(context.Background(), tags.ReconcileTagsInput{
+				RoleARN:     awstest.RoleARN,
+				Region:      awstest.Region,
+				ResourceId:  "vpc-1",
+				CurrentTags: tc.currentTags,
+				WantedTags:  tc.wantedTags,
+				ManagedKeys: tc.managedKeys,
+			})
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("error not matching expected matcher, got %v", err)
+				}
+				if len(fakeEC2.AllCalls()) > 0 {
+					t.Errorf("expected no EC2 calls for invalid tags, got %d", len(fakeEC2.AllCalls()))
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var created, deleted []string
+			for _, call := range fakeEC2.AllCalls() {
+				switch input := call.Input.(type) {
+				case *ec2.CreateTagsInput:
+					for _, tag := range input.Tags {
+						created = append(created, aws.ToString(tag.Key))
+					}
+				case *ec2.DeleteTagsInput:
+					for _, tag := range input.Tags {
+						deleted = append(deleted, aws.ToString(tag.Key))
+					}
+				}
+			}
+			if fmt.Sprint(created) != fmt.Sprint(tc.expectedCreated) {
+				t.Errorf("expected created tags %v, got %v", tc.expectedCreated, created)
+			}
+			if fmt.Sprint(deleted) != fmt.Sprint(tc.expectedDeleted) {
+				t.Errorf("expected deleted tags %v, got %v", tc.expectedDeleted, deleted)
+			}
+		})
+	}
+}
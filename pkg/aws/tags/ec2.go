@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 )
 
 // BuildParamsToTagSpecification builds a TagSpecification for the specified resource type.
@@ -40,3 +41,22 @@ func ToMap(src []ec2Types.Tag) map[string]string {
 
 	return tags
 }
+
+// OwnedByAnyFilter returns an EC2 filter that matches resources that have any
+// aws-vpc-operator tag, e.g. to find resources of all clusters in an account.
+// The owning clusters are found with OwnerClusterNames.
+func OwnedByAnyFilter() ec2Types.Filter {
+	return ec2Types.Filter{
+		Name:   aws.String("tag-key"),
+		Values: []string{NameAWSProviderPrefix + "*"},
+	}
+}
+
+// OwnedByFilter returns an EC2 filter that matches resources created and owned
+// by aws-vpc-operator for the specified cluster.
+func OwnedByFilter(clusterName string) ec2Types.Filter {
+	return ec2Types.Filter{
+		Name:   aws.String("tag:" + NameAWSProviderPrefix + clusterName),
+		Values: []string{string(capa.ResourceLifecycleOwned)},
+	}
+}
@@ -1,5 +1,9 @@
 package tags
 
+import (
+	"sort"
+)
+
 // Diff returns a map with key-value pairs exist in t1, but do not exist in
 // t2.
 func Diff(t1 map[string]string, t2 map[string]string) map[string]string {
@@ -20,3 +24,40 @@ func Diff(t1 map[string]string, t2 map[string]string) map[string]string {
 
 	return diff
 }
+
+// Changes returns the tags that have to be created or updated, and the keys of
+// the tags that have to be deleted, so that the current tags match the wanted
+// tags. Only tags whose keys are in managedKeys are deleted, so that tags
+// added by users or other controllers are preserved.
+func Changes(current, wanted map[string]string, managedKeys []string) (createOrUpdate map[string]string, deleteKeys []string) {
+	createOrUpdate = Diff(wanted, current)
+
+	for _, key := range managedKeys {
+		if _, isWanted := wanted[key]; isWanted {
+			continue
+		}
+		if _, exists := current[key]; exists {
+			deleteKeys = append(deleteKeys, key)
+		}
+	}
+	sort.Strings(deleteKeys)
+
+	return createOrUpdate, deleteKeys
+}
+
+// Apply returns the tags that the resource has after the changes returned by
+// Changes are made.
+func Apply(current, createOrUpdate map[string]string, deleteKeys []string) map[string]string {
+	result := make(map[string]string, len(current)+len(createOrUpdate))
+	for key, value := range current {
+		result[key] = value
+	}
+	for _, key := range deleteKeys {
+		delete(result, key)
+	}
+	for key, value := range createOrUpdate {
+		result[key] = value
+	}
+
+	return result
+}
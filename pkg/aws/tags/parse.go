@@ -0,0 +1,37 @@
+package tags
+
+import (
+	"strings"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// Parse parses tags from a comma-separated list of key=value pairs, e.g.
+// "team=phoenix,cost-center=1234", and validates them against AWS tag limits.
+func Parse(s string) (map[string]string, error) {
+	tags := map[string]string{}
+	if strings.TrimSpace(s) == "" {
+		return tags, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, microerror.Maskf(errors.InvalidTagsError, "tag %q must be in the format key=value", pair)
+		}
+		tags[key] = strings.TrimSpace(value)
+	}
+
+	err := Validate(tags)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return tags, nil
+}
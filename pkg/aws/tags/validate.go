@@ -0,0 +1,62 @@
+package tags
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// AWS limits for user-defined tags, see
+// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/Using_Tags.html#tag-restrictions
+const (
+	MaxTagsPerResource = 50
+	MaxKeyLength       = 128
+	MaxValueLength     = 256
+	ReservedKeyPrefix  = "aws:"
+)
+
+// Validate checks that the tags are within AWS limits, so that invalid tags
+// are reported before calling EC2.
+func Validate(tags map[string]string) error {
+	err := validateCount(tags)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for key, value := range tags {
+		if key == "" {
+			return microerror.Maskf(errors.InvalidTagsError, "tag key must not be empty")
+		}
+		if utf8.RuneCountInString(key) > MaxKeyLength {
+			return microerror.Maskf(errors.InvalidTagsError, "tag key %q is longer than %d characters", key, MaxKeyLength)
+		}
+		if utf8.RuneCountInString(value) > MaxValueLength {
+			return microerror.Maskf(errors.InvalidTagsError, "value of tag %q is longer than %d characters", key, MaxValueLength)
+		}
+		if strings.HasPrefix(strings.ToLower(key), ReservedKeyPrefix) {
+			return microerror.Maskf(errors.InvalidTagsError, "tag key %q must not start with reserved prefix %q", key, ReservedKeyPrefix)
+		}
+	}
+
+	return nil
+}
+
+// validateCount checks that the resource does not have more tags than allowed.
+// Tags with the reserved prefix are created by AWS and do not count against
+// the limit.
+func validateCount(tags map[string]string) error {
+	count := 0
+	for key := range tags {
+		if !strings.HasPrefix(strings.ToLower(key), ReservedKeyPrefix) {
+			count++
+		}
+	}
+	if count > MaxTagsPerResource {
+		return microerror.Maskf(errors.InvalidTagsError, "%d tags exceed the limit of %d tags per resource", count, MaxTagsPerResource)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package vpc
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -9,6 +10,9 @@ import (
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
@@ -23,6 +27,9 @@ const (
 type Client interface {
 	Create(ctx context.Context, input CreateVpcInput) (CreateVpcOutput, error)
 	Get(ctx context.Context, input GetVpcInput) (GetVpcOutput, error)
+	ListOwned(ctx context.Context, input ListOwnedVpcsInput) ([]GetVpcOutput, error)
+	Update(ctx context.Context, input UpdateVpcInput) error
+	UpdateAttributes(ctx context.Context, input UpdateVpcAttributesInput) error
 	Delete(ctx context.Context, input DeleteVpcInput) error
 }
 
@@ -34,15 +41,29 @@ func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Clien
 		return nil, microerror.Maskf(errors.InvalidConfigError, "assumeRoleClient must not be empty")
 	}
 
+	tagsClient, err := tags.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	snapshotClient, err := snapshot.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	return &client{
 		ec2Client:        ec2Client,
 		assumeRoleClient: assumeRoleClient,
+		tagsClient:       tagsClient,
+		snapshotClient:   snapshotClient,
 	}, nil
 }
 
 type client struct {
 	ec2Client        *ec2.Client
 	assumeRoleClient assumerole.Client
+	tagsClient       tags.Client
+	snapshotClient   snapshot.Client
 }
 
 type attributes struct {
@@ -51,37 +72,17 @@ type attributes struct {
 }
 
 func (c *client) getAttributes(ctx context.Context, roleArn, region, vpcId string) (attributes, error) {
-	result := attributes{}
-
-	// get "enableDnsHostnames" attribute
-	ec2Input := ec2.DescribeVpcAttributeInput{
-		VpcId:     aws.String(vpcId),
-		Attribute: ec2Types.VpcAttributeNameEnableDnsHostnames,
-	}
-	ec2Output, err := c.ec2Client.DescribeVpcAttribute(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
-	if err != nil {
-		return result, microerror.Mask(err)
+	snapshotInput := snapshot.Input{
+		RoleARN: roleArn,
+		Region:  region,
+		VpcId:   vpcId,
 	}
-
-	if ec2Output.EnableDnsHostnames != nil {
-		result.EnableDnsHostnames = aws.ToBool(ec2Output.EnableDnsHostnames.Value)
-	}
-
-	// get "enableDnsSupport" attribute
-	ec2Input = ec2.DescribeVpcAttributeInput{
-		VpcId:     aws.String(vpcId),
-		Attribute: ec2Types.VpcAttributeNameEnableDnsSupport,
-	}
-	ec2Output, err = c.ec2Client.DescribeVpcAttribute(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
+	vpcAttributes, err := c.snapshotClient.VpcAttributes(ctx, snapshotInput)
 	if err != nil {
-		return result, microerror.Mask(err)
+		return attributes{}, microerror.Mask(err)
 	}
 
-	if ec2Output.EnableDnsSupport != nil {
-		result.EnableDnsSupport = aws.ToBool(ec2Output.EnableDnsSupport.Value)
-	}
-
-	return result, nil
+	return attributes(vpcAttributes), nil
 }
 
 func (c *client) updateAttribute(ctx context.Context, roleArn, region, vpcId string, attributeName ec2Types.VpcAttributeName, newValue bool) error {
@@ -100,7 +101,18 @@ func (c *client) updateAttribute(ctx context.Context, roleArn, region, vpcId str
 	default:
 		return microerror.Maskf(errors.UnknownVpcAttributeError, "Trying to update unknown VPC attribute %q", attributeName)
 	}
+	if dryrun.Enabled(ctx) {
+		operation := dryrun.Operation{
+			Action:     "ModifyVpcAttribute",
+			ResourceId: vpcId,
+			Details:    fmt.Sprintf("%s %t", attributeName, newValue),
+		}
+		// ModifyVpcAttribute does not support DryRun
+		err := dryrun.Record(ctx, operation, nil)
+		return microerror.Mask(err)
+	}
 	_, err := c.ec2Client.ModifyVpcAttribute(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(roleArn, region))
+	snapshot.Invalidate(ctx, snapshot.KindVpcAttributes)
 	if err != nil {
 		return microerror.Mask(err)
 	}
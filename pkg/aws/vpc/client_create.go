@@ -2,12 +2,15 @@ package vpc
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -50,6 +53,29 @@ func (c *client) Create(ctx context.Context, input CreateVpcInput) (CreateVpcOut
 		},
 	}
 
+	if dryrun.Enabled(ctx) {
+		output := CreateVpcOutput{
+			VpcId:     dryrun.PlannedId("vpc"),
+			CidrBlock: input.CidrBlock,
+			State:     VpcStatePending,
+			Tags:      input.Tags,
+		}
+		operation := dryrun.Operation{
+			Action:     "CreateVpc",
+			ResourceId: output.VpcId,
+			Details:    fmt.Sprintf("cidr-block %s", input.CidrBlock),
+		}
+		err := dryrun.Record(ctx, operation, func() error {
+			ec2Input.DryRun = aws.Bool(true)
+			_, err := c.ec2Client.CreateVpc(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			return err
+		})
+		if err != nil {
+			return CreateVpcOutput{}, microerror.Mask(err)
+		}
+		return output, nil
+	}
+
 	ec2Output, err := c.ec2Client.CreateVpc(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
 	if err != nil {
 		return CreateVpcOutput{}, microerror.Mask(err)
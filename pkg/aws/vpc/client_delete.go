@@ -8,13 +8,18 @@ import (
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
 type DeleteVpcInput struct {
-	RoleARN string
-	Region  string
-	VpcId   string
+	RoleARN     string
+	Region      string
+	VpcId       string
+	ClusterName string
+	ClusterUID  string
 }
 
 func (c *client) Delete(ctx context.Context, input DeleteVpcInput) error {
@@ -31,11 +36,42 @@ func (c *client) Delete(ctx context.Context, input DeleteVpcInput) error {
 	if input.VpcId == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
 	}
+	if input.ClusterName == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", input)
+	}
+
+	// We delete only VPCs that we created
+	getVpcInput := GetVpcInput{
+		RoleARN:     input.RoleARN,
+		Region:      input.Region,
+		VpcId:       input.VpcId,
+		ClusterName: input.ClusterName,
+	}
+	getVpcOutput, err := c.Get(ctx, getVpcInput)
+	if errors.IsVpcNotFound(err) {
+		logger.Info("VPC not found, nothing to delete", "vpc-id", input.VpcId)
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+	if !tags.IsOwnedBy(getVpcOutput.Tags, input.ClusterName, input.ClusterUID) {
+		logger.Info("Skipped deleting VPC that is not owned by the cluster", "vpc-id", input.VpcId)
+		return microerror.Maskf(errors.ResourceNotOwnedError, "VPC %s is not owned by cluster %s", input.VpcId, input.ClusterName)
+	}
 
 	ec2Input := ec2.DeleteVpcInput{
 		VpcId: aws.String(input.VpcId),
 	}
-	_, err := c.ec2Client.DeleteVpc(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	if dryrun.Enabled(ctx) {
+		err = dryrun.Record(ctx, dryrun.Operation{Action: "DeleteVpc", ResourceId: input.VpcId}, func() error {
+			ec2Input.DryRun = aws.Bool(true)
+			_, err := c.ec2Client.DeleteVpc(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			return err
+		})
+		return microerror.Mask(err)
+	}
+	_, err = c.ec2Client.DeleteVpc(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	snapshot.Invalidate(ctx, snapshot.AllKinds...)
 	if errors.IsVpcNotFound(err) {
 		logger.Info("VPC not found, nothing to delete", "vpc-id", input.VpcId)
 		return nil
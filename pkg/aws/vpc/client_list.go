@@ -0,0 +1,78 @@
+package vpc
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+type ListOwnedVpcsInput struct {
+	RoleARN     string
+	Region      string
+	ClusterName string
+	ClusterUID  string
+}
+
+// ListOwned returns all pending and available VPCs that are tagged as owned by
+// the specified cluster, see tags.IsOwnedBy.
+func (c *client) ListOwned(ctx context.Context, input ListOwnedVpcsInput) (output []GetVpcOutput, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started listing owned VPCs")
+	defer func() {
+		if err == nil {
+			logger.Info("Finished listing owned VPCs", "count", len(output))
+		} else {
+			logger.Error(err, "Failed to list owned VPCs")
+		}
+	}()
+
+	if input.RoleARN == "" {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.ClusterName == "" {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", input)
+	}
+
+	ec2Input := ec2.DescribeVpcsInput{
+		Filters: []ec2Types.Filter{
+			tags.OwnedByFilter(input.ClusterName),
+			{
+				Name:   aws.String("state"),
+				Values: []string{string(ec2Types.VpcStatePending), string(ec2Types.VpcStateAvailable)},
+			},
+		},
+	}
+	paginator := ec2.NewDescribeVpcsPaginator(c.ec2Client, &ec2Input)
+	for paginator.HasMorePages() {
+		ec2Output, err := paginator.NextPage(ctx, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, ec2Vpc := range ec2Output.Vpcs {
+			// the filter only matches the cluster name
+			if !tags.IsOwnedBy(TagsToMap(ec2Vpc.Tags), input.ClusterName, input.ClusterUID) {
+				logger.Info("Skipped VPC owned by another cluster with the same name", "vpc-id", aws.ToString(ec2Vpc.VpcId))
+				continue
+			}
+			output = append(output, GetVpcOutput{
+				VpcId:     aws.ToString(ec2Vpc.VpcId),
+				CidrBlock: aws.ToString(ec2Vpc.CidrBlock),
+				State:     VpcState(ec2Vpc.State),
+				Tags:      TagsToMap(ec2Vpc.Tags),
+			})
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,101 @@
+package vpc
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+type UpdateVpcInput struct {
+	RoleARN string
+	Region  string
+	VpcId   string
+	Tags    map[string]string
+
+	// CurrentTags are the tags that the VPC currently has.
+	CurrentTags map[string]string
+
+	// ManagedTagKeys are the keys of the tags that were previously applied
+	// by the operator. They are deleted when they are not in Tags anymore.
+	ManagedTagKeys []string
+}
+
+func (c *client) Update(ctx context.Context, input UpdateVpcInput) (err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started updating VPC", "vpc-id", input.VpcId)
+	defer func() {
+		if err == nil {
+			logger.Info("Finished updating VPC", "vpc-id", input.VpcId)
+		} else {
+			logger.Error(err, "Failed to update VPC", "vpc-id", input.VpcId)
+		}
+	}()
+
+	if input.RoleARN == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.VpcId == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
+	}
+
+	// update VPC tags
+	reconcileTagsInput := tags.ReconcileTagsInput{
+		RoleARN:     input.RoleARN,
+		Region:      input.Region,
+		ResourceId:  input.VpcId,
+		CurrentTags: input.CurrentTags,
+		WantedTags:  input.Tags,
+		ManagedKeys: input.ManagedTagKeys,
+	}
+	err = c.tagsClient.Reconcile(ctx, reconcileTagsInput)
+	snapshot.Invalidate(ctx, snapshot.KindVpc)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+type UpdateVpcAttributesInput struct {
+	RoleARN            string
+	Region             string
+	VpcId              string
+	EnableDnsHostnames bool
+	EnableDnsSupport   bool
+}
+
+// UpdateAttributes ensures that the VPC attributes have the specified values.
+func (c *client) UpdateAttributes(ctx context.Context, input UpdateVpcAttributesInput) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Started updating VPC attributes", "vpc-id", input.VpcId)
+	defer logger.Info("Finished updating VPC attributes", "vpc-id", input.VpcId)
+
+	if input.RoleARN == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.RoleARN must not be empty", input)
+	}
+	if input.Region == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
+	}
+	if input.VpcId == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
+	}
+
+	wantedAttributes := attributes{
+		EnableDnsHostnames: input.EnableDnsHostnames,
+		EnableDnsSupport:   input.EnableDnsSupport,
+	}
+	err := c.ensureAttributes(ctx, input.RoleARN, input.Region, input.VpcId, wantedAttributes)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
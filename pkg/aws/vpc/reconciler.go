@@ -14,7 +14,9 @@ import (
 )
 
 const (
-	defaultVPCCidr = "10.0.0.0/16"
+	// DefaultCidr is the CIDR block of VPCs created for AWSClusters that do not
+	// specify one, e.g. when the defaulting webhook is disabled.
+	DefaultCidr = "10.0.0.0/16"
 )
 
 type Reconciler interface {
@@ -45,6 +47,7 @@ func (s *reconciler) getVpcTags(spec Spec) map[string]string {
 
 	params := tags.BuildParams{
 		ClusterName: spec.ClusterName,
+		ClusterUID:  spec.ClusterUID,
 		ResourceID:  id,
 		Name:        name,
 		Role:        capa.CommonRoleTagValue,
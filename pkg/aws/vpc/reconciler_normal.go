@@ -2,20 +2,34 @@ package vpc
 
 import (
 	"context"
+	"strings"
 
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
 )
 
 type Spec struct {
 	ClusterName    string
+	ClusterUID     string
 	RoleARN        string
 	Region         string
 	VpcId          string
 	CidrBlock      string
 	AdditionalTags map[string]string
+
+	// LastAppliedTagKeys are the keys of AdditionalTags that were applied to
+	// the VPC before. Those that are not in AdditionalTags anymore are
+	// removed from the VPC.
+	LastAppliedTagKeys []string
+
+	// Unmanaged is set for VPCs that are brought in by the user. They are
+	// never created or changed, and they don't have to be owned by the
+	// cluster.
+	Unmanaged bool
 }
 
 type Status struct {
@@ -25,11 +39,20 @@ type Status struct {
 	Tags      map[string]string
 }
 
-func (s *reconciler) Reconcile(ctx context.Context, spec Spec) (Status, error) {
+func (s *reconciler) Reconcile(ctx context.Context, spec Spec) (status Status, err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Started reconciling VPC")
 	defer logger.Info("Finished reconciling VPC")
 
+	ctx, span := tracing.Start(ctx, "vpc.Reconcile",
+		tracing.ClusterNameKey.String(spec.ClusterName),
+		tracing.RegionKey.String(spec.Region),
+		tracing.VpcIdKey.String(spec.VpcId))
+	defer func() {
+		span.SetAttributes(tracing.VpcIdKey.String(status.VpcId))
+		tracing.End(span, err)
+	}()
+
 	if spec.ClusterName == "" {
 		return Status{}, microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", spec)
 	}
@@ -54,13 +77,84 @@ func (s *reconciler) Reconcile(ctx context.Context, spec Spec) (Status, error) {
 		if err != nil {
 			return Status{}, microerror.Mask(err)
 		}
+		if !spec.Unmanaged && !tags.IsOwnedBy(getVpcOutput.Tags, spec.ClusterName, spec.ClusterUID) {
+			return Status{}, microerror.Maskf(errors.ResourceNotOwnedError, "VPC %s is not owned by cluster %s", spec.VpcId, spec.ClusterName)
+		}
+
+		if !spec.Unmanaged {
+			wantedTags := s.getVpcTags(spec)
+			changedOrNewTags, removedTagKeys := tags.Changes(getVpcOutput.Tags, wantedTags, spec.LastAppliedTagKeys)
+			if len(changedOrNewTags) > 0 || len(removedTagKeys) > 0 {
+				updateVpcInput := UpdateVpcInput{
+					RoleARN:        spec.RoleARN,
+					Region:         spec.Region,
+					VpcId:          spec.VpcId,
+					Tags:           wantedTags,
+					CurrentTags:    getVpcOutput.Tags,
+					ManagedTagKeys: spec.LastAppliedTagKeys,
+				}
+				err = s.client.Update(ctx, updateVpcInput)
+				if err != nil {
+					return Status{}, microerror.Mask(err)
+				}
+				getVpcOutput.Tags = tags.Apply(getVpcOutput.Tags, changedOrNewTags, removedTagKeys)
+			}
+		}
 
-		status := Status(getVpcOutput)
+		status = Status(getVpcOutput)
 		return status, nil
 	}
 
+	if spec.Unmanaged {
+		return Status{}, microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty for unmanaged VPC", spec)
+	}
+
 	if spec.CidrBlock == "" {
-		spec.CidrBlock = defaultVPCCidr
+		spec.CidrBlock = DefaultCidr
+	}
+
+	//
+	// Adopt a VPC that we already created for this cluster, but whose ID was
+	// never written to the AWSCluster, e.g. because the operator crashed or
+	// failed to patch the AWSCluster right after creating the VPC. CreateVpc
+	// does not support client tokens, so the ownership tag, which is set
+	// atomically on creation, is the only way to find such VPCs.
+	//
+	listOwnedVpcsInput := ListOwnedVpcsInput{
+		RoleARN:     spec.RoleARN,
+		Region:      spec.Region,
+		ClusterName: spec.ClusterName,
+		ClusterUID:  spec.ClusterUID,
+	}
+	ownedVpcs, err := s.client.ListOwned(ctx, listOwnedVpcsInput)
+	if err != nil {
+		return Status{}, microerror.Mask(err)
+	}
+	if len(ownedVpcs) > 1 {
+		var vpcIds []string
+		for _, ownedVpc := range ownedVpcs {
+			vpcIds = append(vpcIds, ownedVpc.VpcId)
+		}
+		return Status{}, microerror.Maskf(errors.VpcConflictError, "found %d VPCs owned by cluster %s: %s. Only one VPC per cluster is supported, delete the duplicate VPCs", len(ownedVpcs), spec.ClusterName, strings.Join(vpcIds, ", "))
+	} else if len(ownedVpcs) == 1 {
+		logger.Info("Adopting existing VPC owned by the cluster", "vpc-id", ownedVpcs[0].VpcId)
+
+		// the VPC attributes are set after the VPC is created, so they could
+		// be missing
+		updateVpcAttributesInput := UpdateVpcAttributesInput{
+			RoleARN:            spec.RoleARN,
+			Region:             spec.Region,
+			VpcId:              ownedVpcs[0].VpcId,
+			EnableDnsHostnames: true,
+			EnableDnsSupport:   true,
+		}
+		err = s.client.UpdateAttributes(ctx, updateVpcAttributesInput)
+		if err != nil {
+			return Status{}, microerror.Mask(err)
+		}
+
+		status = Status(ownedVpcs[0])
+		return status, nil
 	}
 
 	//
@@ -79,6 +173,6 @@ func (s *reconciler) Reconcile(ctx context.Context, spec Spec) (Status, error) {
 		return Status{}, microerror.Mask(err)
 	}
 
-	status := Status(createVpcOutput)
+	status = Status(createVpcOutput)
 	return status, nil
 }
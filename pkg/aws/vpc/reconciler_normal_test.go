@@ -0,0 +1,173 @@
+package vpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpc"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+func Test_Reconcile_AdoptsVpcOwnedByCluster(t *testing.T) {
+	const clusterName = "test"
+
+	ownedVpc := func(id string) ec2Types.Vpc {
+		return ec2Types.Vpc{
+			VpcId:     aws.String(id),
+			CidrBlock: aws.String("10.0.0.0/16"),
+			State:     ec2Types.VpcStateAvailable,
+			Tags: []ec2Types.Tag{
+				{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		existingVpcs  []ec2Types.Vpc
+		expectedVpcId string
+		expectCreate  bool
+		errorMatcher  func(error) bool
+	}{
+		{
+			name:          "case 0: no owned VPC, new VPC is created",
+			expectedVpcId: "vpc-new",
+			expectCreate:  true,
+		},
+		{
+			name:          "case 1: owned VPC is adopted",
+			existingVpcs:  []ec2Types.Vpc{ownedVpc("vpc-1")},
+			expectedVpcId: "vpc-1",
+		},
+		{
+			name:         "case 2: multiple owned VPCs are a conflict",
+			existingVpcs: []ec2Types.Vpc{ownedVpc("vpc-1"), ownedVpc("vpc-2")},
+			errorMatcher: errors.IsVpcConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeEC2 := awstest.NewEC2().
+				On("DescribeVpcs", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeVpcsOutput{Vpcs: tc.existingVpcs}, nil
+				}).
+				On("CreateVpc", func(interface{}) (interface{}, error) {
+					return &ec2.CreateVpcOutput{Vpc: &ec2Types.Vpc{
+						VpcId:     aws.String("vpc-new"),
+						CidrBlock: aws.String("10.0.0.0/16"),
+						State:     ec2Types.VpcStatePending,
+					}}, nil
+				}).
+				On("DescribeVpcAttribute", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeVpcAttributeOutput{
+						EnableDnsHostnames: &ec2Types.AttributeBooleanValue{Value: aws.Bool(true)},
+						EnableDnsSupport:   &ec2Types.AttributeBooleanValue{Value: aws.Bool(true)},
+					}, nil
+				})
+
+			client, err := vpc.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			reconciler, err := vpc.NewReconciler(client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			status, err := reconciler.Reconcile(context.Background(), vpc.Spec{
+				ClusterName: clusterName,
+				RoleARN:     awstest.RoleARN,
+				Region:      awstest.Region,
+			})
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("error not matching expected matcher, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if status.VpcId != tc.expectedVpcId {
+				t.Errorf("expected VPC %q, got %q", tc.expectedVpcId, status.VpcId)
+			}
+			if created := fakeEC2.Calls("CreateVpc") > 0; created != tc.expectCreate {
+				t.Errorf("expected VPC creation %t, got %t", tc.expectCreate, created)
+			}
+		})
+	}
+}
+
+func Test_Reconcile_DryRun(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		dryRunErrorCode          string
+		expectedPermissionDenied bool
+	}{
+		{
+			name:            "case 0: permitted VPC creation is planned",
+			dryRunErrorCode: "DryRunOperation",
+		},
+		{
+			name:                     "case 1: denied VPC creation is planned as denied",
+			dryRunErrorCode:          "UnauthorizedOperation",
+			expectedPermissionDenied: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeEC2 := awstest.NewEC2().
+				On("DescribeVpcs", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeVpcsOutput{}, nil
+				}).
+				On("CreateVpc", func(input interface{}) (interface{}, error) {
+					if !aws.ToBool(input.(*ec2.CreateVpcInput).DryRun) {
+						t.Fatalf("expected CreateVpc to be called with DryRun")
+					}
+					return nil, &smithy.GenericAPIError{Code: tc.dryRunErrorCode}
+				})
+
+			client, err := vpc.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			reconciler, err := vpc.NewReconciler(client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			plan := dryrun.NewPlan()
+			status, err := reconciler.Reconcile(dryrun.NewContext(context.Background(), plan), vpc.Spec{
+				ClusterName: "test",
+				RoleARN:     awstest.RoleARN,
+				Region:      awstest.Region,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !dryrun.IsPlannedId(status.VpcId) || status.State != vpc.VpcStatePending {
+				t.Errorf("expected pending planned VPC, got %q in state %q", status.VpcId, status.State)
+			}
+			operations := plan.Operations()
+			if len(operations) != 1 || operations[0].Action != "CreateVpc" {
+				t.Fatalf("expected planned CreateVpc, got %v", operations)
+			}
+			if operations[0].PermissionDenied != tc.expectedPermissionDenied {
+				t.Errorf("expected permission denied %t, got %t", tc.expectedPermissionDenied, operations[0].PermissionDenied)
+			}
+			if calls := fakeEC2.Calls("ModifyVpcAttribute"); calls != 0 {
+				t.Errorf("expected no VPC attribute changes, got %d", calls)
+			}
+		})
+	}
+}
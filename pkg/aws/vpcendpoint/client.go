@@ -9,6 +9,7 @@ import (
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -39,10 +40,16 @@ func NewClient(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (Clien
 		return nil, microerror.Mask(err)
 	}
 
+	snapshotClient, err := snapshot.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	return &client{
 		ec2Client:        ec2Client,
 		assumeRoleClient: assumeRoleClient,
 		tagsClient:       tagsClient,
+		snapshotClient:   snapshotClient,
 	}, nil
 }
 
@@ -50,8 +57,11 @@ type client struct {
 	ec2Client        *ec2.Client
 	tagsClient       tags.Client
 	assumeRoleClient assumerole.Client
+	snapshotClient   snapshot.Client
 }
 
-func s3ServiceName(region string) string {
-	return fmt.Sprintf("com.amazonaws.%s.s3", region)
+// ServiceName returns the full name of the AWS service in the region, e.g.
+// com.amazonaws.eu-west-1.s3 for s3.
+func ServiceName(region, service string) string {
+	return fmt.Sprintf("com.amazonaws.%s.%s", region, service)
 }
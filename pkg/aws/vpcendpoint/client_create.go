@@ -2,6 +2,7 @@ package vpcendpoint
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -9,6 +10,9 @@ import (
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	awsresource "github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -21,6 +25,10 @@ type CreateVpcEndpointInput struct {
 	Type        ec2Types.VpcEndpointType
 	VpcId       string
 
+	// ClientToken makes the creation idempotent, see aws.ClientToken. It is
+	// combined with a hash of the create input, see aws.InputClientToken.
+	ClientToken string
+
 	VPCEndpointGatewayConfig *VPCEndpointGatewayConfig
 }
 
@@ -72,16 +80,69 @@ func (c *client) Create(ctx context.Context, input CreateVpcEndpointInput) (outp
 		},
 		VpcEndpointType: input.Type,
 	}
-
-	ec2Output, err := c.ec2Client.CreateVpcEndpoint(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	clientToken, err := awsresource.InputClientToken(input.ClientToken, ec2Input)
 	if err != nil {
 		return CreateVpcEndpointOutput{}, microerror.Mask(err)
 	}
+	if clientToken != "" {
+		ec2Input.ClientToken = aws.String(clientToken)
+	}
+
+	if dryrun.Enabled(ctx) {
+		output = CreateVpcEndpointOutput{
+			VpcEndpointId:    dryrun.PlannedId("vpce"),
+			VpcEndpointState: string(ec2Types.StatePending),
+		}
+		operation := dryrun.Operation{
+			Action:     "CreateVpcEndpoint",
+			ResourceId: output.VpcEndpointId,
+			Details:    fmt.Sprintf("service %s, vpc %s", input.ServiceName, input.VpcId),
+		}
+		err = dryrun.Record(ctx, operation, func() error {
+			ec2Input.DryRun = aws.Bool(true)
+			_, err := c.ec2Client.CreateVpcEndpoint(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			return err
+		})
+		if err != nil {
+			return CreateVpcEndpointOutput{}, microerror.Mask(err)
+		}
+		return output, nil
+	}
 
-	output = CreateVpcEndpointOutput{
-		VpcEndpointId:    *ec2Output.VpcEndpoint.VpcEndpointId,
-		VpcEndpointState: string(ec2Output.VpcEndpoint.State),
+	for attempt := 1; ; attempt++ {
+		ec2Output, err := c.ec2Client.CreateVpcEndpoint(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		// gateway VPC endpoints add routes to their route tables
+		snapshot.Invalidate(ctx, snapshot.KindVpcEndpoints, snapshot.KindRouteTables)
+		retry := ec2Input.ClientToken != nil && attempt < awsresource.MaxClientTokenAttempts
+		if retry && errors.IsIdempotentParameterMismatch(err) {
+			logger.Info("Client token was used with other parameters, retrying with next client token")
+			ec2Input.ClientToken = aws.String(awsresource.NextClientToken(*ec2Input.ClientToken, "IdempotentParameterMismatch"))
+			continue
+		} else if err != nil {
+			return CreateVpcEndpointOutput{}, microerror.Mask(err)
+		}
+
+		output = CreateVpcEndpointOutput{
+			VpcEndpointId:    *ec2Output.VpcEndpoint.VpcEndpointId,
+			VpcEndpointState: string(ec2Output.VpcEndpoint.State),
+		}
+
+		// the client token returned the VPC endpoint that was created with
+		// it before, but which has been deleted since
+		if retry && isDeletedState(ec2Output.VpcEndpoint.State) {
+			logger.Info("Client token returned deleted VPC endpoint, retrying with next client token", "vpc-endpoint-id", output.VpcEndpointId, "vpc-endpoint-state", output.VpcEndpointState)
+			ec2Input.ClientToken = aws.String(awsresource.NextClientToken(*ec2Input.ClientToken, output.VpcEndpointId))
+			continue
+		}
+
+		return output, nil
 	}
+}
 
-	return output, err
+func isDeletedState(state ec2Types.State) bool {
+	switch state {
+	case ec2Types.StateDeleting, ec2Types.StateDeleted, ec2Types.StateRejected, ec2Types.StateFailed, ec2Types.StateExpired:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,120 @@
+package vpcendpoint_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+func Test_Create_ClientToken(t *testing.T) {
+	testCases := []struct {
+		name string
+		// responses are returned by the CreateVpcEndpoint calls in order
+		responses             []func() (*ec2.CreateVpcEndpointOutput, error)
+		expectedVpcEndpointId string
+		expectedCalls         int
+		expectedErrorClass    errors.Class
+	}{
+		{
+			name: "case 0: created VPC endpoint is returned",
+			responses: []func() (*ec2.CreateVpcEndpointOutput, error){
+				created("vpce-1", ec2Types.StatePending),
+			},
+			expectedVpcEndpointId: "vpce-1",
+			expectedCalls:         1,
+		},
+		{
+			name: "case 1: deleted VPC endpoint returned for the client token is recreated",
+			responses: []func() (*ec2.CreateVpcEndpointOutput, error){
+				created("vpce-old", ec2Types.StateDeleted),
+				created("vpce-new", ec2Types.StatePending),
+			},
+			expectedVpcEndpointId: "vpce-new",
+			expectedCalls:         2,
+		},
+		{
+			name: "case 2: idempotent parameter mismatch is retried with the next client token",
+			responses: []func() (*ec2.CreateVpcEndpointOutput, error){
+				mismatch,
+				created("vpce-1", ec2Types.StatePending),
+			},
+			expectedVpcEndpointId: "vpce-1",
+			expectedCalls:         2,
+		},
+		{
+			name: "case 3: idempotent parameter mismatch is returned after the last attempt",
+			responses: []func() (*ec2.CreateVpcEndpointOutput, error){
+				mismatch, mismatch, mismatch, mismatch, mismatch,
+			},
+			expectedCalls:      5,
+			expectedErrorClass: errors.ClassConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var tokens []string
+			fakeEC2 := awstest.NewEC2().
+				On("CreateVpcEndpoint", func(input interface{}) (interface{}, error) {
+					tokens = append(tokens, aws.ToString(input.(*ec2.CreateVpcEndpointInput).ClientToken))
+					return tc.responses[len(tokens)-1]()
+				})
+
+			client, err := vpcendpoint.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			output, err := client.Create(context.Background(), vpcendpoint.CreateVpcEndpointInput{
+				RoleARN:                  awstest.RoleARN,
+				Region:                   awstest.Region,
+				ServiceName:              "com.amazonaws.eu-west-1.s3",
+				Type:                     ec2Types.VpcEndpointTypeGateway,
+				VpcId:                    "vpc-1",
+				ClientToken:              "token",
+				VPCEndpointGatewayConfig: &vpcendpoint.VPCEndpointGatewayConfig{RouteTableIDs: []string{"rtb-1"}},
+			})
+			if tc.expectedErrorClass != "" {
+				if errors.Classify(err) != tc.expectedErrorClass {
+					t.Fatalf("expected error of class %s, got %v", tc.expectedErrorClass, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.VpcEndpointId != tc.expectedVpcEndpointId {
+				t.Errorf("expected VPC endpoint %q, got %q", tc.expectedVpcEndpointId, output.VpcEndpointId)
+			}
+
+			if len(tokens) != tc.expectedCalls {
+				t.Fatalf("expected %d CreateVpcEndpoint calls, got %d", tc.expectedCalls, len(tokens))
+			}
+			seen := map[string]bool{}
+			for _, token := range tokens {
+				if token == "" || token == "token" || seen[token] {
+					t.Errorf("expected a new client token with the input hash in every call, got %v", tokens)
+				}
+				seen[token] = true
+			}
+		})
+	}
+}
+
+func created(id string, state ec2Types.State) func() (*ec2.CreateVpcEndpointOutput, error) {
+	return func() (*ec2.CreateVpcEndpointOutput, error) {
+		return &ec2.CreateVpcEndpointOutput{
+			VpcEndpoint: &ec2Types.VpcEndpoint{VpcEndpointId: aws.String(id), State: state},
+		}, nil
+	}
+}
+
+func mismatch() (*ec2.CreateVpcEndpointOutput, error) {
+	return nil, &smithy.GenericAPIError{Code: "IdempotentParameterMismatch", Message: "test"}
+}
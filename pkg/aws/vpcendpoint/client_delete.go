@@ -2,12 +2,19 @@ package vpcendpoint
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
@@ -17,6 +24,12 @@ type DeleteVpcEndpointInput struct {
 	ServiceName string
 	Type        ec2Types.VpcEndpointType
 	VpcId       string
+	ClusterName string
+	ClusterUID  string
+
+	// VpcEndpointId deletes the VPC endpoint with this ID instead of the one
+	// of ServiceName and Type, which are not required then.
+	VpcEndpointId string
 }
 
 func (c *client) Delete(ctx context.Context, input DeleteVpcEndpointInput) (err error) {
@@ -36,41 +49,78 @@ func (c *client) Delete(ctx context.Context, input DeleteVpcEndpointInput) (err
 	if input.Region == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
 	}
-	if input.ServiceName == "" {
+	if input.ServiceName == "" && input.VpcEndpointId == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.ServiceName must not be empty", input)
 	}
-	if input.Type == "" {
+	if input.Type == "" && input.VpcEndpointId == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.Type must not be empty", input)
 	}
 	if input.VpcId == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
 	}
+	if input.ClusterName == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.ClusterName must not be empty", input)
+	}
+
+	getInput := GetVpcEndpointInput{
+		RoleARN:     input.RoleARN,
+		Region:      input.Region,
+		ServiceName: input.ServiceName,
+		Type:        input.Type,
+		VpcId:       input.VpcId,
 
-	getInput := GetVpcEndpointInput(input)
+		VpcEndpointId: input.VpcEndpointId,
+	}
 	vpcEndpoint, err := c.Get(ctx, getInput)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	if vpcEndpoint.VpcEndpointState == StateDeleted {
+	if strings.EqualFold(vpcEndpoint.VpcEndpointState, StateDeleted) {
 		message := "VPC endpoint is already deleted"
 		logger.Info(message, "vpc-endpoint-id", vpcEndpoint.VpcEndpointId)
 		return microerror.Maskf(errors.ResourceAlreadyDeletedError, "%s", message)
 	}
-	if vpcEndpoint.VpcEndpointState == StateDeleting {
+	if strings.EqualFold(vpcEndpoint.VpcEndpointState, StateDeleting) {
 		message := "VPC endpoint deletion is already in progress"
 		logger.Info(message, "vpc-endpoint-id", vpcEndpoint.VpcEndpointId)
 		return microerror.Maskf(errors.ResourceDeletionInProgressError, "%s", message)
 	}
 
+	// We delete only VPC endpoints that we created
+	if !tags.IsOwnedBy(vpcEndpoint.Tags, input.ClusterName, input.ClusterUID) {
+		logger.Info("Skipped deleting VPC endpoint that is not owned by the cluster", "vpc-endpoint-id", vpcEndpoint.VpcEndpointId)
+		return microerror.Maskf(errors.ResourceNotOwnedError, "VPC endpoint %s is not owned by cluster %s", vpcEndpoint.VpcEndpointId, input.ClusterName)
+	}
+
 	logger.Info("Found VPC endpoint to delete", "vpc-endpoint-id", vpcEndpoint.VpcEndpointId)
 	ec2Input := ec2.DeleteVpcEndpointsInput{
 		VpcEndpointIds: []string{vpcEndpoint.VpcEndpointId},
 	}
-	_, err = c.ec2Client.DeleteVpcEndpoints(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	if dryrun.Enabled(ctx) {
+		err = dryrun.Record(ctx, dryrun.Operation{Action: "DeleteVpcEndpoints", ResourceId: vpcEndpoint.VpcEndpointId}, func() error {
+			ec2Input.DryRun = aws.Bool(true)
+			_, err := c.ec2Client.DeleteVpcEndpoints(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			return err
+		})
+		return microerror.Mask(err)
+	}
+	ec2Output, err := c.ec2Client.DeleteVpcEndpoints(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	snapshot.Invalidate(ctx, snapshot.KindVpcEndpoints, snapshot.KindRouteTables)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	return err
+	// failures for individual VPC endpoints are not returned as an error
+	for _, item := range ec2Output.Unsuccessful {
+		if item.Error != nil {
+			// keep the AWS error code, so that the error can be classified
+			return microerror.Mask(&smithy.GenericAPIError{
+				Code:    aws.ToString(item.Error.Code),
+				Message: fmt.Sprintf("failed to delete VPC endpoint %s: %s", aws.ToString(item.ResourceId), aws.ToString(item.Error.Message)),
+			})
+		}
+	}
+
+	return nil
 }
@@ -4,11 +4,12 @@ import (
 	"context"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
 
@@ -18,6 +19,10 @@ type GetVpcEndpointInput struct {
 	ServiceName string
 	Type        ec2Types.VpcEndpointType
 	VpcId       string
+
+	// VpcEndpointId finds the VPC endpoint by its ID instead of ServiceName
+	// and Type, which are not required then.
+	VpcEndpointId string
 }
 
 type GetVpcEndpointOutput struct {
@@ -25,6 +30,7 @@ type GetVpcEndpointOutput struct {
 	VpcEndpointState         string
 	Type                     ec2Types.VpcEndpointType
 	VPCEndpointGatewayConfig *VPCEndpointGatewayConfig
+	Tags                     map[string]string
 }
 
 func (c *client) Get(ctx context.Context, input GetVpcEndpointInput) (output GetVpcEndpointOutput, err error) {
@@ -44,49 +50,49 @@ func (c *client) Get(ctx context.Context, input GetVpcEndpointInput) (output Get
 	if input.Region == "" {
 		return GetVpcEndpointOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", input)
 	}
-	if input.ServiceName == "" {
+	if input.ServiceName == "" && input.VpcEndpointId == "" {
 		return GetVpcEndpointOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.ServiceName must not be empty", input)
 	}
-	if input.Type == "" {
+	if input.Type == "" && input.VpcEndpointId == "" {
 		return GetVpcEndpointOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.Type must not be empty", input)
 	}
 	if input.VpcId == "" {
 		return GetVpcEndpointOutput{}, microerror.Maskf(errors.InvalidConfigError, "%T.VpcId must not be empty", input)
 	}
 
-	ec2Input := ec2.DescribeVpcEndpointsInput{
-		Filters: []ec2Types.Filter{
-			{
-				Name:   aws.String("vpc-id"),
-				Values: []string{input.VpcId},
-			},
-			{
-				Name:   aws.String("service-name"),
-				Values: []string{input.ServiceName},
-			},
-			{
-				Name:   aws.String("vpc-endpoint-type"),
-				Values: []string{string(input.Type)},
-			},
-		},
+	snapshotInput := snapshot.Input{
+		RoleARN: input.RoleARN,
+		Region:  input.Region,
+		VpcId:   input.VpcId,
 	}
-	ec2Output, err := c.ec2Client.DescribeVpcEndpoints(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+	ec2VpcEndpoints, err := c.snapshotClient.VpcEndpoints(ctx, snapshotInput)
 	if err != nil {
 		return GetVpcEndpointOutput{}, microerror.Mask(err)
 	}
 
-	if len(ec2Output.VpcEndpoints) == 0 {
-		return GetVpcEndpointOutput{}, microerror.Maskf(errors.VpcEndpointNotFoundError, "VPC %s endpoint %s for VPC %s not found", input.Type, input.ServiceName, input.VpcId)
-	}
+	for _, ec2VpcEndpoint := range ec2VpcEndpoints {
+		if input.VpcEndpointId != "" {
+			if aws.ToString(ec2VpcEndpoint.VpcEndpointId) != input.VpcEndpointId {
+				continue
+			}
+		} else if aws.ToString(ec2VpcEndpoint.ServiceName) != input.ServiceName || ec2VpcEndpoint.VpcEndpointType != input.Type {
+			continue
+		}
 
-	output = GetVpcEndpointOutput{
-		VpcEndpointId:    *ec2Output.VpcEndpoints[0].VpcEndpointId,
-		VpcEndpointState: string(ec2Output.VpcEndpoints[0].State),
-		VPCEndpointGatewayConfig: &VPCEndpointGatewayConfig{
-			RouteTableIDs: ec2Output.VpcEndpoints[0].RouteTableIds,
-		},
-		Type: ec2Output.VpcEndpoints[0].VpcEndpointType,
+		output = GetVpcEndpointOutput{
+			VpcEndpointId:    *ec2VpcEndpoint.VpcEndpointId,
+			VpcEndpointState: string(ec2VpcEndpoint.State),
+			VPCEndpointGatewayConfig: &VPCEndpointGatewayConfig{
+				RouteTableIDs: ec2VpcEndpoint.RouteTableIds,
+			},
+			Type: ec2VpcEndpoint.VpcEndpointType,
+			Tags: tags.ToMap(ec2VpcEndpoint.Tags),
+		}
+		return output, nil
 	}
 
-	return output, err
+	if input.VpcEndpointId != "" {
+		return GetVpcEndpointOutput{}, microerror.Maskf(errors.VpcEndpointNotFoundError, "VPC endpoint %s for VPC %s not found", input.VpcEndpointId, input.VpcId)
+	}
+	return GetVpcEndpointOutput{}, microerror.Maskf(errors.VpcEndpointNotFoundError, "VPC %s endpoint %s for VPC %s not found", input.Type, input.ServiceName, input.VpcId)
 }
@@ -2,6 +2,7 @@ package vpcendpoint
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -9,6 +10,8 @@ import (
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/snapshot"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
 )
@@ -21,6 +24,13 @@ type UpdateVpcEndpointInput struct {
 	VpcEndpointId            string
 	VPCEndpointGatewayConfig *VPCEndpointGatewayUpdateConfig
 	Tags                     map[string]string
+
+	// CurrentTags are the tags that the VPC endpoint currently has.
+	CurrentTags map[string]string
+
+	// ManagedTagKeys are the keys of the tags that were previously applied
+	// by the operator. They are deleted when they are not in Tags anymore.
+	ManagedTagKeys []string
 }
 
 type VPCEndpointGatewayUpdateConfig struct {
@@ -70,7 +80,23 @@ func (c *client) Update(ctx context.Context, input UpdateVpcEndpointInput) (err
 			RemoveRouteTableIds: input.VPCEndpointGatewayConfig.RemoveRouteTableIDs,
 			ResetPolicy:         aws.Bool(true),
 		}
-		_, err = c.ec2Client.ModifyVpcEndpoint(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+		if dryrun.Enabled(ctx) {
+			operation := dryrun.Operation{
+				Action:     "ModifyVpcEndpoint",
+				ResourceId: input.VpcEndpointId,
+				Details: fmt.Sprintf("add route tables %v, remove route tables %v",
+					input.VPCEndpointGatewayConfig.AddRouteTableIDs,
+					input.VPCEndpointGatewayConfig.RemoveRouteTableIDs),
+			}
+			err = dryrun.Record(ctx, operation, func() error {
+				ec2Input.DryRun = aws.Bool(true)
+				_, err := c.ec2Client.ModifyVpcEndpoint(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+				return err
+			})
+		} else {
+			_, err = c.ec2Client.ModifyVpcEndpoint(ctx, &ec2Input, c.assumeRoleClient.AssumeRoleFunc(input.RoleARN, input.Region))
+			snapshot.Invalidate(ctx, snapshot.KindVpcEndpoints, snapshot.KindRouteTables)
+		}
 		if err != nil {
 			return microerror.Mask(err)
 		}
@@ -81,13 +107,16 @@ func (c *client) Update(ctx context.Context, input UpdateVpcEndpointInput) (err
 	logger.Info("Updating VPC endpoint tags", "tags", input.Tags)
 
 	// Update VPC endpoint tags
-	createTagsInput := tags.CreateTagsInput{
-		RoleARN:    input.RoleARN,
-		Region:     input.Region,
-		ResourceId: input.VpcEndpointId,
-		Tags:       input.Tags,
+	reconcileTagsInput := tags.ReconcileTagsInput{
+		RoleARN:     input.RoleARN,
+		Region:      input.Region,
+		ResourceId:  input.VpcEndpointId,
+		CurrentTags: input.CurrentTags,
+		WantedTags:  input.Tags,
+		ManagedKeys: input.ManagedTagKeys,
 	}
-	err = c.tagsClient.Create(ctx, createTagsInput)
+	err = c.tagsClient.Reconcile(ctx, reconcileTagsInput)
+	snapshot.Invalidate(ctx, snapshot.KindVpcEndpoints)
 	if err != nil {
 		return microerror.Mask(err)
 	}
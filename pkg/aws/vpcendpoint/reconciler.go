@@ -10,8 +10,32 @@ import (
 )
 
 type Reconciler interface {
-	Reconcile(ctx context.Context, request aws.ReconcileRequest[Spec]) (aws.ReconcileResult[Status], error)
-	ReconcileDelete(ctx context.Context, request aws.ReconcileRequest[aws.DeletedCloudResourceSpec]) error
+	// Reconcile reconciles a gateway VPC endpoint for each of the services in
+	// the spec, and returns their statuses in the same order.
+	Reconcile(ctx context.Context, request aws.ReconcileRequest[Spec]) (aws.ReconcileResult[[]Status], error)
+
+	// ReconcileDelete deletes the VPC endpoints of the services in the
+	// specified VPC. It returns ResourceDeletionInProgressError until all VPC
+	// endpoints are deleted.
+	ReconcileDelete(ctx context.Context, request aws.ReconcileRequest[DeleteSpec]) error
+}
+
+// DefaultServices are the services for which gateway VPC endpoints are
+// reconciled when no services are specified.
+var DefaultServices = []string{S3}
+
+type DeleteSpec struct {
+	VpcId string
+
+	// Services are short names of the services whose VPC endpoints are
+	// deleted, e.g. s3. DefaultServices are used when it is not set.
+	Services []string
+
+	// VpcEndpointIds are VPC endpoints that are deleted together with the
+	// VPC endpoints of Services, e.g. VPC endpoints owned by the cluster that
+	// were discovered by their tags. These can be interface endpoints or
+	// endpoints of services that are not configured anymore.
+	VpcEndpointIds []string
 }
 
 func NewReconciler(client Client) (Reconciler, error) {
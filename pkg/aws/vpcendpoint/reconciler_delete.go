@@ -2,6 +2,7 @@ package vpcendpoint
 
 import (
 	"context"
+	"strings"
 
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/giantswarm/microerror"
@@ -9,12 +10,18 @@ import (
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
 )
 
-func (r *reconciler) ReconcileDelete(ctx context.Context, request aws.ReconcileRequest[aws.DeletedCloudResourceSpec]) (err error) {
+func (r *reconciler) ReconcileDelete(ctx context.Context, request aws.ReconcileRequest[DeleteSpec]) (err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Started reconciling VPC endpoint deletion")
+	ctx, span := tracing.Start(ctx, "vpcendpoint.ReconcileDelete",
+		tracing.ClusterNameKey.String(request.ClusterName),
+		tracing.RegionKey.String(request.Region),
+		tracing.VpcIdKey.String(request.Spec.VpcId))
 	defer func() {
+		tracing.End(span, err)
 		if err == nil {
 			logger.Info("Finished reconciling VPC endpoint deletion")
 		} else {
@@ -35,27 +42,111 @@ func (r *reconciler) ReconcileDelete(ctx context.Context, request aws.ReconcileR
 	if request.Region == "" {
 		return microerror.Maskf(errors.InvalidConfigError, "%T.Region must not be empty", request)
 	}
-	if request.Spec.Id == "" {
-		return microerror.Maskf(errors.InvalidConfigError, "%T.Spec.Id must not be empty", request)
+	if request.Spec.VpcId == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Spec.VpcId must not be empty", request)
 	}
 
-	input := DeleteVpcEndpointInput{
-		RoleARN:     request.RoleARN,
-		Region:      request.Region,
-		VpcId:       request.Spec.Id,
-		Type:        ec2Types.VpcEndpointTypeGateway,
-		ServiceName: s3ServiceName(request.Region),
+	services := request.Spec.Services
+	if len(services) == 0 {
+		services = DefaultServices
 	}
-	err = r.client.Delete(ctx, input)
+
+	// Deletion of all VPC endpoints is started before waiting for any of
+	// them. VPC endpoints that are not owned by the cluster are skipped, so
+	// that the owned ones are still deleted.
+	var inProgressErr error
+	var notOwned []string
+	for _, service := range services {
+		input := DeleteVpcEndpointInput{
+			RoleARN:     request.RoleARN,
+			Region:      request.Region,
+			VpcId:       request.Spec.VpcId,
+			Type:        ec2Types.VpcEndpointTypeGateway,
+			ServiceName: ServiceName(request.Region, service),
+			ClusterName: request.ClusterName,
+			ClusterUID:  request.ClusterUID,
+		}
+		err = r.delete(ctx, input)
+		if errors.IsResourceDeletionInProgress(err) {
+			inProgressErr = err
+		} else if errors.IsResourceNotOwned(err) {
+			notOwned = append(notOwned, input.ServiceName)
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+	for _, vpcEndpointId := range request.Spec.VpcEndpointIds {
+		input := DeleteVpcEndpointInput{
+			RoleARN:       request.RoleARN,
+			Region:        request.Region,
+			VpcId:         request.Spec.VpcId,
+			ClusterName:   request.ClusterName,
+			ClusterUID:    request.ClusterUID,
+			VpcEndpointId: vpcEndpointId,
+		}
+		err = r.delete(ctx, input)
+		if errors.IsResourceDeletionInProgress(err) {
+			inProgressErr = err
+		} else if errors.IsResourceNotOwned(err) {
+			notOwned = append(notOwned, vpcEndpointId)
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	if inProgressErr != nil {
+		return inProgressErr
+	}
+	if len(notOwned) > 0 {
+		return microerror.Maskf(errors.ResourceNotOwnedError, "VPC endpoints %s are not owned by cluster %s", strings.Join(notOwned, ", "), request.ClusterName)
+	}
+
+	return nil
+}
+
+// delete deletes a single VPC endpoint, either of a service or by its ID.
+func (r *reconciler) delete(ctx context.Context, input DeleteVpcEndpointInput) error {
+	logger := log.FromContext(ctx)
+	if input.VpcEndpointId != "" {
+		logger = logger.WithValues("vpc-endpoint-id", input.VpcEndpointId)
+	} else {
+		logger = logger.WithValues("service-name", input.ServiceName)
+	}
+
+	err := r.client.Delete(ctx, input)
 	if errors.IsVpcEndpointNotFound(err) {
 		logger.Info("Nothing to delete, VPC endpoint not found")
+		return nil
 	} else if errors.IsResourceAlreadyDeleted(err) {
 		logger.Info("Nothing to delete, VPC endpoint already deleted")
-	} else if errors.IsResourceDeletionInProgress(err) {
-		logger.Info("VPC endpoint deletion is already in progress")
+		return nil
 	} else if err != nil {
+		// this includes ResourceDeletionInProgressError, as the caller has to
+		// wait until the VPC endpoint is deleted
 		return microerror.Mask(err)
 	}
 
-	return nil
+	//
+	// VPC endpoints are deleted asynchronously, so we check if the deletion
+	// is already done, which is often the case for gateway endpoints.
+	//
+	getInput := GetVpcEndpointInput{
+		RoleARN:       input.RoleARN,
+		Region:        input.Region,
+		Type:          input.Type,
+		ServiceName:   input.ServiceName,
+		VpcId:         input.VpcId,
+		VpcEndpointId: input.VpcEndpointId,
+	}
+	getOutput, err := r.client.Get(ctx, getInput)
+	if errors.IsVpcEndpointNotFound(err) {
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+	if strings.EqualFold(getOutput.VpcEndpointState, StateDeleted) {
+		return nil
+	}
+
+	return microerror.Maskf(errors.ResourceDeletionInProgressError, "VPC endpoint %s is %s", getOutput.VpcEndpointId, getOutput.VpcEndpointState)
 }
@@ -0,0 +1,239 @@
+package vpcendpoint_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+
+	awsresource "github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+func Test_ReconcileDelete_WaitsForDeletion(t *testing.T) {
+	const clusterName = "test"
+
+	testCases := []struct {
+		name                string
+		stateAfterDeletion  ec2Types.State
+		expectInProgress    bool
+		expectDeleteRequest bool
+	}{
+		{
+			name:                "case 0: VPC endpoint still being deleted",
+			stateAfterDeletion:  "deleting",
+			expectInProgress:    true,
+			expectDeleteRequest: true,
+		},
+		{
+			name:                "case 1: VPC endpoint deleted right away",
+			stateAfterDeletion:  "deleted",
+			expectDeleteRequest: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := ec2Types.State("available")
+			fakeEC2 := awstest.NewEC2().
+				On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+					return &ec2.DescribeVpcEndpointsOutput{
+						VpcEndpoints: []ec2Types.VpcEndpoint{
+							{
+								VpcEndpointId:   aws.String("vpce-1"),
+								ServiceName:     aws.String(vpcendpoint.ServiceName(awstest.Region, vpcendpoint.S3)),
+								VpcEndpointType: ec2Types.VpcEndpointTypeGateway,
+								State:           state,
+								Tags: []ec2Types.Tag{
+									{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")},
+								},
+							},
+						},
+					}, nil
+				}).
+				On("DeleteVpcEndpoints", func(interface{}) (interface{}, error) {
+					state = tc.stateAfterDeletion
+					return &ec2.DeleteVpcEndpointsOutput{}, nil
+				})
+
+			client, err := vpcendpoint.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			reconciler, err := vpcendpoint.NewReconciler(client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request := awsresource.ReconcileRequest[vpcendpoint.DeleteSpec]{
+				Resource:    &capa.AWSCluster{},
+				ClusterName: clusterName,
+				CloudResourceRequest: awsresource.CloudResourceRequest[vpcendpoint.DeleteSpec]{
+					RoleARN: awstest.RoleARN,
+					Region:  awstest.Region,
+					Spec:    vpcendpoint.DeleteSpec{VpcId: "vpc-1"},
+				},
+			}
+			err = reconciler.ReconcileDelete(context.Background(), request)
+			if tc.expectInProgress {
+				if !errors.IsResourceDeletionInProgress(err) {
+					t.Fatalf("expected ResourceDeletionInProgressError, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if deleted := fakeEC2.Calls("DeleteVpcEndpoints") > 0; deleted != tc.expectDeleteRequest {
+				t.Errorf("expected delete request %t, got %t", tc.expectDeleteRequest, deleted)
+			}
+
+			// the next reconciliation does not send another delete request
+			// while the VPC endpoint is being deleted
+			if tc.expectInProgress {
+				err = reconciler.ReconcileDelete(context.Background(), request)
+				if !errors.IsResourceDeletionInProgress(err) {
+					t.Fatalf("expected ResourceDeletionInProgressError, got %v", err)
+				}
+				if calls := fakeEC2.Calls("DeleteVpcEndpoints"); calls != 1 {
+					t.Errorf("expected 1 delete request, got %d", calls)
+				}
+			}
+		})
+	}
+}
+
+func Test_ReconcileDelete_DeletesVpcEndpointsById(t *testing.T) {
+	const clusterName = "test"
+
+	var deletedIds []string
+	fakeEC2 := awstest.NewEC2().
+		On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+			var vpcEndpoints []ec2Types.VpcEndpoint
+			for _, id := range []string{"vpce-interface", "vpce-removed-service"} {
+				state := ec2Types.StateAvailable
+				for _, deletedId := range deletedIds {
+					if id == deletedId {
+						state = ec2Types.StateDeleted
+					}
+				}
+				vpcEndpoints = append(vpcEndpoints, ec2Types.VpcEndpoint{
+					VpcEndpointId:   aws.String(id),
+					ServiceName:     aws.String("com.amazonaws.eu-west-1." + id),
+					VpcEndpointType: ec2Types.VpcEndpointTypeInterface,
+					State:           state,
+					Tags: []ec2Types.Tag{
+						{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")},
+					},
+				})
+			}
+			return &ec2.DescribeVpcEndpointsOutput{VpcEndpoints: vpcEndpoints}, nil
+		}).
+		On("DeleteVpcEndpoints", func(input interface{}) (interface{}, error) {
+			deletedIds = append(deletedIds, input.(*ec2.DeleteVpcEndpointsInput).VpcEndpointIds...)
+			return &ec2.DeleteVpcEndpointsOutput{}, nil
+		})
+
+	client, err := vpcendpoint.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reconciler, err := vpcendpoint.NewReconciler(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := awsresource.ReconcileRequest[vpcendpoint.DeleteSpec]{
+		Resource:    &capa.AWSCluster{},
+		ClusterName: clusterName,
+		CloudResourceRequest: awsresource.CloudResourceRequest[vpcendpoint.DeleteSpec]{
+			RoleARN: awstest.RoleARN,
+			Region:  awstest.Region,
+			Spec: vpcendpoint.DeleteSpec{
+				VpcId:          "vpc-1",
+				VpcEndpointIds: []string{"vpce-interface", "vpce-removed-service"},
+			},
+		},
+	}
+	err = reconciler.ReconcileDelete(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the gateway VPC endpoint of the default service does not exist, so
+	// only the VPC endpoints with the specified IDs are deleted
+	if len(deletedIds) != 2 || deletedIds[0] != "vpce-interface" || deletedIds[1] != "vpce-removed-service" {
+		t.Errorf("expected VPC endpoints vpce-interface and vpce-removed-service to be deleted, got %v", deletedIds)
+	}
+}
+
+func Test_ReconcileDelete_SkipsVpcEndpointsNotOwned(t *testing.T) {
+	const clusterName = "test"
+
+	var deletedIds []string
+	fakeEC2 := awstest.NewEC2().
+		On("DescribeVpcEndpoints", func(input interface{}) (interface{}, error) {
+			var vpcEndpoints []ec2Types.VpcEndpoint
+			for _, id := range []string{"vpce-not-owned", "vpce-owned"} {
+				state := ec2Types.StateAvailable
+				for _, deletedId := range deletedIds {
+					if id == deletedId {
+						state = ec2Types.StateDeleted
+					}
+				}
+				vpcEndpoint := ec2Types.VpcEndpoint{
+					VpcEndpointId:   aws.String(id),
+					ServiceName:     aws.String("com.amazonaws.eu-west-1." + id),
+					VpcEndpointType: ec2Types.VpcEndpointTypeInterface,
+					State:           state,
+				}
+				if id == "vpce-owned" {
+					vpcEndpoint.Tags = []ec2Types.Tag{
+						{Key: aws.String(tags.NameAWSProviderPrefix + clusterName), Value: aws.String("owned")},
+					}
+				}
+				vpcEndpoints = append(vpcEndpoints, vpcEndpoint)
+			}
+			return &ec2.DescribeVpcEndpointsOutput{VpcEndpoints: vpcEndpoints}, nil
+		}).
+		On("DeleteVpcEndpoints", func(input interface{}) (interface{}, error) {
+			deletedIds = append(deletedIds, input.(*ec2.DeleteVpcEndpointsInput).VpcEndpointIds...)
+			return &ec2.DeleteVpcEndpointsOutput{}, nil
+		})
+
+	client, err := vpcendpoint.NewClient(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reconciler, err := vpcendpoint.NewReconciler(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := awsresource.ReconcileRequest[vpcendpoint.DeleteSpec]{
+		Resource:    &capa.AWSCluster{},
+		ClusterName: clusterName,
+		CloudResourceRequest: awsresource.CloudResourceRequest[vpcendpoint.DeleteSpec]{
+			RoleARN: awstest.RoleARN,
+			Region:  awstest.Region,
+			Spec: vpcendpoint.DeleteSpec{
+				VpcId:          "vpc-1",
+				VpcEndpointIds: []string{"vpce-not-owned", "vpce-owned"},
+			},
+		},
+	}
+	err = reconciler.ReconcileDelete(context.Background(), request)
+	if !errors.IsResourceNotOwned(err) {
+		t.Fatalf("expected ResourceNotOwnedError, got %v", err)
+	}
+
+	// the VPC endpoint after the one that is not owned is still deleted
+	if len(deletedIds) != 1 || deletedIds[0] != "vpce-owned" {
+		t.Errorf("expected only VPC endpoint vpce-owned to be deleted, got %v", deletedIds)
+	}
+}
@@ -2,7 +2,6 @@ package vpcendpoint
 
 import (
 	"context"
-	"fmt"
 	"sort"
 
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -13,8 +12,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/naming"
 	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
 	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
 )
 
 const (
@@ -28,17 +29,39 @@ type Spec struct {
 	SecurityGroupIds []string
 	AdditionalTags   map[string]string
 	RouteTableIds    []string
+
+	// Services are short names of the services for which gateway VPC
+	// endpoints are reconciled, e.g. s3 or dynamodb. DefaultServices are
+	// used when it is not set.
+	Services []string
+
+	// NameTemplate renders Name tags of VPC endpoints.
+	// naming.DefaultVpcEndpoint is used when it is not set.
+	NameTemplate naming.Template
 }
 
 type Status struct {
+	Service          string
 	VpcEndpointId    string
 	VpcEndpointState string
 }
 
-func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest[Spec]) (result aws.ReconcileResult[Status], err error) {
+var defaultNameTemplate = naming.MustParse(naming.DefaultVpcEndpoint)
+
+func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest[Spec]) (result aws.ReconcileResult[[]Status], err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Started reconciling VPC endpoint")
+	ctx, span := tracing.Start(ctx, "vpcendpoint.Reconcile",
+		tracing.ClusterNameKey.String(request.ClusterName),
+		tracing.RegionKey.String(request.Region),
+		tracing.VpcIdKey.String(request.Spec.VpcId))
 	defer func() {
+		var vpcEndpointIds []string
+		for _, status := range result.Status {
+			vpcEndpointIds = append(vpcEndpointIds, status.VpcEndpointId)
+		}
+		span.SetAttributes(tracing.VpcEndpointIdKey.StringSlice(vpcEndpointIds))
+		tracing.End(span, err)
 		if err == nil {
 			logger.Info("Finished reconciling VPC endpoint")
 		} else {
@@ -46,36 +69,54 @@ func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest
 		}
 	}()
 
+	services := request.Spec.Services
+	if len(services) == 0 {
+		services = DefaultServices
+	}
+
 	if !shouldReconcileVpcEndpoint(request.Resource.GetAnnotations()) {
-		result.Status = Status{
-			VpcEndpointState: StateAvailable,
+		for _, service := range services {
+			result.Status = append(result.Status, Status{
+				Service:          service,
+				VpcEndpointState: StateAvailable,
+			})
 		}
 		return result, nil
 	}
 
 	if request.ClusterName == "" {
-		return aws.ReconcileResult[Status]{}, microerror.Maskf(errors.InvalidConfigError, "ClusterName must not be empty")
+		return aws.ReconcileResult[[]Status]{}, microerror.Maskf(errors.InvalidConfigError, "ClusterName must not be empty")
 	}
 	if request.RoleARN == "" {
-		return aws.ReconcileResult[Status]{}, microerror.Maskf(errors.InvalidConfigError, "RoleARN must not be empty")
+		return aws.ReconcileResult[[]Status]{}, microerror.Maskf(errors.InvalidConfigError, "RoleARN must not be empty")
 	}
 	if request.Region == "" {
-		return aws.ReconcileResult[Status]{}, microerror.Maskf(errors.InvalidConfigError, "Region must not be empty")
+		return aws.ReconcileResult[[]Status]{}, microerror.Maskf(errors.InvalidConfigError, "Region must not be empty")
 	}
 	if request.Spec.VpcId == "" {
-		return aws.ReconcileResult[Status]{}, microerror.Maskf(errors.InvalidConfigError, "VpcId must not be empty")
+		return aws.ReconcileResult[[]Status]{}, microerror.Maskf(errors.InvalidConfigError, "VpcId must not be empty")
 	}
 
-	result = aws.ReconcileResult[Status]{
-		Status: Status{},
+	result = aws.ReconcileResult[[]Status]{}
+	for _, service := range services {
+		status, err := r.reconcileService(ctx, request, service)
+		if err != nil {
+			return aws.ReconcileResult[[]Status]{}, microerror.Mask(err)
+		}
+		result.Status = append(result.Status, status)
 	}
 
+	return result, nil
+}
+
+// reconcileService reconciles the gateway VPC endpoint of a single service.
+func (r *reconciler) reconcileService(ctx context.Context, request aws.ReconcileRequest[Spec], service string) (Status, error) {
 	// Get existing VPC endpoint
 	getInput := GetVpcEndpointInput{
 		RoleARN:     request.RoleARN,
 		Region:      request.Region,
 		Type:        ec2Types.VpcEndpointTypeGateway,
-		ServiceName: s3ServiceName(request.Region),
+		ServiceName: ServiceName(request.Region, service),
 		VpcId:       request.Spec.VpcId,
 	}
 	getOutput, err := r.client.Get(ctx, getInput)
@@ -85,79 +126,93 @@ func (r *reconciler) Reconcile(ctx context.Context, request aws.ReconcileRequest
 			RoleARN:     request.RoleARN,
 			Region:      request.Region,
 			Type:        ec2Types.VpcEndpointTypeGateway,
-			ServiceName: s3ServiceName(request.Region),
+			ServiceName: ServiceName(request.Region, service),
 			VpcId:       request.Spec.VpcId,
 			VPCEndpointGatewayConfig: &VPCEndpointGatewayConfig{
 				RouteTableIDs: request.Spec.RouteTableIds,
 			},
-			Tags: r.getVpcEndpointTags(request.ClusterName, request.Spec.VpcId, "", S3, request.Region, request.AdditionalTags),
+			Tags:        r.getVpcEndpointTags(request, "", service),
+			ClientToken: aws.ClientToken(request.Resource.GetUID(), "vpcendpoint", request.Spec.VpcId, ServiceName(request.Region, service)),
 		}
 		createOutput, err := r.client.Create(ctx, createInput)
 		if err != nil {
-			return aws.ReconcileResult[Status]{}, microerror.Mask(err)
+			return Status{}, microerror.Mask(err)
 		}
 
-		result.Status = Status(createOutput)
+		return Status{
+			Service:          service,
+			VpcEndpointId:    createOutput.VpcEndpointId,
+			VpcEndpointState: createOutput.VpcEndpointState,
+		}, nil
 	} else if err != nil {
-		return aws.ReconcileResult[Status]{}, microerror.Mask(err)
-	} else {
-		// Sort current routeTablesID, so we can use sort.SearchStrings
-		// when checking difference in slices.
-		// This modifies slice in-place, but we just use it here anyway, so that's
-		// fine.
-		currentRouteTableIds := cloneAndSort(getOutput.VPCEndpointGatewayConfig.RouteTableIDs)
-		wantedRouteTableIds := cloneAndSort(request.Spec.RouteTableIds)
-
-		// securityGroupIDs that we will add, those specified in the input, but not
-		// already present in current state
-		routeTableIdsToBeAdded := diff(wantedRouteTableIds, currentRouteTableIds)
-
-		// securityGroupIDs that we will remove, those already in the current state,
-		// but not present in the input
-		routeTableIdsToBeRemoved := diff(currentRouteTableIds, wantedRouteTableIds)
-
-		updateInput := UpdateVpcEndpointInput{
-			RoleARN:       request.RoleARN,
-			Region:        request.Region,
-			VpcEndpointId: getOutput.VpcEndpointId,
-			VPCEndpointGatewayConfig: &VPCEndpointGatewayUpdateConfig{
-				AddRouteTableIDs:    routeTableIdsToBeAdded,
-				RemoveRouteTableIDs: routeTableIdsToBeRemoved,
-			},
-			Type:        ec2Types.VpcEndpointTypeGateway,
-			ServiceName: s3ServiceName(request.Region),
-			Tags:        r.getVpcEndpointTags(request.ClusterName, request.Spec.VpcId, getOutput.VpcEndpointId, S3, request.Region, request.AdditionalTags),
-		}
+		return Status{}, microerror.Mask(err)
+	}
 
-		err = r.client.Update(ctx, updateInput)
-		if err != nil {
-			return aws.ReconcileResult[Status]{}, microerror.Mask(err)
-		}
-		result.Status = Status{
-			VpcEndpointId:    getOutput.VpcEndpointId,
-			VpcEndpointState: getOutput.VpcEndpointState,
-		}
+	// Sort current routeTablesID, so we can use sort.SearchStrings
+	// when checking difference in slices.
+	// This modifies slice in-place, but we just use it here anyway, so that's
+	// fine.
+	currentRouteTableIds := cloneAndSort(getOutput.VPCEndpointGatewayConfig.RouteTableIDs)
+	wantedRouteTableIds := cloneAndSort(request.Spec.RouteTableIds)
+
+	// securityGroupIDs that we will add, those specified in the input, but not
+	// already present in current state
+	routeTableIdsToBeAdded := diff(wantedRouteTableIds, currentRouteTableIds)
+
+	// securityGroupIDs that we will remove, those already in the current state,
+	// but not present in the input
+	routeTableIdsToBeRemoved := diff(currentRouteTableIds, wantedRouteTableIds)
+
+	updateInput := UpdateVpcEndpointInput{
+		RoleARN:       request.RoleARN,
+		Region:        request.Region,
+		VpcEndpointId: getOutput.VpcEndpointId,
+		VPCEndpointGatewayConfig: &VPCEndpointGatewayUpdateConfig{
+			AddRouteTableIDs:    routeTableIdsToBeAdded,
+			RemoveRouteTableIDs: routeTableIdsToBeRemoved,
+		},
+		Type:        ec2Types.VpcEndpointTypeGateway,
+		ServiceName: ServiceName(request.Region, service),
+		Tags:        r.getVpcEndpointTags(request, getOutput.VpcEndpointId, service),
+
+		CurrentTags:    getOutput.Tags,
+		ManagedTagKeys: request.LastAppliedTagKeys,
 	}
 
-	return result, nil
+	err = r.client.Update(ctx, updateInput)
+	if err != nil {
+		return Status{}, microerror.Mask(err)
+	}
+
+	return Status{
+		Service:          service,
+		VpcEndpointId:    getOutput.VpcEndpointId,
+		VpcEndpointState: getOutput.VpcEndpointState,
+	}, nil
 }
 
-func (r *reconciler) getVpcEndpointTags(clusterName, vpcId, vpcEndpointId, vpcServiceName, region string, additionalTags map[string]string) map[string]string {
+func (r *reconciler) getVpcEndpointTags(request aws.ReconcileRequest[Spec], vpcEndpointId, service string) map[string]string {
 	id := vpcEndpointId
 	if id == "" {
 		id = capaservices.TemporaryResourceID
 	}
-	name := fmt.Sprintf("%s-vpc-endpoint-%s-%s", clusterName, vpcServiceName, region)
+	name := request.Spec.NameTemplate.Or(defaultNameTemplate).Name(naming.Params{
+		ClusterName: request.ClusterName,
+		Region:      request.Region,
+		Role:        capa.PrivateRoleTagValue,
+		Service:     service,
+	})
 
 	allTags := map[string]string{}
-	for k, v := range additionalTags {
+	for k, v := range request.AdditionalTags {
 		allTags[k] = v
 	}
-	allTags["vpc-id"] = vpcId
-	allTags["region"] = region
+	allTags["vpc-id"] = request.Spec.VpcId
+	allTags["region"] = request.Region
 
 	params := tags.BuildParams{
-		ClusterName: clusterName,
+		ClusterName: request.ClusterName,
+		ClusterUID:  request.ClusterUID,
 		ResourceID:  id,
 		Name:        name,
 		Role:        capa.PrivateRoleTagValue,
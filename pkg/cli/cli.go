@@ -0,0 +1,220 @@
+// Package cli implements the aws-vpc-operator subcommands that inspect and
+// reconcile the network of a single AWSCluster that is read from a YAML file,
+// without a Kubernetes cluster, that find orphaned resources of deleted
+// clusters, and that generate the IAM policy of the assumed role. They use the
+// same AWS clients and reconciliation logic as the controller, e.g. for
+// debugging from a laptop.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/giantswarm/microerror"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/quotas"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/ratelimit"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	InspectCommand   = "inspect"
+	ReconcileCommand = "reconcile"
+	OrphansCommand   = "orphans"
+	IAMPolicyCommand = "iam-policy"
+
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// IsCommand checks if the argument is the name of a subcommand.
+func IsCommand(arg string) bool {
+	return arg == InspectCommand || arg == ReconcileCommand || arg == OrphansCommand || arg == IAMPolicyCommand
+}
+
+// Run runs the subcommand that is the first of the arguments, and writes its
+// output to stdout.
+func Run(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return microerror.Maskf(errors.InvalidConfigError, "command must not be empty")
+	}
+
+	switch args[0] {
+	case InspectCommand:
+		return microerror.Mask(runInspect(ctx, args[1:], stdout))
+	case ReconcileCommand:
+		return microerror.Mask(runReconcile(ctx, args[1:], stdout))
+	case OrphansCommand:
+		return microerror.Mask(runOrphans(ctx, args[1:], stdout))
+	case IAMPolicyCommand:
+		return microerror.Mask(runIAMPolicy(ctx, args[1:], stdout))
+	default:
+		return microerror.Maskf(errors.InvalidConfigError, "unknown command %q, known commands are %s, %s, %s and %s", args[0], InspectCommand, ReconcileCommand, OrphansCommand, IAMPolicyCommand)
+	}
+}
+
+// options are the flags shared by all subcommands.
+type options struct {
+	awsClusterFile string
+	identityFile   string
+	roleArn        string
+	configFile     string
+	output         string
+	verbose        bool
+}
+
+func (o *options) bind(flags *flag.FlagSet) {
+	flags.StringVar(&o.awsClusterFile, "awscluster", "", "The YAML file with the AWSCluster.")
+	flags.StringVar(&o.identityFile, "identity", "", "The YAML file with the AWSClusterRoleIdentity of the AWSCluster. Not needed when --role-arn is set.")
+	flags.StringVar(&o.roleArn, "role-arn", "", "The ARN of the role that is assumed for AWS API calls. Overrides the role of the identity.")
+	flags.StringVar(&o.configFile, "config-file", "", "The operator configuration file. The built-in defaults are used when empty.")
+	flags.StringVar(&o.output, "output", OutputTable, fmt.Sprintf("The output format, %s or %s.", OutputTable, OutputJSON))
+	flags.BoolVar(&o.verbose, "verbose", false, "Log the calls of the AWS clients to stderr.")
+}
+
+func (o *options) validate() error {
+	if o.awsClusterFile == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "--awscluster must not be empty")
+	}
+	if o.identityFile == "" && o.roleArn == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "--identity or --role-arn must be set")
+	}
+	if o.output != OutputTable && o.output != OutputJSON {
+		return microerror.Maskf(errors.InvalidConfigError, "--output must be %s or %s", OutputTable, OutputJSON)
+	}
+	return nil
+}
+
+// input is what the subcommands work with.
+type input struct {
+	AWSCluster *capa.AWSCluster
+	Identity   *capa.AWSClusterRoleIdentity
+	Config     *config.Config
+}
+
+// load reads the AWSCluster, its identity and the operator configuration.
+// When only the role ARN is set, an identity with that role is created.
+func (o *options) load() (input, error) {
+	setLogger(o.verbose)
+
+	awsCluster := &capa.AWSCluster{}
+	err := readObject(o.awsClusterFile, "AWSCluster", awsCluster)
+	if err != nil {
+		return input{}, microerror.Mask(err)
+	}
+	if awsCluster.Spec.Region == "" {
+		return input{}, microerror.Maskf(errors.InvalidConfigError, "AWSCluster %s/%s does not have Spec.Region set", awsCluster.Namespace, awsCluster.Name)
+	}
+
+	identity := &capa.AWSClusterRoleIdentity{}
+	if o.identityFile != "" {
+		err = readObject(o.identityFile, "AWSClusterRoleIdentity", identity)
+		if err != nil {
+			return input{}, microerror.Mask(err)
+		}
+	} else if awsCluster.Spec.IdentityRef != nil {
+		identity.Name = awsCluster.Spec.IdentityRef.Name
+	} else {
+		identity.Name = awsCluster.Name
+	}
+	if o.roleArn != "" {
+		identity.Spec.RoleArn = o.roleArn
+	}
+	if awsCluster.Spec.IdentityRef == nil {
+		awsCluster.Spec.IdentityRef = &capa.AWSIdentityReference{
+			Kind: capa.ClusterRoleIdentityKind,
+			Name: identity.Name,
+		}
+	}
+	if awsCluster.Spec.IdentityRef.Name != identity.Name {
+		return input{}, microerror.Maskf(errors.InvalidConfigError, "AWSCluster uses identity %s, but identity %s was specified", awsCluster.Spec.IdentityRef.Name, identity.Name)
+	}
+
+	operatorConfig, err := config.Load(o.configFile, config.Default())
+	if err != nil {
+		return input{}, microerror.Mask(err)
+	}
+
+	return input{
+		AWSCluster: awsCluster,
+		Identity:   identity,
+		Config:     operatorConfig,
+	}, nil
+}
+
+// setLogger logs to stderr in verbose mode, and discards logs otherwise, so
+// that they are not mixed with the output.
+func setLogger(verbose bool) {
+	if verbose {
+		ctrl.SetLogger(zap.New(zap.WriteTo(os.Stderr)))
+	} else {
+		ctrl.SetLogger(logr.Discard())
+	}
+}
+
+// readObject reads a Kubernetes object of the specified kind from a YAML file.
+func readObject(path string, kind string, object runtime.Object) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	err = yaml.Unmarshal(data, object)
+	if err != nil {
+		return microerror.Maskf(errors.InvalidConfigError, "invalid %s in %s: %s", kind, path, err)
+	}
+	if actualKind := object.GetObjectKind().GroupVersionKind().Kind; actualKind != kind {
+		return microerror.Maskf(errors.InvalidConfigError, "%s must contain a %s, got %q", path, kind, actualKind)
+	}
+	return nil
+}
+
+// newAWSClients creates the EC2 client, the client for assuming roles and the
+// client for service quota limits in the same way as the controller, with the
+// credentials from the environment.
+func newAWSClients(ctx context.Context, store *config.Store) (*ec2.Client, assumerole.Client, quotas.LimitsAPI, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, nil, nil, microerror.Mask(err)
+	}
+	cfg.Retryer = func() aws.Retryer {
+		return config.NewRetryer(store)
+	}
+
+	rateLimiter := ratelimit.NewLimiter(func() ratelimit.Settings {
+		return store.Get().AWS.RateLimit
+	})
+	assumeRoleClient, err := assumerole.NewClient(sts.NewFromConfig(cfg), rateLimiter)
+	if err != nil {
+		return nil, nil, nil, microerror.Mask(err)
+	}
+
+	limits, err := quotas.NewLimitsClient(servicequotas.NewFromConfig(cfg), assumeRoleClient)
+	if err != nil {
+		return nil, nil, nil, microerror.Mask(err)
+	}
+
+	return ec2.NewFromConfig(cfg), assumeRoleClient, limits, nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return microerror.Mask(encoder.Encode(v))
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-vpc-operator/controllers"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/discovery"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/quotas"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+func newInput(t *testing.T, vpcId string, subnets ...capa.SubnetSpec) input {
+	t.Helper()
+
+	awsCluster := &capa.AWSCluster{}
+	awsCluster.Namespace = "org-test"
+	awsCluster.Name = "test"
+	awsCluster.Annotations = map[string]string{annotation.AWSVPCMode: annotation.AWSVPCModePrivate}
+	awsCluster.Spec.Region = awstest.Region
+	awsCluster.Spec.IdentityRef = &capa.AWSIdentityReference{Kind: capa.ClusterRoleIdentityKind, Name: "test"}
+	awsCluster.Spec.NetworkSpec.VPC.ID = vpcId
+	awsCluster.Spec.NetworkSpec.VPC.CidrBlock = "10.0.0.0/16"
+	awsCluster.Spec.NetworkSpec.Subnets = subnets
+
+	identity := &capa.AWSClusterRoleIdentity{}
+	identity.Name = "test"
+	identity.Spec.RoleArn = awstest.RoleARN
+
+	operatorConfig, err := config.Parse(nil, config.Default())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return input{
+		AWSCluster: awsCluster,
+		Identity:   identity,
+		Config:     operatorConfig,
+	}
+}
+
+func Test_inspect(t *testing.T) {
+	fakeEC2 := awstest.NewEC2().
+		On("DescribeVpcs", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcsOutput{Vpcs: []ec2Types.Vpc{
+				{VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.0.0/16"), State: ec2Types.VpcStateAvailable},
+			}}, nil
+		}).
+		On("DescribeSubnets", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeSubnetsOutput{Subnets: []ec2Types.Subnet{
+				{SubnetId: aws.String("subnet-1"), VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.0.0/20"), AvailabilityZone: aws.String("eu-west-1a"), State: ec2Types.SubnetStateAvailable},
+				{SubnetId: aws.String("subnet-2"), VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.16.0/20"), AvailabilityZone: aws.String("eu-west-1b"), State: ec2Types.SubnetStateAvailable},
+			}}, nil
+		}).
+		On("DescribeRouteTables", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeRouteTablesOutput{RouteTables: []ec2Types.RouteTable{
+				{RouteTableId: aws.String("rtb-1"), VpcId: aws.String("vpc-1"), Associations: []ec2Types.RouteTableAssociation{
+					{SubnetId: aws.String("subnet-1"), AssociationState: &ec2Types.RouteTableAssociationState{State: ec2Types.RouteTableAssociationStateCodeAssociated}},
+				}},
+			}}, nil
+		}).
+		On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcEndpointsOutput{}, nil
+		})
+
+	c, err := newClients(fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, err := inspect(context.Background(), c, newInput(t, "vpc-1", capa.SubnetSpec{
+		ID:               "subnet-1",
+		CidrBlock:        "10.0.0.0/20",
+		AvailabilityZone: "eu-west-1a",
+		RouteTableID:     aws.String("rtb-1"),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		resourceType discovery.ResourceType
+		id           string
+		inSync       bool
+	}{
+		{resourceType: discovery.ResourceTypeVpc, id: "vpc-1", inSync: true},
+		{resourceType: discovery.ResourceTypeSubnet, id: "subnet-1", inSync: true},
+		{resourceType: discovery.ResourceTypeRouteTable, id: "rtb-1", inSync: true},
+		{resourceType: discovery.ResourceTypeSubnet, id: "subnet-2", inSync: false},
+		{resourceType: discovery.ResourceTypeVpcEndpoint, id: "", inSync: false},
+	}
+	if len(state.Resources) != len(expected) {
+		t.Fatalf("expected %d resources, got %v", len(expected), state.Resources)
+	}
+	for i, e := range expected {
+		resource := state.Resources[i]
+		if resource.Type != e.resourceType || resource.Id != e.id || resource.InSync != e.inSync {
+			t.Errorf("expected %s %q with in sync %t, got %v", e.resourceType, e.id, e.inSync, resource)
+		}
+	}
+	if state.InSync() {
+		t.Errorf("expected state not to be in sync")
+	}
+}
+
+func Test_reconcileOnce_DryRun(t *testing.T) {
+	fakeEC2 := awstest.NewEC2().
+		On("DescribeVpcs", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcsOutput{}, nil
+		}).
+		On("CreateVpc", func(input interface{}) (interface{}, error) {
+			if !aws.ToBool(input.(*ec2.CreateVpcInput).DryRun) {
+				t.Fatalf("expected CreateVpc to be called with DryRun")
+			}
+			return nil, &smithy.GenericAPIError{Code: "DryRunOperation"}
+		})
+	// service quotas are checked before the VPC is planned
+	fakeEC2.On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+		return &ec2.DescribeVpcEndpointsOutput{}, nil
+	})
+	// IAM permissions are checked with DryRun calls before the VPC is planned
+	for _, operation := range []string{"CreateSubnet", "CreateRouteTable", "AssociateRouteTable", "CreateVpcEndpoint", "CreateTags", "DeleteVpc", "DeleteSubnet", "DeleteRouteTable", "DisassociateRouteTable", "DeleteVpcEndpoints", "DeleteTags"} {
+		fakeEC2.On(operation, func(interface{}) (interface{}, error) {
+			return nil, &smithy.GenericAPIError{Code: "DryRunOperation"}
+		})
+	}
+
+	in := newInput(t, "")
+	in.Config.DryRun = true
+	// the AWSCluster is reconciled even when it is paused for the operator
+	in.AWSCluster.Annotations[capi.PausedAnnotation] = "true"
+	awsCluster, result, err := reconcileOnce(context.Background(), in, func(k8sClient client.Client, scheme *runtime.Scheme, plans *dryrun.Plans) (*controllers.AWSClusterReconciler, error) {
+		return controllers.NewAWSClusterReconciler(k8sClient, scheme, record.NewFakeRecorder(10), fakeEC2.Client(), awstest.AssumeRoleClient{}, awstest.Limits{quotas.VpcsPerRegion.QuotaCode: 5, quotas.GatewayVpcEndpointsPerRegion.QuotaCode: 20}, config.NewStore(in.Config), plans)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Applied {
+		t.Errorf("expected changes not to be applied")
+	}
+	if len(result.Operations) != 1 || result.Operations[0].Action != "CreateVpc" {
+		t.Errorf("expected planned CreateVpc, got %v", result.Operations)
+	}
+	if awsCluster.Spec.NetworkSpec.VPC.ID != "" {
+		t.Errorf("expected AWSCluster spec not to be changed, got VPC %q", awsCluster.Spec.NetworkSpec.VPC.ID)
+	}
+	if !conditions.IsFalse(awsCluster, controllers.NoChangesPlanned) {
+		t.Errorf("expected condition %s to be false", controllers.NoChangesPlanned)
+	}
+	if _, paused := awsCluster.Annotations[capi.PausedAnnotation]; !paused {
+		t.Errorf("expected AWSCluster to be still paused")
+	}
+}
+
+func Test_runReconcile_ApplyRequiresPausedAWSCluster(t *testing.T) {
+	awsClusterFile := filepath.Join(t.TempDir(), "awscluster.yaml")
+	err := os.WriteFile(awsClusterFile, []byte(`apiVersion: infrastructure.cluster.x-k8s.io/v1beta2
+kind: AWSCluster
+metadata:
+  name: test
+  namespace: org-test
+  annotations:
+    aws.giantswarm.io/vpc-mode: private
+spec:
+  region: eu-west-1
+`), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = runReconcile(context.Background(), []string{"--awscluster", awsClusterFile, "--role-arn", awstest.RoleARN, "--once", "--apply"}, io.Discard)
+	if !errors.IsInvalidConfig(err) {
+		t.Errorf("expected invalid config error, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"io"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/iampolicy"
+)
+
+// runIAMPolicy writes the least-privilege IAM policy of the role that is
+// assumed for AWSClusters, for the operator configuration.
+func runIAMPolicy(_ context.Context, args []string, stdout io.Writer) error {
+	var configFile string
+	var unmanagedNetworksOnly bool
+	flags := flag.NewFlagSet(IAMPolicyCommand, flag.ContinueOnError)
+	flags.StringVar(&configFile, "config-file", "", "The operator configuration file, whose feature gates decide which actions are needed. The built-in defaults are used when empty.")
+	flags.BoolVar(&unmanagedNetworksOnly, "unmanaged-networks-only", false, "Leave out the actions for managed networks, when all AWSClusters that use the role have unmanaged networks.")
+	err := flags.Parse(args)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	operatorConfig, err := config.Load(configFile, config.Default())
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	policy, err := iampolicy.Generate(iampolicy.Options{
+		Config:                operatorConfig,
+		UnmanagedNetworksOnly: unmanagedNetworksOnly,
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return microerror.Mask(writeJSON(stdout, policy))
+}
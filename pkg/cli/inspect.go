@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/discovery"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/subnets"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpc"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	stateNotCreated  = "not created"
+	stateNotFound    = "not found"
+	stateNotInSpec   = "not in AWSCluster spec"
+	stateOwned       = "owned by cluster"
+	stateUserManaged = "user managed"
+)
+
+// State is the desired and actual state of the network of an AWSCluster.
+type State struct {
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Resources []ResourceState `json:"resources"`
+}
+
+// InSync checks if all resources are in the desired state.
+func (s State) InSync() bool {
+	for _, resource := range s.Resources {
+		if !resource.InSync {
+			return false
+		}
+	}
+	return true
+}
+
+// ResourceState is the desired and actual state of an AWS resource.
+type ResourceState struct {
+	Type    discovery.ResourceType `json:"type"`
+	Id      string                 `json:"id,omitempty"`
+	Desired string                 `json:"desired"`
+	Actual  string                 `json:"actual"`
+	InSync  bool                   `json:"inSync"`
+}
+
+// clients are the AWS clients that are used to get the actual state.
+type clients struct {
+	vpc         vpc.Client
+	subnets     subnets.Client
+	vpcEndpoint vpcendpoint.Client
+	discovery   discovery.Client
+}
+
+func newClients(ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (clients, error) {
+	vpcClient, err := vpc.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return clients{}, microerror.Mask(err)
+	}
+	subnetsClient, err := subnets.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return clients{}, microerror.Mask(err)
+	}
+	vpcEndpointClient, err := vpcendpoint.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return clients{}, microerror.Mask(err)
+	}
+	discoveryClient, err := discovery.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return clients{}, microerror.Mask(err)
+	}
+
+	return clients{
+		vpc:         vpcClient,
+		subnets:     subnetsClient,
+		vpcEndpoint: vpcEndpointClient,
+		discovery:   discoveryClient,
+	}, nil
+}
+
+func runInspect(ctx context.Context, args []string, stdout io.Writer) error {
+	var o options
+	flags := flag.NewFlagSet(InspectCommand, flag.ContinueOnError)
+	o.bind(flags)
+	err := flags.Parse(args)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	err = o.validate()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	in, err := o.load()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	ec2Client, assumeRoleClient, _, err := newAWSClients(ctx, config.NewStore(in.Config))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	c, err := newClients(ec2Client, assumeRoleClient)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	state, err := inspect(ctx, c, in)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if o.output == OutputJSON {
+		return microerror.Mask(writeJSON(stdout, state))
+	}
+	return microerror.Mask(writeStateTable(stdout, state))
+}
+
+// inspect compares the network in the AWSCluster spec with the resources in
+// AWS. Resources that are owned by the cluster, but that are not in the spec,
+// are listed as well.
+func inspect(ctx context.Context, c clients, in input) (State, error) {
+	awsCluster := in.AWSCluster
+	roleArn := in.Identity.Spec.RoleArn
+	region := awsCluster.Spec.Region
+
+	state := State{
+		Namespace: awsCluster.Namespace,
+		Name:      awsCluster.Name,
+	}
+	add := func(resource ResourceState) {
+		state.Resources = append(state.Resources, resource)
+	}
+
+	//
+	// VPC
+	//
+	vpcId := awsCluster.Spec.NetworkSpec.VPC.ID
+	vpcExists := false
+	{
+		desiredCidr := awsCluster.Spec.NetworkSpec.VPC.CidrBlock
+		if desiredCidr == "" {
+			desiredCidr = in.Config.Defaults.VpcCidr
+		}
+		resource := ResourceState{
+			Type:    discovery.ResourceTypeVpc,
+			Id:      vpcId,
+			Desired: fmt.Sprintf("%s %s", desiredCidr, vpc.VpcStateAvailable),
+			Actual:  stateNotCreated,
+		}
+		if vpcId != "" {
+			output, err := c.vpc.Get(ctx, vpc.GetVpcInput{
+				RoleARN:     roleArn,
+				Region:      region,
+				VpcId:       vpcId,
+				ClusterName: awsCluster.Name,
+			})
+			if errors.IsVpcNotFound(err) {
+				resource.Actual = stateNotFound
+			} else if err != nil {
+				return State{}, microerror.Mask(err)
+			} else {
+				vpcExists = true
+				resource.Actual = fmt.Sprintf("%s %s", output.CidrBlock, output.State)
+				resource.InSync = output.CidrBlock == desiredCidr && output.State == vpc.VpcStateAvailable
+			}
+		}
+		add(resource)
+	}
+
+	//
+	// Subnets and their route tables
+	//
+	var actualSubnets subnets.GetSubnetsOutput
+	if vpcExists {
+		var err error
+		actualSubnets, err = c.subnets.Get(ctx, subnets.GetSubnetsInput{
+			RoleARN:     roleArn,
+			Region:      region,
+			VpcId:       vpcId,
+			ClusterName: awsCluster.Name,
+		})
+		if err != nil {
+			return State{}, microerror.Mask(err)
+		}
+	}
+	matchedSubnets := map[string]bool{}
+	for _, subnetSpec := range awsCluster.Spec.NetworkSpec.Subnets {
+		var actual *subnets.GetSubnetOutput
+		for i := range actualSubnets {
+			if (subnetSpec.ID != "" && actualSubnets[i].SubnetId == subnetSpec.ID) ||
+				(subnetSpec.ID == "" && actualSubnets[i].CidrBlock == subnetSpec.CidrBlock) {
+				actual = &actualSubnets[i]
+				break
+			}
+		}
+
+		subnet := ResourceState{
+			Type:    discovery.ResourceTypeSubnet,
+			Id:      subnetSpec.ID,
+			Desired: fmt.Sprintf("%s %s %s", subnetSpec.CidrBlock, subnetSpec.AvailabilityZone, subnets.SubnetStateAvailable),
+			Actual:  stateNotCreated,
+		}
+		if subnetSpec.ID != "" {
+			subnet.Actual = stateNotFound
+		}
+		routeTable := ResourceState{
+			Type:    discovery.ResourceTypeRouteTable,
+			Desired: fmt.Sprintf("associated with subnet %s", subnetSpec.CidrBlock),
+			Actual:  stateNotCreated,
+		}
+		if subnetSpec.RouteTableID != nil {
+			routeTable.Id = *subnetSpec.RouteTableID
+		}
+
+		if actual != nil {
+			matchedSubnets[actual.SubnetId] = true
+			subnet.Id = actual.SubnetId
+			subnet.Actual = fmt.Sprintf("%s %s %s", actual.CidrBlock, actual.AvailabilityZone, actual.State)
+			subnet.InSync = actual.CidrBlock == subnetSpec.CidrBlock &&
+				actual.AvailabilityZone == subnetSpec.AvailabilityZone &&
+				actual.State == subnets.SubnetStateAvailable
+
+			association := actual.RouteTableAssociation
+			if association.RouteTableId != "" {
+				routeTable.Actual = fmt.Sprintf("%s with subnet %s", association.AssociationStateCode, actual.CidrBlock)
+				routeTable.InSync = association.RouteTableId == routeTable.Id &&
+					association.AssociationStateCode == subnets.AssociationStateCodeAssociated
+				if routeTable.Id == "" {
+					routeTable.Id = association.RouteTableId
+				} else if association.RouteTableId != routeTable.Id {
+					routeTable.Actual = fmt.Sprintf("subnet %s is %s with %s", actual.CidrBlock, association.AssociationStateCode, association.RouteTableId)
+				}
+			}
+		}
+		add(subnet)
+		add(routeTable)
+	}
+	for _, actual := range actualSubnets {
+		if matchedSubnets[actual.SubnetId] {
+			continue
+		}
+		add(ResourceState{
+			Type:    discovery.ResourceTypeSubnet,
+			Id:      actual.SubnetId,
+			Desired: stateNotInSpec,
+			Actual:  fmt.Sprintf("%s %s %s", actual.CidrBlock, actual.AvailabilityZone, actual.State),
+		})
+	}
+
+	//
+	// Gateway VPC endpoints
+	//
+	userManaged := awsCluster.Annotations[annotation.VPCEndpointModeAnnotation] == annotation.VPCEndpointModeUserManaged
+	for _, service := range in.Config.VpcEndpoints.GatewayServices {
+		serviceName := vpcendpoint.ServiceName(region, service)
+		resource := ResourceState{
+			Type:    discovery.ResourceTypeVpcEndpoint,
+			Desired: fmt.Sprintf("%s %s", serviceName, strings.ToLower(vpcendpoint.StateAvailable)),
+			Actual:  stateNotCreated,
+		}
+		if userManaged {
+			resource.Desired = stateUserManaged
+			resource.Actual = stateUserManaged
+			resource.InSync = true
+		} else if vpcExists {
+			output, err := c.vpcEndpoint.Get(ctx, vpcendpoint.GetVpcEndpointInput{
+				RoleARN:     roleArn,
+				Region:      region,
+				ServiceName: serviceName,
+				Type:        ec2Types.VpcEndpointTypeGateway,
+				VpcId:       vpcId,
+			})
+			if err != nil && !errors.IsVpcEndpointNotFound(err) {
+				return State{}, microerror.Mask(err)
+			} else if err == nil {
+				resource.Id = output.VpcEndpointId
+				resource.Actual = fmt.Sprintf("%s %s", serviceName, strings.ToLower(output.VpcEndpointState))
+				resource.InSync = strings.EqualFold(output.VpcEndpointState, vpcendpoint.StateAvailable)
+			}
+		}
+		add(resource)
+	}
+
+	//
+	// Resources that are owned by the cluster, but not in the spec
+	//
+	discovered, err := c.discovery.Discover(ctx, discovery.DiscoverInput{
+		RoleARN:     roleArn,
+		Region:      region,
+		ClusterName: awsCluster.Name,
+		ClusterUID:  string(awsCluster.UID),
+	})
+	if err != nil {
+		return State{}, microerror.Mask(err)
+	}
+	listed := map[string]bool{}
+	for _, resource := range state.Resources {
+		listed[resource.Id] = true
+	}
+	for _, resource := range discovered {
+		if listed[resource.Id] {
+			continue
+		}
+		add(ResourceState{
+			Type:    resource.Type,
+			Id:      resource.Id,
+			Desired: stateNotInSpec,
+			Actual:  stateOwned,
+		})
+	}
+
+	return state, nil
+}
+
+func writeStateTable(w io.Writer, state State) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tID\tDESIRED\tACTUAL\tIN SYNC")
+	for _, resource := range state.Resources {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\n", resource.Type, dash(resource.Id), resource.Desired, resource.Actual, resource.InSync)
+	}
+	return microerror.Mask(tw.Flush())
+}
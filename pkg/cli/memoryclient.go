@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// memoryClient is a minimal in-memory Kubernetes API for reconcileOnce. It
+// supports what the controller and the Cluster API patch helper use: getting,
+// creating, updating and deleting objects, and JSON merge patches of objects
+// and their status. Lists and other patch types are not supported.
+//
+// The status is not a separate subresource, so updates and patches change
+// the whole object. The patch helper only sends the changed fields of the
+// spec and the status in separate patches, so they do not overwrite each
+// other.
+type memoryClient struct {
+	scheme *runtime.Scheme
+
+	mutex           sync.Mutex
+	objects         map[memoryKey][]byte
+	resourceVersion int
+}
+
+type memoryKey struct {
+	gvk schema.GroupVersionKind
+	key client.ObjectKey
+}
+
+var _ client.Client = &memoryClient{}
+
+func newMemoryClient(scheme *runtime.Scheme, objects ...client.Object) (*memoryClient, error) {
+	c := &memoryClient{
+		scheme:  scheme,
+		objects: map[memoryKey][]byte{},
+	}
+	for _, obj := range objects {
+		err := c.Create(context.Background(), obj)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+	return c, nil
+}
+
+func (c *memoryClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	k, err := c.keyFor(obj, key)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	data, ok := c.objects[k]
+	if !ok {
+		return notFound(k)
+	}
+	return microerror.Mask(decode(data, obj))
+}
+
+func (c *memoryClient) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return microerror.Maskf(errors.InvalidConfigError, "listing objects is not supported by the in-memory Kubernetes API")
+}
+
+func (c *memoryClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	k, err := c.keyFor(obj, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if _, ok := c.objects[k]; ok {
+		return apierrors.NewAlreadyExists(groupResource(k), k.key.Name)
+	}
+	return microerror.Mask(c.store(k, obj))
+}
+
+func (c *memoryClient) Delete(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	k, err := c.keyFor(obj, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if _, ok := c.objects[k]; !ok {
+		return notFound(k)
+	}
+	delete(c.objects, k)
+	return nil
+}
+
+func (c *memoryClient) DeleteAllOf(context.Context, client.Object, ...client.DeleteAllOfOption) error {
+	return microerror.Maskf(errors.InvalidConfigError, "deleting all objects is not supported by the in-memory Kubernetes API")
+}
+
+func (c *memoryClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	k, err := c.keyFor(obj, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if _, ok := c.objects[k]; !ok {
+		return notFound(k)
+	}
+	return microerror.Mask(c.store(k, obj))
+}
+
+func (c *memoryClient) Patch(_ context.Context, obj client.Object, patch client.Patch, _ ...client.PatchOption) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	k, err := c.keyFor(obj, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if patch.Type() != types.MergePatchType {
+		return microerror.Maskf(errors.InvalidConfigError, "patch type %s is not supported by the in-memory Kubernetes API", patch.Type())
+	}
+	current, ok := c.objects[k]
+	if !ok {
+		return notFound(k)
+	}
+	patchData, err := patch.Data(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	patched, err := jsonpatch.MergePatch(current, patchData)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	err = decode(patched, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	return microerror.Mask(c.store(k, obj))
+}
+
+func (c *memoryClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *memoryClient) SubResource(subResource string) client.SubResourceClient {
+	return &memorySubResourceClient{client: c, subResource: subResource}
+}
+
+func (c *memoryClient) Scheme() *runtime.Scheme {
+	return c.scheme
+}
+
+func (c *memoryClient) RESTMapper() meta.RESTMapper {
+	return nil
+}
+
+func (c *memoryClient) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	return apiutil.GVKForObject(obj, c.scheme)
+}
+
+// IsObjectNamespaced returns true for all objects, the in-memory API treats
+// all objects as namespaced.
+func (c *memoryClient) IsObjectNamespaced(runtime.Object) (bool, error) {
+	return true, nil
+}
+
+func (c *memoryClient) keyFor(obj runtime.Object, key client.ObjectKey) (memoryKey, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return memoryKey{}, microerror.Mask(err)
+	}
+	return memoryKey{gvk: gvk, key: key}, nil
+}
+
+// store sets a new resource version of the object and stores it. Like the
+// Kubernetes API, it removes objects that are deleted and have no finalizers
+// left.
+func (c *memoryClient) store(k memoryKey, obj client.Object) error {
+	if !obj.GetDeletionTimestamp().IsZero() && len(obj.GetFinalizers()) == 0 {
+		delete(c.objects, k)
+		return nil
+	}
+	c.resourceVersion++
+	obj.SetResourceVersion(strconv.Itoa(c.resourceVersion))
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	c.objects[k] = data
+	return nil
+}
+
+// memorySubResourceClient updates and patches subresources of objects in the
+// in-memory API, which changes the whole object.
+type memorySubResourceClient struct {
+	client      *memoryClient
+	subResource string
+}
+
+func (c *memorySubResourceClient) Get(context.Context, client.Object, client.Object, ...client.SubResourceGetOption) error {
+	return microerror.Maskf(errors.InvalidConfigError, "getting subresource %s is not supported by the in-memory Kubernetes API", c.subResource)
+}
+
+func (c *memorySubResourceClient) Create(context.Context, client.Object, client.Object, ...client.SubResourceCreateOption) error {
+	return microerror.Maskf(errors.InvalidConfigError, "creating subresource %s is not supported by the in-memory Kubernetes API", c.subResource)
+}
+
+func (c *memorySubResourceClient) Update(ctx context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	return microerror.Mask(c.client.Update(ctx, obj))
+}
+
+func (c *memorySubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, _ ...client.SubResourcePatchOption) error {
+	return microerror.Mask(c.client.Patch(ctx, obj, patch))
+}
+
+// decode replaces the object with the JSON data, so that fields that are not
+// set in the data are not kept from the object.
+func decode(data []byte, obj client.Object) error {
+	v := reflect.ValueOf(obj).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return microerror.Mask(json.Unmarshal(data, obj))
+}
+
+func notFound(k memoryKey) error {
+	return apierrors.NewNotFound(groupResource(k), k.key.Name)
+}
+
+func groupResource(k memoryKey) schema.GroupResource {
+	return schema.GroupResource{Group: k.gvk.Group, Resource: strings.ToLower(k.gvk.Kind)}
+}
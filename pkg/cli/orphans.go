@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/giantswarm/microerror"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/orphans"
+)
+
+// runOrphans scans the targets for resources of clusters without an
+// AWSCluster in the management cluster of the current kubeconfig context.
+func runOrphans(ctx context.Context, args []string, stdout io.Writer) error {
+	var targetsFlag, deleteClustersFlag, output string
+	var verbose bool
+	flags := flag.NewFlagSet(OrphansCommand, flag.ContinueOnError)
+	flags.StringVar(&targetsFlag, "targets", "", "Comma-separated roles and regions to scan, in the format <role-arn>@<region>.")
+	flags.StringVar(&deleteClustersFlag, "delete-clusters", "", "Comma-separated names of clusters whose orphaned resources are deleted. Without it, orphaned resources are only listed.")
+	flags.StringVar(&output, "output", OutputTable, fmt.Sprintf("The output format, %s or %s.", OutputTable, OutputJSON))
+	flags.BoolVar(&verbose, "verbose", false, "Log the calls of the AWS clients to stderr.")
+	err := flags.Parse(args)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	targets, err := orphans.ParseTargets(targetsFlag)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if len(targets) == 0 {
+		return microerror.Maskf(errors.InvalidConfigError, "--targets must not be empty")
+	}
+	if output != OutputTable && output != OutputJSON {
+		return microerror.Maskf(errors.InvalidConfigError, "--output must be %s or %s", OutputTable, OutputJSON)
+	}
+	setLogger(verbose)
+
+	scheme, err := newScheme()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	operatorConfig := config.Default()
+	ec2Client, assumeRoleClient, _, err := newAWSClients(ctx, config.NewStore(&operatorConfig))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	scanner, err := orphans.NewScanner(k8sClient, ec2Client, assumeRoleClient)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	report, err := scanner.Scan(ctx, targets)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if approved := orphans.ParseClusterNames(deleteClustersFlag); len(approved) > 0 {
+		scanner.DeleteApproved(ctx, &report, approved)
+	}
+
+	if output == OutputJSON {
+		return microerror.Mask(writeJSON(stdout, report))
+	}
+	return microerror.Mask(writeOrphansTable(stdout, report))
+}
+
+func writeOrphansTable(w io.Writer, report orphans.Report) error {
+	deleted := map[orphans.Orphan]bool{}
+	for _, orphan := range report.Deleted {
+		deleted[orphan] = true
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CLUSTER\tREGION\tTYPE\tID\tVPC\tDELETED")
+	for _, orphan := range report.Orphans {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\n", orphan.ClusterName, orphan.Region, orphan.Type, orphan.Id, dash(orphan.VpcId), deleted[orphan])
+	}
+	err := tw.Flush()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	for _, e := range report.Errors {
+		fmt.Fprintf(w, "error: %s\n", e)
+	}
+	if report.Note != "" {
+		fmt.Fprintf(w, "\nNote: %s\n", report.Note)
+	}
+	return nil
+}
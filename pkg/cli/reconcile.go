@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/aws-vpc-operator/controllers"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/dryrun"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// ReconcileResult is the outcome of a single reconciliation.
+type ReconcileResult struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Applied is set when the changes were made, otherwise they are only
+	// planned.
+	Applied bool `json:"applied"`
+
+	// Operations are the planned changes, when they were not applied.
+	Operations []dryrun.Operation `json:"operations,omitempty"`
+
+	// Conditions are the conditions of the AWSCluster after the changes were
+	// applied.
+	Conditions capi.Conditions `json:"conditions,omitempty"`
+
+	// RequeueAfter is when the controller would reconcile the AWSCluster
+	// again, e.g. because resources are still being created.
+	RequeueAfter string `json:"requeueAfter,omitempty"`
+}
+
+func runReconcile(ctx context.Context, args []string, stdout io.Writer) error {
+	var o options
+	var once, apply bool
+	var writeAWSClusterFile string
+	flags := flag.NewFlagSet(ReconcileCommand, flag.ContinueOnError)
+	o.bind(flags)
+	flags.BoolVar(&once, "once", false, "Reconcile the AWSCluster once. Required, continuous reconciliation is done by the operator.")
+	flags.BoolVar(&apply, "apply", false, "Make the changes in AWS. Without it, the changes are only planned like in dry-run mode. The AWSCluster must be paused, so that the operator does not reconcile it at the same time.")
+	flags.StringVar(&writeAWSClusterFile, "write-awscluster", "", "Write the reconciled AWSCluster to this YAML file, e.g. to see the updated spec and status.")
+	err := flags.Parse(args)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	err = o.validate()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !once {
+		return microerror.Maskf(errors.InvalidConfigError, "--once must be set")
+	}
+
+	in, err := o.load()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if in.AWSCluster.Annotations[annotation.AWSVPCMode] != annotation.AWSVPCModePrivate {
+		return microerror.Maskf(errors.InvalidConfigError, "AWSCluster must have annotation %s set to %s", annotation.AWSVPCMode, annotation.AWSVPCModePrivate)
+	}
+	// The operator must not change the same resources at the same time.
+	if _, paused := in.AWSCluster.Annotations[capi.PausedAnnotation]; apply && !paused {
+		return microerror.Maskf(errors.InvalidConfigError, "AWSCluster must have annotation %s set with --apply, so that the operator does not reconcile it at the same time", capi.PausedAnnotation)
+	}
+	in.Config.DryRun = !apply
+
+	store := config.NewStore(in.Config)
+	ec2Client, assumeRoleClient, limits, err := newAWSClients(ctx, store)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	awsCluster, result, err := reconcileOnce(ctx, in, func(k8sClient client.Client, scheme *runtime.Scheme, plans *dryrun.Plans) (*controllers.AWSClusterReconciler, error) {
+		return controllers.NewAWSClusterReconciler(k8sClient, scheme, record.NewFakeRecorder(100), ec2Client, assumeRoleClient, limits, store, plans)
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if writeAWSClusterFile != "" {
+		data, err := yaml.Marshal(awsCluster)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		err = os.WriteFile(writeAWSClusterFile, data, 0o600)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	if o.output == OutputJSON {
+		return microerror.Mask(writeJSON(stdout, result))
+	}
+	return microerror.Mask(writeReconcileTable(stdout, result))
+}
+
+type newReconcilerFunc func(k8sClient client.Client, scheme *runtime.Scheme, plans *dryrun.Plans) (*controllers.AWSClusterReconciler, error)
+
+// reconcileOnce reconciles the AWSCluster with the controller, which reads
+// and writes the AWSCluster and its identity in an in-memory Kubernetes API.
+// It returns the AWSCluster as updated by the controller. The AWSCluster is
+// reconciled even when it is paused for the operator.
+func reconcileOnce(ctx context.Context, in input, newReconciler newReconcilerFunc) (*capa.AWSCluster, ReconcileResult, error) {
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, ReconcileResult{}, microerror.Mask(err)
+	}
+
+	awsCluster := in.AWSCluster.DeepCopy()
+	awsCluster.ResourceVersion = ""
+	pausedValue, paused := awsCluster.Annotations[capi.PausedAnnotation]
+	delete(awsCluster.Annotations, capi.PausedAnnotation)
+	identity := in.Identity.DeepCopy()
+	identity.ResourceVersion = ""
+	// The in-memory API treats all objects as namespaced, and the controller
+	// gets the identity from the namespace of the AWSCluster.
+	identity.Namespace = awsCluster.Namespace
+	// The endpoints phase needs the Cluster of the AWSCluster to exist.
+	cluster := &capi.Cluster{}
+	cluster.Namespace = awsCluster.Namespace
+	cluster.Name = awsCluster.Name
+
+	k8sClient, err := newMemoryClient(scheme, awsCluster, identity, cluster)
+	if err != nil {
+		return nil, ReconcileResult{}, microerror.Mask(err)
+	}
+	plans := dryrun.NewPlans()
+
+	reconciler, err := newReconciler(k8sClient, scheme, plans)
+	if err != nil {
+		return nil, ReconcileResult{}, microerror.Mask(err)
+	}
+
+	key := client.ObjectKeyFromObject(awsCluster)
+	ctrlResult, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		return nil, ReconcileResult{}, microerror.Mask(err)
+	}
+
+	reconciled := &capa.AWSCluster{}
+	err = k8sClient.Get(ctx, key, reconciled)
+	if err != nil {
+		return nil, ReconcileResult{}, microerror.Mask(err)
+	}
+	if paused {
+		if reconciled.Annotations == nil {
+			reconciled.Annotations = map[string]string{}
+		}
+		reconciled.Annotations[capi.PausedAnnotation] = pausedValue
+	}
+
+	result := ReconcileResult{
+		Namespace: key.Namespace,
+		Name:      key.Name,
+		Applied:   !in.Config.DryRun,
+	}
+	if in.Config.DryRun {
+		result.Operations = []dryrun.Operation{}
+		for _, plan := range plans.List() {
+			result.Operations = append(result.Operations, plan.Operations...)
+		}
+	} else {
+		result.Conditions = reconciled.Status.Conditions
+		if ctrlResult.RequeueAfter > 0 {
+			result.RequeueAfter = ctrlResult.RequeueAfter.String()
+		}
+	}
+
+	return reconciled, result, nil
+}
+
+// newScheme returns a scheme with the Kubernetes, Cluster API and CAPA types,
+// like the scheme of the operator.
+func newScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, capi.AddToScheme, capa.AddToScheme} {
+		err := addToScheme(scheme)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+	return scheme, nil
+}
+
+func writeReconcileTable(w io.Writer, result ReconcileResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if !result.Applied {
+		if len(result.Operations) == 0 {
+			fmt.Fprintln(w, "No changes planned")
+			return nil
+		}
+		fmt.Fprintln(tw, "ACTION\tRESOURCE\tDETAILS\tPERMISSION DENIED")
+		for _, operation := range result.Operations {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%t\n", operation.Action, dash(operation.ResourceId), dash(operation.Details), operation.PermissionDenied)
+		}
+		return microerror.Mask(tw.Flush())
+	}
+
+	fmt.Fprintln(tw, "CONDITION\tSTATUS\tREASON\tMESSAGE")
+	for _, condition := range result.Conditions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", condition.Type, condition.Status, dash(condition.Reason), dash(condition.Message))
+	}
+	err := tw.Flush()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if result.RequeueAfter != "" {
+		fmt.Fprintf(w, "\nNot done yet, run again in %s\n", result.RequeueAfter)
+	}
+	return nil
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,378 @@
+// Package config loads the operator configuration file, which holds the
+// per-installation defaults of aws-vpc-operator, e.g. network defaults, name
+// templates and requeue schedules.
+package config
+
+import (
+	"math/bits"
+	"net/netip"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/microerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/naming"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/ratelimit"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/subnets"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpc"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+	"github.com/giantswarm/aws-vpc-operator/pkg/requeue"
+)
+
+const (
+	// APIVersion is the version of the configuration file format. Files
+	// with other versions are rejected.
+	APIVersion = "aws-vpc-operator.giantswarm.io/v1alpha1"
+	Kind       = "OperatorConfig"
+
+	// maxSubnetPrefixLength is the smallest subnet size allowed by AWS.
+	maxSubnetPrefixLength = 28
+)
+
+var awsAccountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// Config is the operator configuration. Fields that are not set in the
+// configuration file keep their values from the command line flags or the
+// defaults, see Load.
+type Config struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Defaults are set in AWSClusters in private VPC mode by the defaulting
+	// webhook.
+	Defaults Defaults `json:"defaults"`
+
+	// Tags that have a special meaning for aws-vpc-operator.
+	Tags Tags `json:"tags"`
+
+	// Naming has the templates of Name tags of AWS resources.
+	Naming Naming `json:"naming"`
+
+	VpcEndpoints VpcEndpoints `json:"vpcEndpoints"`
+
+	Requeue Requeue `json:"requeue"`
+
+	Drift Drift `json:"drift"`
+
+	// FeatureGates enable or disable features, see Feature.
+	FeatureGates map[Feature]bool `json:"featureGates,omitempty"`
+
+	// DryRun records the changes of AWS resources in a plan for each
+	// AWSCluster instead of making them. AWSClusters are not changed, except
+	// for the condition that reports the plan.
+	DryRun bool `json:"dryRun"`
+
+	AWS AWS `json:"aws"`
+
+	requeueSchedule requeue.Schedule
+}
+
+type Defaults struct {
+	// VpcCidr is set as the VPC CIDR block when it is not specified.
+	VpcCidr string `json:"vpcCidr"`
+
+	// AvailabilityZoneCount is the number of private subnets created when no
+	// subnets are specified. The VPC CIDR block is split evenly between them,
+	// and they are spread over the available zones of the region when they
+	// are created.
+	AvailabilityZoneCount int `json:"availabilityZoneCount"`
+
+	// VpcEndpointMode is set as the aws.giantswarm.io/vpc-endpoint-mode
+	// annotation when it is not set. It is not set when empty.
+	VpcEndpointMode string `json:"vpcEndpointMode"`
+
+	// AdditionalTags are added to the AWSCluster AdditionalTags when a
+	// cluster is created. Tags that are already specified are not changed.
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+}
+
+type Tags struct {
+	// EndpointSubnet is the tag that marks subnets in which VPC endpoints are
+	// created, when set to "true".
+	EndpointSubnet string `json:"endpointSubnet"`
+
+	// InternalLoadBalancer is the tag that is set on private subnets, so that
+	// they are used for internal load balancers.
+	InternalLoadBalancer string `json:"internalLoadBalancer"`
+}
+
+// Naming has text/template templates of Name tags, with the fields of
+// naming.Params, e.g. "{{ .ClusterName }}-rt-private-{{ .AvailabilityZone }}".
+// Changing a template renames existing resources.
+type Naming struct {
+	Subnet      naming.Template `json:"subnet"`
+	RouteTable  naming.Template `json:"routeTable"`
+	VpcEndpoint naming.Template `json:"vpcEndpoint"`
+}
+
+type VpcEndpoints struct {
+	// GatewayServices are short names of services for which gateway VPC
+	// endpoints are created, e.g. s3 or dynamodb. VPC endpoints of services
+	// that are removed from the list are neither updated nor deleted.
+	GatewayServices []string `json:"gatewayServices"`
+}
+
+type Requeue struct {
+	// Schedule for retrying reconciliations that are waiting for AWS
+	// resources, in the format of the --requeue-schedule flag.
+	Schedule string `json:"schedule"`
+
+	// Jitter is the max fraction of the requeue time that is randomly added
+	// to it, between 0 and 1.
+	Jitter float64 `json:"jitter"`
+}
+
+// Drift configures the periodic checks of ready AWSClusters for changes of
+// their AWS resources that were not made by aws-vpc-operator, e.g. a route
+// table association that was deleted in the AWS console.
+type Drift struct {
+	// Interval between drift checks of each ready AWSCluster. Drift checks
+	// are disabled when zero.
+	Interval metav1.Duration `json:"interval"`
+
+	// AutoRepair reverts the changes found by drift checks. Otherwise they
+	// are only reported, and reconciliations of a ready AWSCluster change
+	// its AWS resources only after its spec changes.
+	AutoRepair bool `json:"autoRepair"`
+}
+
+type AWS struct {
+	Retry Retry `json:"retry"`
+
+	// RateLimit limits the EC2 API calls of all clusters per AWS account and
+	// region.
+	RateLimit ratelimit.Settings `json:"rateLimit"`
+}
+
+// Retry configures retries of AWS API calls that failed with retryable
+// errors, e.g. throttling.
+type Retry struct {
+	// MaxAttempts is the max number of attempts of each call, including the
+	// first one.
+	MaxAttempts int `json:"maxAttempts"`
+
+	// MaxBackoff is the max delay between attempts.
+	MaxBackoff metav1.Duration `json:"maxBackoff"`
+}
+
+// Default returns the built-in configuration, which is used for fields that
+// are neither set with command line flags nor in the configuration file.
+func Default() Config {
+	return Config{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Defaults: Defaults{
+			VpcCidr:               vpc.DefaultCidr,
+			AvailabilityZoneCount: 3,
+			VpcEndpointMode:       annotation.VPCEndpointModeGiantSwarmManaged,
+		},
+		Tags: Tags{
+			EndpointSubnet:       subnets.DefaultEndpointSubnetTag,
+			InternalLoadBalancer: subnets.DefaultInternalLoadBalancerTag,
+		},
+		Naming: Naming{
+			Subnet:      naming.MustParse(naming.DefaultSubnet),
+			RouteTable:  naming.MustParse(naming.DefaultRouteTable),
+			VpcEndpoint: naming.MustParse(naming.DefaultVpcEndpoint),
+		},
+		VpcEndpoints: VpcEndpoints{
+			GatewayServices: vpcendpoint.DefaultServices,
+		},
+		Requeue: Requeue{
+			Schedule: requeue.DefaultSchedule.String(),
+			Jitter:   requeue.DefaultSchedule.Jitter,
+		},
+		Drift: Drift{
+			Interval: metav1.Duration{Duration: 30 * time.Minute},
+		},
+		AWS: AWS{
+			Retry: Retry{
+				MaxAttempts: 3,
+				MaxBackoff:  metav1.Duration{Duration: 20 * time.Second},
+			},
+			RateLimit: ratelimit.Settings{
+				Default: ratelimit.Bucket{QPS: 10, Burst: 50},
+			},
+		},
+	}
+}
+
+// Load reads the configuration file and sets its fields over the base
+// configuration, which has the defaults and the values of command line flags.
+// When the path is empty, the base configuration is used. The configuration
+// is validated, and unknown fields are rejected.
+func Load(path string, base Config) (*Config, error) {
+	var data []byte
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	config, err := Parse(data, base)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return config, nil
+}
+
+// Parse is like Load, but it parses the configuration file content. When the
+// content is empty, the base configuration is used.
+func Parse(data []byte, base Config) (*Config, error) {
+	config := base.deepCopy()
+	if len(data) > 0 {
+		// the file may only set some of the fields, but it must always have
+		// its own version
+		config.APIVersion = ""
+		config.Kind = ""
+		err := yaml.UnmarshalStrict(data, config)
+		if err != nil {
+			return nil, microerror.Maskf(errors.InvalidConfigError, "%s", err)
+		}
+	}
+
+	err := config.complete()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return config, nil
+}
+
+// complete validates the configuration and parses the fields that are used
+// in parsed form.
+func (c *Config) complete() error {
+	if c.APIVersion != APIVersion {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.APIVersion must be %s", c, APIVersion)
+	}
+	if c.Kind != Kind {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Kind must be %s", c, Kind)
+	}
+
+	vpcCidr, err := netip.ParsePrefix(c.Defaults.VpcCidr)
+	if err != nil || !vpcCidr.Addr().Is4() || vpcCidr.Masked() != vpcCidr {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Defaults.VpcCidr must be a valid IPv4 CIDR block", c)
+	}
+	if c.Defaults.AvailabilityZoneCount < 1 || c.Defaults.AvailabilityZoneCount > 26 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Defaults.AvailabilityZoneCount must be between 1 and 26", c)
+	}
+	if vpcCidr.Bits()+bits.Len(uint(c.Defaults.AvailabilityZoneCount-1)) > maxSubnetPrefixLength {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Defaults.VpcCidr %s is too small for %d subnets", c, c.Defaults.VpcCidr, c.Defaults.AvailabilityZoneCount)
+	}
+	switch c.Defaults.VpcEndpointMode {
+	case "", annotation.VPCEndpointModeGiantSwarmManaged, annotation.VPCEndpointModeUserManaged:
+	default:
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Defaults.VpcEndpointMode must be %s or %s", c, annotation.VPCEndpointModeGiantSwarmManaged, annotation.VPCEndpointModeUserManaged)
+	}
+	err = tags.Validate(c.Defaults.AdditionalTags)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if c.Tags.EndpointSubnet == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Tags.EndpointSubnet must not be empty", c)
+	}
+	if c.Tags.InternalLoadBalancer == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Tags.InternalLoadBalancer must not be empty", c)
+	}
+
+	if c.Naming.Subnet.String() == "" || c.Naming.RouteTable.String() == "" || c.Naming.VpcEndpoint.String() == "" {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Naming templates must not be empty", c)
+	}
+
+	if len(c.VpcEndpoints.GatewayServices) == 0 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.VpcEndpoints.GatewayServices must not be empty", c)
+	}
+
+	c.requeueSchedule, err = requeue.Parse(c.Requeue.Schedule, c.Requeue.Jitter)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if c.Drift.Interval.Duration < 0 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Drift.Interval must not be negative", c)
+	}
+
+	for feature := range c.FeatureGates {
+		if _, ok := defaultFeatureGates[feature]; !ok {
+			return microerror.Maskf(errors.InvalidConfigError, "unknown feature gate %s, known feature gates are %v", feature, knownFeatures())
+		}
+	}
+
+	if c.AWS.Retry.MaxAttempts < 1 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.AWS.Retry.MaxAttempts must be at least 1", c)
+	}
+	if c.AWS.Retry.MaxBackoff.Duration <= 0 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.AWS.Retry.MaxBackoff must be positive", c)
+	}
+	err = c.validateBucket("Default", c.AWS.RateLimit.Default)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	for account, bucket := range c.AWS.RateLimit.Accounts {
+		if !awsAccountIDPattern.MatchString(account) {
+			return microerror.Maskf(errors.InvalidConfigError, "%T.AWS.RateLimit.Accounts key %q must be an AWS account ID", c, account)
+		}
+		err = c.validateBucket("Accounts["+account+"]", bucket)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// validateBucket validates the rate limit bucket of the AWS.RateLimit field.
+func (c *Config) validateBucket(field string, bucket ratelimit.Bucket) error {
+	if bucket.QPS < 0 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.AWS.RateLimit.%s.QPS must not be negative", c, field)
+	}
+	if bucket.QPS > 0 && bucket.Burst < 1 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.AWS.RateLimit.%s.Burst must be at least 1 when QPS is set", c, field)
+	}
+	return nil
+}
+
+// RequeueSchedule returns the parsed requeue schedule.
+func (c *Config) RequeueSchedule() requeue.Schedule {
+	return c.requeueSchedule
+}
+
+func (c Config) deepCopy() *Config {
+	copied := c
+	copied.Defaults.AdditionalTags = copyMap(c.Defaults.AdditionalTags)
+	copied.VpcEndpoints.GatewayServices = append([]string(nil), c.VpcEndpoints.GatewayServices...)
+	copied.FeatureGates = copyMap(c.FeatureGates)
+	copied.AWS.RateLimit.Accounts = copyMap(c.AWS.RateLimit.Accounts)
+	return &copied
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[K]V, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
+func knownFeatures() []Feature {
+	var features []Feature
+	for feature := range defaultFeatureGates {
+		features = append(features, feature)
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
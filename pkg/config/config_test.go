@@ -0,0 +1,206 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/naming"
+)
+
+func Test_Parse(t *testing.T) {
+	base := Default()
+	base.Defaults.VpcCidr = "10.1.0.0/16"
+	base.Defaults.AdditionalTags = map[string]string{"team": "phoenix"}
+
+	testCases := []struct {
+		name        string
+		data        string
+		check       func(t *testing.T, config *Config)
+		expectError bool
+	}{
+		{
+			name: "empty file uses base",
+			data: "",
+			check: func(t *testing.T, config *Config) {
+				if config.Defaults.VpcCidr != "10.1.0.0/16" {
+					t.Errorf("expected VPC CIDR from base, got %s", config.Defaults.VpcCidr)
+				}
+			},
+		},
+		{
+			name: "partial file overrides base",
+			data: `apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1
+kind: OperatorConfig
+defaults:
+  availabilityZoneCount: 2
+naming:
+  routeTable: "{{ .ClusterName }}-{{ .AvailabilityZone }}"
+vpcEndpoints:
+  gatewayServices: [s3, dynamodb]
+featureGates:
+  DeleteLeftoverResources: false
+drift:
+  interval: 1h
+  autoRepair: true
+aws:
+  retry:
+    maxBackoff: 5s
+  rateLimit:
+    accounts:
+      "123456789012":
+        qps: 50
+        burst: 100
+`,
+			check: func(t *testing.T, config *Config) {
+				if config.Defaults.VpcCidr != "10.1.0.0/16" {
+					t.Errorf("expected VPC CIDR from base, got %s", config.Defaults.VpcCidr)
+				}
+				if config.Defaults.AvailabilityZoneCount != 2 {
+					t.Errorf("expected 2 availability zones, got %d", config.Defaults.AvailabilityZoneCount)
+				}
+				if config.Defaults.AdditionalTags["team"] != "phoenix" {
+					t.Errorf("expected additional tags from base, got %v", config.Defaults.AdditionalTags)
+				}
+				name := config.Naming.RouteTable.Name(naming.Params{ClusterName: "test", AvailabilityZone: "eu-west-1a"})
+				if name != "test-eu-west-1a" {
+					t.Errorf("expected route table name test-eu-west-1a, got %s", name)
+				}
+				if len(config.VpcEndpoints.GatewayServices) != 2 {
+					t.Errorf("expected 2 gateway services, got %v", config.VpcEndpoints.GatewayServices)
+				}
+				if config.Enabled(DeleteLeftoverResources) {
+					t.Errorf("expected feature %s to be disabled", DeleteLeftoverResources)
+				}
+				if config.Drift.Interval.Duration != time.Hour || !config.Drift.AutoRepair {
+					t.Errorf("unexpected drift configuration %v", config.Drift)
+				}
+				if config.AWS.Retry.MaxAttempts != 3 || config.AWS.Retry.MaxBackoff.Duration != 5*time.Second {
+					t.Errorf("unexpected retry configuration %v", config.AWS.Retry)
+				}
+				if bucket := config.AWS.RateLimit.Bucket("123456789012"); bucket.QPS != 50 || bucket.Burst != 100 {
+					t.Errorf("unexpected rate limit of account 123456789012 %v", bucket)
+				}
+				if bucket := config.AWS.RateLimit.Bucket("210987654321"); bucket.QPS != 10 || bucket.Burst != 50 {
+					t.Errorf("expected default rate limit for other accounts, got %v", bucket)
+				}
+				if len(config.RequeueSchedule().Steps) == 0 {
+					t.Errorf("expected requeue schedule to be parsed")
+				}
+			},
+		},
+		{
+			name:        "missing apiVersion",
+			data:        "kind: OperatorConfig\n",
+			expectError: true,
+		},
+		{
+			name:        "unsupported apiVersion",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v2\nkind: OperatorConfig\n",
+			expectError: true,
+		},
+		{
+			name:        "unknown field",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\ndefaults:\n  vpcCidrBlock: 10.0.0.0/16\n",
+			expectError: true,
+		},
+		{
+			name:        "unknown feature gate",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\nfeatureGates:\n  Teleport: true\n",
+			expectError: true,
+		},
+		{
+			name:        "invalid name template",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\nnaming:\n  subnet: \"{{ .Zone }}\"\n",
+			expectError: true,
+		},
+		{
+			name:        "VPC too small for subnets",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\ndefaults:\n  vpcCidr: 10.0.0.0/27\n",
+			expectError: true,
+		},
+		{
+			name:        "rate limit without burst",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\naws:\n  rateLimit:\n    default:\n      qps: 5\n      burst: 0\n",
+			expectError: true,
+		},
+		{
+			name:        "rate limit of invalid account",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\naws:\n  rateLimit:\n    accounts:\n      giantswarm:\n        qps: 5\n        burst: 5\n",
+			expectError: true,
+		},
+		{
+			name:        "negative drift interval",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\ndrift:\n  interval: -5m\n",
+			expectError: true,
+		},
+		{
+			name:        "invalid requeue schedule",
+			data:        "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\nrequeue:\n  schedule: 5m:5m\n",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := Parse([]byte(tc.data), base)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got %v", config)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.check(t, config)
+		})
+	}
+
+	if base.Defaults.AdditionalTags["team"] != "phoenix" || len(base.VpcEndpoints.GatewayServices) != 1 {
+		t.Errorf("expected base configuration not to be changed")
+	}
+}
+
+func Test_Watcher_reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	header := "apiVersion: aws-vpc-operator.giantswarm.io/v1alpha1\nkind: OperatorConfig\n"
+
+	write(header + "defaults:\n  availabilityZoneCount: 1\n")
+	config, err := Load(path, Default())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store := NewStore(config)
+	watcher, err := NewWatcher(path, Default(), store, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reloads := 0
+	watcher.OnReload = func(*Config) { reloads++ }
+
+	write(header + "defaults:\n  availabilityZoneCount: 2\n")
+	watcher.reload(context.Background())
+	if count := store.Get().Defaults.AvailabilityZoneCount; count != 2 {
+		t.Errorf("expected reloaded availability zone count 2, got %d", count)
+	}
+
+	write(header + "defaults:\n  availabilityZoneCount: 0\n")
+	watcher.reload(context.Background())
+	if count := store.Get().Defaults.AvailabilityZoneCount; count != 2 {
+		t.Errorf("expected previous configuration to be kept after invalid change, got availability zone count %d", count)
+	}
+
+	watcher.reload(context.Background())
+	if reloads != 1 {
+		t.Errorf("expected 1 reload, got %d", reloads)
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+// Feature is the name of a feature gate.
+type Feature string
+
+const (
+	// DeleteLeftoverResources allows deletion of leftover network interfaces
+	// and security groups that are owned by the cluster and block deletion of
+	// subnets or the VPC, when the AWSCluster has the
+	// aws-vpc-operator.giantswarm.io/delete-leftover-resources annotation.
+	DeleteLeftoverResources Feature = "DeleteLeftoverResources"
+
+	// PermissionsPreflight checks the IAM permissions of the role of each
+	// AWSCluster with EC2 DryRun calls before any resources are reconciled,
+	// and reports missing actions in the PermissionsReady condition.
+	PermissionsPreflight Feature = "PermissionsPreflight"
+
+	// QuotaPreflight compares the current usage of VPCs, gateway VPC
+	// endpoints and Elastic IPs with their service quotas before they are
+	// created, and reports insufficient headroom in the QuotasReady
+	// condition.
+	QuotaPreflight Feature = "QuotaPreflight"
+)
+
+// defaultFeatureGates are the known feature gates and whether they are
+// enabled by default.
+var defaultFeatureGates = map[Feature]bool{
+	DeleteLeftoverResources: true,
+	PermissionsPreflight:    true,
+	QuotaPreflight:          true,
+}
+
+// Enabled checks if the feature is enabled.
+func (c *Config) Enabled(feature Feature) bool {
+	if enabled, ok := c.FeatureGates[feature]; ok {
+		return enabled
+	}
+	return defaultFeatureGates[feature]
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// Retryer is an AWS SDK retryer that uses the current AWS retry settings
+// from the store, so that they can be changed without a restart. It is set
+// as aws.Config.Retryer.
+type Retryer struct {
+	store *Store
+
+	mutex   sync.Mutex
+	retry   Retry
+	retryer aws.RetryerV2
+}
+
+var _ aws.RetryerV2 = &Retryer{}
+
+func NewRetryer(store *Store) *Retryer {
+	return &Retryer{store: store}
+}
+
+// current returns the standard retryer for the current settings. It is
+// created again when the settings change, which also resets its retry
+// token bucket.
+func (r *Retryer) current() aws.RetryerV2 {
+	settings := r.store.Get().AWS.Retry
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.retryer == nil || settings != r.retry {
+		r.retry = settings
+		r.retryer = retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = settings.MaxAttempts
+			o.MaxBackoff = settings.MaxBackoff.Duration
+		})
+	}
+	return r.retryer
+}
+
+func (r *Retryer) IsErrorRetryable(err error) bool {
+	return r.current().IsErrorRetryable(err)
+}
+
+func (r *Retryer) MaxAttempts() int {
+	return r.current().MaxAttempts()
+}
+
+func (r *Retryer) RetryDelay(attempt int, err error) (time.Duration, error) {
+	return r.current().RetryDelay(attempt, err)
+}
+
+func (r *Retryer) GetRetryToken(ctx context.Context, err error) (func(error) error, error) {
+	return r.current().GetRetryToken(ctx, err)
+}
+
+func (r *Retryer) GetInitialToken() func(error) error {
+	return r.current().GetInitialToken()
+}
+
+func (r *Retryer) GetAttemptToken(ctx context.Context) (func(error) error, error) {
+	return r.current().GetAttemptToken(ctx)
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"sync/atomic"
+)
+
+// Store holds the current configuration, which is replaced when the
+// configuration file changes. Components read the configuration from the
+// store whenever they use it, e.g. at the start of each reconciliation, so
+// that changes take effect without a restart.
+type Store struct {
+	config atomic.Pointer[Config]
+}
+
+// NewStore creates a store with the initial configuration, which must have
+// been returned by Load.
+func NewStore(config *Config) *Store {
+	s := &Store{}
+	s.config.Store(config)
+	return s
+}
+
+// Get returns the current configuration. It must not be modified.
+func (s *Store) Get() *Config {
+	return s.config.Load()
+}
+
+func (s *Store) set(config *Config) {
+	s.config.Store(config)
+}
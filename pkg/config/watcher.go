@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// DefaultWatchInterval is how often the configuration file is checked for
+// changes. Files mounted from a ConfigMap are updated by kubelet with a delay
+// of up to a minute anyway.
+const DefaultWatchInterval = 10 * time.Second
+
+// Watcher reloads the configuration file when it changes, and replaces the
+// configuration in the store. Invalid configuration is logged and ignored, so
+// the operator keeps running with the last valid configuration.
+type Watcher struct {
+	path     string
+	base     Config
+	store    *Store
+	interval time.Duration
+
+	// OnReload is called after a new configuration has been stored.
+	OnReload func(config *Config)
+
+	data []byte
+}
+
+// NewWatcher creates a watcher for the configuration file. The base
+// configuration is the same one that was passed to Load.
+func NewWatcher(path string, base Config, store *Store, interval time.Duration) (*Watcher, error) {
+	if path == "" {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "path must not be empty")
+	}
+	if store == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "store must not be empty")
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return &Watcher{
+		path:     path,
+		base:     base,
+		store:    store,
+		interval: interval,
+		data:     data,
+	}, nil
+}
+
+// Start checks the configuration file for changes until the context is done.
+// It implements manager.Runnable.
+func (w *Watcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.reload(ctx)
+		}
+	}
+}
+
+func (w *Watcher) reload(ctx context.Context) {
+	logger := log.FromContext(ctx).WithValues("path", w.path)
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		logger.Error(err, "Failed to read configuration file")
+		return
+	}
+	if bytes.Equal(data, w.data) {
+		return
+	}
+	w.data = data
+
+	config, err := Parse(data, w.base)
+	if err != nil {
+		logger.Error(err, "Invalid configuration file, keeping the previous configuration")
+		return
+	}
+
+	w.store.set(config)
+	logger.Info("Reloaded configuration file")
+	if w.OnReload != nil {
+		w.OnReload(config)
+	}
+}
+
+// NeedLeaderElection returns false, as every replica, including webhook
+// servers that are not leaders, needs the current configuration.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
@@ -30,6 +30,23 @@ func isAWSSubnetNotFound(err error) bool {
 	return errors.As(err, &apiErr) && apiErr.ErrorCode() == subnetNotFoundAWSErrorCode
 }
 
+// isAWSRouteTableNotFound asserts that the specified AWS SDK error means that
+// the route table is not found.
+func isAWSRouteTableNotFound(err error) bool {
+	const routeTableNotFoundAWSErrorCode = "InvalidRouteTableID.NotFound"
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == routeTableNotFoundAWSErrorCode
+}
+
+// IsRouteTableAssociationNotFound asserts that the specified AWS SDK error
+// means that the route table association is not found.
+func IsRouteTableAssociationNotFound(err error) bool {
+	const associationNotFoundAWSErrorCode = "InvalidAssociationID.NotFound"
+	var apiErr smithy.APIError
+	return (errors.As(err, &apiErr) && apiErr.ErrorCode() == associationNotFoundAWSErrorCode) ||
+		IsAWSHTTPStatusNotFound(err)
+}
+
 var VpcNotFoundError = &microerror.Error{
 	Kind: "VpcNotFoundError",
 }
@@ -89,9 +106,12 @@ var RouteTableNotFoundError = &microerror.Error{
 	Kind: "RouteTableNotFoundError",
 }
 
-// IsRouteTableNotFound asserts RouteTableNotFoundError.
+// IsRouteTableNotFound asserts RouteTableNotFoundError, AWS SDK
+// InvalidRouteTableID.NotFound error code, or AWS SDK not found error.
 func IsRouteTableNotFound(err error) bool {
-	return microerror.Cause(err) == RouteTableNotFoundError
+	return microerror.Cause(err) == RouteTableNotFoundError ||
+		isAWSRouteTableNotFound(err) ||
+		IsAWSHTTPStatusNotFound(err)
 }
 
 var UnknownVpcAttributeError = &microerror.Error{
@@ -129,3 +149,56 @@ var ResourceAlreadyDeletedError = &microerror.Error{
 func IsResourceAlreadyDeleted(err error) bool {
 	return microerror.Cause(err) == ResourceAlreadyDeletedError
 }
+
+var ResourceNotOwnedError = &microerror.Error{
+	Kind: "ResourceNotOwnedError",
+}
+
+// IsResourceNotOwned asserts ResourceNotOwnedError, which is returned when a
+// resource has not been created by aws-vpc-operator for the cluster, so it
+// must not be changed or deleted.
+func IsResourceNotOwned(err error) bool {
+	return microerror.Cause(err) == ResourceNotOwnedError
+}
+
+var InvalidTagsError = &microerror.Error{
+	Kind: "InvalidTagsError",
+}
+
+// IsInvalidTags asserts InvalidTagsError, which is returned when tags would
+// exceed AWS tag limits.
+func IsInvalidTags(err error) bool {
+	return microerror.Cause(err) == InvalidTagsError
+}
+
+// IsDryRunOperation asserts the AWS SDK DryRunOperation error code, which is
+// returned by EC2 API calls made with DryRun set when the call would have
+// succeeded.
+func IsDryRunOperation(err error) bool {
+	const dryRunOperationAWSErrorCode = "DryRunOperation"
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == dryRunOperationAWSErrorCode
+}
+
+// IsIdempotentParameterMismatch asserts the AWS SDK
+// IdempotentParameterMismatch error code, which is returned by EC2 create
+// calls when the client token was already used with other parameters.
+func IsIdempotentParameterMismatch(err error) bool {
+	const idempotentParameterMismatchAWSErrorCode = "IdempotentParameterMismatch"
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == idempotentParameterMismatchAWSErrorCode
+}
+
+// IsDependencyViolation asserts AWS SDK DependencyViolation error.
+func IsDependencyViolation(err error) bool {
+	const dependencyViolationAWSErrorCode = "DependencyViolation"
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == dependencyViolationAWSErrorCode
+}
+
+// IsAWSAPIError asserts that the error was returned by the AWS API, as
+// opposed to e.g. network errors or errors of this operator.
+func IsAWSAPIError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr)
+}
@@ -0,0 +1,176 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/giantswarm/microerror"
+)
+
+// Class is a category of errors that are handled in the same way, e.g. all
+// errors caused by API rate limiting are in ClassThrottled.
+type Class string
+
+const (
+	// ClassUnknown contains all errors that are not recognized.
+	ClassUnknown Class = "Unknown"
+
+	// ClassTransient contains errors that are expected to go away on their
+	// own, e.g. AWS internal errors.
+	ClassTransient Class = "Transient"
+
+	// ClassThrottled contains errors caused by AWS API rate limiting.
+	ClassThrottled Class = "Throttled"
+
+	// ClassQuotaExceeded contains errors caused by reaching an AWS service
+	// quota, e.g. the max number of VPCs per region.
+	ClassQuotaExceeded Class = "QuotaExceeded"
+
+	// ClassPermission contains errors caused by missing IAM permissions or
+	// invalid credentials.
+	ClassPermission Class = "Permission"
+
+	// ClassNotFound contains errors caused by AWS resources that do not exist.
+	ClassNotFound Class = "NotFound"
+
+	// ClassDependency contains errors caused by AWS resources that cannot be
+	// changed or deleted because other resources depend on them.
+	ClassDependency Class = "Dependency"
+
+	// ClassConflict contains errors caused by requests that conflict with
+	// earlier requests, e.g. a client token that was used with other
+	// parameters.
+	ClassConflict Class = "Conflict"
+)
+
+// awsErrorCodeClasses maps AWS API error codes to error classes.
+//
+// See https://docs.aws.amazon.com/AWSEC2/latest/APIReference/errors-overview.html
+var awsErrorCodeClasses = map[string]Class{
+	// transient
+	"InternalError":      ClassTransient,
+	"InternalFailure":    ClassTransient,
+	"ServiceUnavailable": ClassTransient,
+	"Unavailable":        ClassTransient,
+	"RequestTimeout":     ClassTransient,
+	"RequestExpired":     ClassTransient,
+
+	// throttled
+	"RequestLimitExceeded":                   ClassThrottled,
+	"Throttling":                             ClassThrottled,
+	"ThrottlingException":                    ClassThrottled,
+	"RequestThrottled":                       ClassThrottled,
+	"RequestThrottledException":              ClassThrottled,
+	"TooManyRequestsException":               ClassThrottled,
+	"EC2ThrottledException":                  ClassThrottled,
+	"PriorRequestNotComplete":                ClassThrottled,
+	"BandwidthLimitExceeded":                 ClassThrottled,
+	"ProvisionedThroughputExceededException": ClassThrottled,
+
+	// quota exceeded
+	"VpcLimitExceeded":              ClassQuotaExceeded,
+	"SubnetLimitExceeded":           ClassQuotaExceeded,
+	"RouteTableLimitExceeded":       ClassQuotaExceeded,
+	"RouteLimitExceeded":            ClassQuotaExceeded,
+	"VpcEndpointLimitExceeded":      ClassQuotaExceeded,
+	"AddressLimitExceeded":          ClassQuotaExceeded,
+	"NetworkInterfaceLimitExceeded": ClassQuotaExceeded,
+	"SecurityGroupLimitExceeded":    ClassQuotaExceeded,
+	"TagLimitExceeded":              ClassQuotaExceeded,
+	"ResourceLimitExceeded":         ClassQuotaExceeded,
+	"ServiceQuotaExceededException": ClassQuotaExceeded,
+
+	// permission
+	"UnauthorizedOperation":       ClassPermission,
+	"AuthFailure":                 ClassPermission,
+	"AccessDenied":                ClassPermission,
+	"AccessDeniedException":       ClassPermission,
+	"InvalidClientTokenId":        ClassPermission,
+	"SignatureDoesNotMatch":       ClassPermission,
+	"ExpiredToken":                ClassPermission,
+	"OptInRequired":               ClassPermission,
+	"Blocked":                     ClassPermission,
+	"UnrecognizedClientException": ClassPermission,
+
+	// not found
+	"InvalidVpcID.NotFound":              ClassNotFound,
+	"InvalidSubnetID.NotFound":           ClassNotFound,
+	"InvalidRouteTableID.NotFound":       ClassNotFound,
+	"InvalidAssociationID.NotFound":      ClassNotFound,
+	"InvalidVpcEndpointId.NotFound":      ClassNotFound,
+	"InvalidNetworkInterfaceID.NotFound": ClassNotFound,
+	"InvalidGroup.NotFound":              ClassNotFound,
+
+	// dependency
+	"DependencyViolation":           ClassDependency,
+	"IncorrectState":                ClassDependency,
+	"InvalidVpcState":               ClassDependency,
+	"ResourceInUse":                 ClassDependency,
+	"InvalidNetworkInterface.InUse": ClassDependency,
+
+	// conflict
+	"IdempotentParameterMismatch": ClassConflict,
+
+	// unknown, returned with a 5xx status code, but they do not go away on
+	// their own, so they must not look transient
+	"InsufficientCapacity": ClassUnknown,
+}
+
+// Classify returns the class of the specified error. AWS API errors are
+// classified by their error code, AWS HTTP response errors without a known
+// error code by the HTTP status code, and errors defined in this package by
+// their kind.
+func Classify(err error) Class {
+	if err == nil {
+		return ClassUnknown
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if class, ok := awsErrorCodeClasses[apiErr.ErrorCode()]; ok {
+			return class
+		}
+	}
+
+	var httpResponseError *awshttp.ResponseError
+	if errors.As(err, &httpResponseError) {
+		switch statusCode := httpResponseError.HTTPStatusCode(); {
+		case statusCode == http.StatusNotFound:
+			return ClassNotFound
+		case statusCode == http.StatusTooManyRequests:
+			return ClassThrottled
+		case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+			return ClassPermission
+		case statusCode >= http.StatusInternalServerError:
+			return ClassTransient
+		}
+	}
+
+	switch microerror.Cause(err) {
+	case VpcNotFoundError, RouteTableNotFoundError, VpcEndpointNotFoundError:
+		return ClassNotFound
+	case ResourceDeletionInProgressError:
+		return ClassDependency
+	}
+
+	return ClassUnknown
+}
+
+// IsThrottled asserts that the error is caused by AWS API rate limiting.
+func IsThrottled(err error) bool {
+	return Classify(err) == ClassThrottled
+}
+
+// IsQuotaExceeded asserts that the error is caused by reaching an AWS service
+// quota.
+func IsQuotaExceeded(err error) bool {
+	return Classify(err) == ClassQuotaExceeded
+}
+
+// IsPermissionDenied asserts that the error is caused by missing IAM
+// permissions or invalid credentials.
+func IsPermissionDenied(err error) bool {
+	return Classify(err) == ClassPermission
+}
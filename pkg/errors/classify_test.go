@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/giantswarm/microerror"
+)
+
+func Test_Classify(t *testing.T) {
+	awsError := func(code string) error {
+		return &smithy.GenericAPIError{Code: code, Message: "test"}
+	}
+	httpError := func(statusCode int) error {
+		return &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: statusCode}},
+				Err:      fmt.Errorf("test"),
+			},
+		}
+	}
+	awsHTTPError := func(code string, statusCode int) error {
+		return &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: statusCode}},
+				Err:      awsError(code),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		err           error
+		expectedClass Class
+	}{
+		{"nil error", nil, ClassUnknown},
+		{"unknown error", fmt.Errorf("test"), ClassUnknown},
+		{"unknown AWS error code", awsError("SomethingWentWrong"), ClassUnknown},
+		{"InternalError", awsError("InternalError"), ClassTransient},
+		{"RequestLimitExceeded", awsError("RequestLimitExceeded"), ClassThrottled},
+		{"masked RequestLimitExceeded", microerror.Mask(awsError("RequestLimitExceeded")), ClassThrottled},
+		{"VpcLimitExceeded", awsError("VpcLimitExceeded"), ClassQuotaExceeded},
+		{"UnauthorizedOperation", awsError("UnauthorizedOperation"), ClassPermission},
+		{"InvalidRouteTableID.NotFound", awsError("InvalidRouteTableID.NotFound"), ClassNotFound},
+		{"DependencyViolation", awsError("DependencyViolation"), ClassDependency},
+		{"IncorrectState", awsError("IncorrectState"), ClassDependency},
+		{"InsufficientCapacity with HTTP 500", awsHTTPError("InsufficientCapacity", http.StatusInternalServerError), ClassUnknown},
+		{"IdempotentParameterMismatch", awsError("IdempotentParameterMismatch"), ClassConflict},
+		{"HTTP 404", httpError(http.StatusNotFound), ClassNotFound},
+		{"HTTP 429", httpError(http.StatusTooManyRequests), ClassThrottled},
+		{"HTTP 403", httpError(http.StatusForbidden), ClassPermission},
+		{"HTTP 503", httpError(http.StatusServiceUnavailable), ClassTransient},
+		{"VpcNotFoundError", microerror.Maskf(VpcNotFoundError, "test"), ClassNotFound},
+		{"ResourceDeletionInProgressError", microerror.Maskf(ResourceDeletionInProgressError, "test"), ClassDependency},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			class := Classify(tc.err)
+			if class != tc.expectedClass {
+				t.Errorf("expected class %s, got %s", tc.expectedClass, class)
+			}
+		})
+	}
+}
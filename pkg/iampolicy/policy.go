@@ -0,0 +1,202 @@
+// Package iampolicy generates the least-privilege IAM policy that the role
+// assumed for AWSClusters needs, from the EC2 actions that the client packages
+// in pkg/aws use with the operator configuration.
+package iampolicy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	// Version is the version of the IAM policy language.
+	Version = "2012-10-17"
+
+	allResources = "*"
+)
+
+// createdResourceTypes are the types of the resources that the actions with
+// the TaggedOnCreate condition create.
+var createdResourceTypes = map[string]string{
+	"ec2:CreateVpc":         "vpc",
+	"ec2:CreateSubnet":      "subnet",
+	"ec2:CreateRouteTable":  "route-table",
+	"ec2:CreateVpcEndpoint": "vpc-endpoint",
+}
+
+// parentResourceTypes are the types of the existing resources that actions
+// with the TaggedOnCreate condition use, e.g. the VPC of a created subnet.
+// IAM authorizes the action for each of them, and they do not have the tags
+// of the request, so they are allowed in a statement without the condition.
+var parentResourceTypes = map[string][]string{
+	"ec2:CreateSubnet":      {"vpc"},
+	"ec2:CreateRouteTable":  {"vpc"},
+	"ec2:CreateVpcEndpoint": {"vpc", "subnet", "route-table", "security-group"},
+}
+
+// Policy is an IAM policy document.
+type Policy struct {
+	Version   string      `json:"Version"`
+	Statement []Statement `json:"Statement"`
+}
+
+// Statement is a statement of an IAM policy document.
+type Statement struct {
+	Sid       string                            `json:"Sid"`
+	Effect    string                            `json:"Effect"`
+	Action    []string                          `json:"Action"`
+	Resource  []string                          `json:"Resource"`
+	Condition map[string]map[string]interface{} `json:"Condition,omitempty"`
+}
+
+type Options struct {
+	// Config is the operator configuration, whose feature gates decide which
+	// actions are needed.
+	Config *config.Config
+
+	// UnmanagedNetworksOnly is set when all AWSClusters that use the role
+	// have unmanaged networks, so that only VPC endpoints are reconciled.
+	UnmanagedNetworksOnly bool
+}
+
+// Generate returns the policy with the actions of all client packages that
+// are used with the options. Actions are grouped in statements by their
+// condition.
+func Generate(options Options) (Policy, error) {
+	if options.Config == nil {
+		return Policy{}, microerror.Maskf(errors.InvalidConfigError, "%T.Config must not be empty", options)
+	}
+
+	actionsByCondition := map[Condition]map[string]bool{}
+	for _, permissions := range registry {
+		for _, permission := range permissions {
+			if permission.ManagedNetworkOnly && options.UnmanagedNetworksOnly {
+				continue
+			}
+			if permission.Feature != "" && !options.Config.Enabled(permission.Feature) {
+				continue
+			}
+			if actionsByCondition[permission.Condition] == nil {
+				actionsByCondition[permission.Condition] = map[string]bool{}
+			}
+			actionsByCondition[permission.Condition][permission.Action] = true
+		}
+	}
+
+	policy := Policy{
+		Version: Version,
+	}
+	if actions := sorted(actionsByCondition[NoCondition]); len(actions) > 0 {
+		policy.Statement = append(policy.Statement, Statement{
+			Sid:      "AllResources",
+			Effect:   "Allow",
+			Action:   actions,
+			Resource: []string{allResources},
+		})
+	}
+	if actions := sorted(actionsByCondition[TaggedOnCreate]); len(actions) > 0 {
+		created := map[string]bool{}
+		for _, action := range actions {
+			resourceType, ok := createdResourceTypes[action]
+			if !ok {
+				return Policy{}, microerror.Maskf(errors.InvalidConfigError, "created resource type of %s is unknown", action)
+			}
+			created[resourceArn(resourceType)] = true
+		}
+		createdResources := sorted(created)
+
+		// the condition is only checked for the created resources, see
+		// parentResourceTypes
+		policy.Statement = append(policy.Statement, Statement{
+			Sid:      "CreateTaggedResources",
+			Effect:   "Allow",
+			Action:   actions,
+			Resource: createdResources,
+			Condition: map[string]map[string]interface{}{
+				"Null": {"aws:RequestTag/" + tags.NameAWSRole: "false"},
+			},
+		})
+		// each action has its own statement, so that the resources that one
+		// action creates are not allowed as parent resources of another
+		for _, action := range actions {
+			var parentResources []string
+			for _, resourceType := range parentResourceTypes[action] {
+				parentResources = append(parentResources, resourceArn(resourceType))
+			}
+			if len(parentResources) == 0 {
+				continue
+			}
+			policy.Statement = append(policy.Statement, Statement{
+				Sid:      strings.TrimPrefix(action, "ec2:") + "ParentResources",
+				Effect:   "Allow",
+				Action:   []string{action},
+				Resource: parentResources,
+			})
+		}
+		// tagging on creation needs ec2:CreateTags for the create action
+		policy.Statement = append(policy.Statement, Statement{
+			Sid:      "TagOnCreate",
+			Effect:   "Allow",
+			Action:   []string{"ec2:CreateTags"},
+			Resource: createdResources,
+			Condition: map[string]map[string]interface{}{
+				"StringEquals": {"ec2:CreateAction": trimService(actions)},
+			},
+		})
+	}
+	if actions := sorted(actionsByCondition[OwnedResource]); len(actions) > 0 {
+		policy.Statement = append(policy.Statement, Statement{
+			Sid:      "OwnedResources",
+			Effect:   "Allow",
+			Action:   actions,
+			Resource: []string{allResources},
+			Condition: map[string]map[string]interface{}{
+				"Null": {"aws:ResourceTag/" + tags.NameAWSRole: "false"},
+			},
+		})
+	}
+
+	return policy, nil
+}
+
+// Actions returns the sorted actions of the policy.
+func (p Policy) Actions() []string {
+	actions := map[string]bool{}
+	for _, statement := range p.Statement {
+		for _, action := range statement.Action {
+			actions[action] = true
+		}
+	}
+	return sorted(actions)
+}
+
+func sorted(set map[string]bool) []string {
+	var result []string
+	for s := range set {
+		result = append(result, s)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// resourceArn returns the ARN pattern of the EC2 resources of the type in all
+// regions and accounts, e.g. arn:aws:ec2:*:*:vpc/* for VPCs.
+func resourceArn(resourceType string) string {
+	return "arn:aws:ec2:*:*:" + resourceType + "/*"
+}
+
+// trimService returns the actions without the ec2: prefix, as used in the
+// ec2:CreateAction condition key.
+func trimService(actions []string) []string {
+	var result []string
+	for _, action := range actions {
+		result = append(result, strings.TrimPrefix(action, "ec2:"))
+	}
+	return result
+}
@@ -0,0 +1,252 @@
+package iampolicy
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+// usedActions parses the client packages in pkg/aws, and returns the EC2 and
+// Service Quotas actions of each package, i.e. the methods called on an
+// ec2Client or serviceQuotasClient field or variable, and the EC2 paginators
+// that are created.
+func usedActions(t *testing.T) map[string]map[string]bool {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("..", "aws", "*", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	used := map[string]map[string]bool{}
+	fileSet := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fileSet, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packageName := filepath.Base(filepath.Dir(file))
+
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			var action string
+			switch x := selector.X.(type) {
+			case *ast.SelectorExpr:
+				// c.ec2Client.CreateVpc(...)
+				action = clientAction(x.Sel.Name, selector.Sel.Name)
+			case *ast.Ident:
+				// ec2Client.CreateVpc(...) and ec2.NewDescribeVpcsPaginator(...)
+				action = clientAction(x.Name, selector.Sel.Name)
+				if x.Name == "ec2" && strings.HasPrefix(selector.Sel.Name, "New") && strings.HasSuffix(selector.Sel.Name, "Paginator") {
+					action = "ec2:" + strings.TrimSuffix(strings.TrimPrefix(selector.Sel.Name, "New"), "Paginator")
+				}
+			}
+			if action != "" {
+				if used[packageName] == nil {
+					used[packageName] = map[string]bool{}
+				}
+				used[packageName][action] = true
+			}
+			return true
+		})
+	}
+
+	return used
+}
+
+// clientAction returns the action of a method called on an AWS client, or an
+// empty string for other calls.
+func clientAction(client, method string) string {
+	switch client {
+	case "ec2Client":
+		return "ec2:" + method
+	case "serviceQuotasClient":
+		return "servicequotas:" + method
+	}
+	return ""
+}
+
+func Test_registry(t *testing.T) {
+	used := usedActions(t)
+	if len(used) == 0 {
+		t.Fatal("expected AWS API calls in pkg/aws")
+	}
+
+	for packageName, actions := range used {
+		registered := map[string]bool{}
+		for _, permission := range registry[packageName] {
+			registered[permission.Action] = true
+		}
+		for action := range actions {
+			if !registered[action] {
+				t.Errorf("package %s calls %s, which is not in the registry", packageName, action)
+			}
+		}
+		for action := range registered {
+			if !actions[action] {
+				t.Errorf("registry has %s for package %s, which does not call it", action, packageName)
+			}
+		}
+	}
+	for packageName := range registry {
+		if _, ok := used[packageName]; !ok {
+			t.Errorf("registry has package %s, which does not call the AWS API", packageName)
+		}
+	}
+
+	// a condition would be bypassed by the same action in another statement
+	conditions := map[string]Condition{}
+	for packageName, permissions := range registry {
+		for _, permission := range permissions {
+			if condition, ok := conditions[permission.Action]; ok && condition != permission.Condition {
+				t.Errorf("package %s has %s with condition %q, other packages with %q", packageName, permission.Action, permission.Condition, condition)
+			}
+			conditions[permission.Action] = permission.Condition
+		}
+	}
+}
+
+func Test_Generate(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		featureGates          map[config.Feature]bool
+		unmanagedNetworksOnly bool
+		expectedActions       []string
+		unexpectedActions     []string
+	}{
+		{
+			name:            "case 0: default configuration",
+			expectedActions: []string{"ec2:CreateVpc", "ec2:DeleteVpcEndpoints", "ec2:DeleteNetworkInterface", "ec2:DescribeVpcs"},
+		},
+		{
+			name:              "case 1: disabled feature gate",
+			featureGates:      map[config.Feature]bool{config.DeleteLeftoverResources: false},
+			expectedActions:   []string{"ec2:CreateVpc", "ec2:DescribeNetworkInterfaces"},
+			unexpectedActions: []string{"ec2:DeleteNetworkInterface", "ec2:DeleteSecurityGroup"},
+		},
+		{
+			name:                  "case 2: unmanaged networks only",
+			unmanagedNetworksOnly: true,
+			expectedActions:       []string{"ec2:CreateVpcEndpoint", "ec2:CreateTags", "ec2:DescribeSubnets"},
+			unexpectedActions:     []string{"ec2:CreateVpc", "ec2:DeleteSubnet", "ec2:AssociateRouteTable", "ec2:DeleteNetworkInterface"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := config.Default()
+			operatorConfig.FeatureGates = tc.featureGates
+
+			policy, err := Generate(Options{Config: &operatorConfig, UnmanagedNetworksOnly: tc.unmanagedNetworksOnly})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actions := map[string]bool{}
+			for _, action := range policy.Actions() {
+				actions[action] = true
+			}
+			for _, action := range tc.expectedActions {
+				if !actions[action] {
+					t.Errorf("expected action %s in policy", action)
+				}
+			}
+			for _, action := range tc.unexpectedActions {
+				if actions[action] {
+					t.Errorf("expected action %s not to be in policy", action)
+				}
+			}
+
+			for _, statement := range policy.Statement {
+				if statement.Sid == "CreateTaggedResources" || statement.Sid == "OwnedResources" {
+					if len(statement.Condition) == 0 {
+						t.Errorf("expected statement %s to have a tag condition", statement.Sid)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_Generate_Statements(t *testing.T) {
+	operatorConfig := config.Default()
+	policy, err := Generate(Options{Config: &operatorConfig})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]struct {
+		actions   []string
+		resources []string
+		condition map[string]map[string]interface{}
+	}{
+		"CreateTaggedResources": {
+			actions:   []string{"ec2:CreateRouteTable", "ec2:CreateSubnet", "ec2:CreateVpc", "ec2:CreateVpcEndpoint"},
+			resources: []string{"arn:aws:ec2:*:*:route-table/*", "arn:aws:ec2:*:*:subnet/*", "arn:aws:ec2:*:*:vpc-endpoint/*", "arn:aws:ec2:*:*:vpc/*"},
+			condition: map[string]map[string]interface{}{"Null": {"aws:RequestTag/" + tags.NameAWSRole: "false"}},
+		},
+		"CreateRouteTableParentResources": {
+			actions:   []string{"ec2:CreateRouteTable"},
+			resources: []string{"arn:aws:ec2:*:*:vpc/*"},
+		},
+		"CreateSubnetParentResources": {
+			actions:   []string{"ec2:CreateSubnet"},
+			resources: []string{"arn:aws:ec2:*:*:vpc/*"},
+		},
+		"CreateVpcEndpointParentResources": {
+			actions:   []string{"ec2:CreateVpcEndpoint"},
+			resources: []string{"arn:aws:ec2:*:*:vpc/*", "arn:aws:ec2:*:*:subnet/*", "arn:aws:ec2:*:*:route-table/*", "arn:aws:ec2:*:*:security-group/*"},
+		},
+		"TagOnCreate": {
+			actions:   []string{"ec2:CreateTags"},
+			resources: []string{"arn:aws:ec2:*:*:route-table/*", "arn:aws:ec2:*:*:subnet/*", "arn:aws:ec2:*:*:vpc-endpoint/*", "arn:aws:ec2:*:*:vpc/*"},
+			condition: map[string]map[string]interface{}{"StringEquals": {"ec2:CreateAction": []string{"CreateRouteTable", "CreateSubnet", "CreateVpc", "CreateVpcEndpoint"}}},
+		},
+		"OwnedResources": {
+			resources: []string{"*"},
+			condition: map[string]map[string]interface{}{"Null": {"aws:ResourceTag/" + tags.NameAWSRole: "false"}},
+		},
+		"AllResources": {
+			resources: []string{"*"},
+		},
+	}
+
+	for _, statement := range policy.Statement {
+		e, ok := expected[statement.Sid]
+		if !ok {
+			t.Errorf("unexpected statement %s", statement.Sid)
+			continue
+		}
+		delete(expected, statement.Sid)
+		if e.actions != nil && !reflect.DeepEqual(statement.Action, e.actions) {
+			t.Errorf("expected statement %s to have actions %v, got %v", statement.Sid, e.actions, statement.Action)
+		}
+		if !reflect.DeepEqual(statement.Resource, e.resources) {
+			t.Errorf("expected statement %s to have resources %v, got %v", statement.Sid, e.resources, statement.Resource)
+		}
+		if !reflect.DeepEqual(statement.Condition, e.condition) {
+			t.Errorf("expected statement %s to have condition %v, got %v", statement.Sid, e.condition, statement.Condition)
+		}
+	}
+	for sid := range expected {
+		t.Errorf("expected statement %s in policy", sid)
+	}
+}
@@ -0,0 +1,125 @@
+package iampolicy
+
+import (
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+// Condition restricts an action to requests or resources of aws-vpc-operator
+// with tag-based IAM conditions.
+type Condition string
+
+const (
+	// NoCondition is used for actions that do not support resource-level
+	// permissions, e.g. Describe actions, and for actions on resources that
+	// aws-vpc-operator did not create.
+	NoCondition Condition = ""
+
+	// TaggedOnCreate allows the action only when the created resource is
+	// tagged with tags.NameAWSRole in the same request.
+	TaggedOnCreate Condition = "TaggedOnCreate"
+
+	// OwnedResource allows the action only on resources that have the
+	// tags.NameAWSRole tag, which aws-vpc-operator sets on all resources that
+	// it creates.
+	OwnedResource Condition = "OwnedResource"
+)
+
+// Permission is an EC2 or Service Quotas action that a client package in
+// pkg/aws uses.
+type Permission struct {
+	// Action is the IAM action, e.g. ec2:CreateVpc.
+	Action string
+
+	Condition Condition
+
+	// ManagedNetworkOnly is set for actions that are not used for unmanaged
+	// networks, in which only VPC endpoints are reconciled.
+	ManagedNetworkOnly bool
+
+	// Feature is set for actions that are only used when the feature gate is
+	// enabled.
+	Feature config.Feature
+}
+
+// registry has the EC2 and Service Quotas actions of each client package in
+// pkg/aws, by package name. It must list every EC2 and Service Quotas API call
+// of the packages, which is verified by the tests of this package.
+var registry = map[string][]Permission{
+	"blockers": {
+		{Action: "ec2:DescribeNetworkInterfaces", ManagedNetworkOnly: true},
+		{Action: "ec2:DescribeSecurityGroups", ManagedNetworkOnly: true},
+		// leftover resources are owned by the cluster, but they are not
+		// created by aws-vpc-operator, so they may not have its role tag
+		{Action: "ec2:DeleteNetworkInterface", ManagedNetworkOnly: true, Feature: config.DeleteLeftoverResources},
+		{Action: "ec2:DeleteSecurityGroup", ManagedNetworkOnly: true, Feature: config.DeleteLeftoverResources},
+	},
+	"discovery": {
+		{Action: "ec2:DescribeVpcs"},
+		{Action: "ec2:DescribeSubnets"},
+		{Action: "ec2:DescribeRouteTables"},
+		{Action: "ec2:DescribeVpcEndpoints"},
+	},
+	"permissions": {
+		{Action: "ec2:CreateVpc", Condition: TaggedOnCreate, ManagedNetworkOnly: true, Feature: config.PermissionsPreflight},
+		{Action: "ec2:CreateSubnet", Condition: TaggedOnCreate, ManagedNetworkOnly: true, Feature: config.PermissionsPreflight},
+		{Action: "ec2:CreateRouteTable", Condition: TaggedOnCreate, ManagedNetworkOnly: true, Feature: config.PermissionsPreflight},
+		{Action: "ec2:AssociateRouteTable", ManagedNetworkOnly: true, Feature: config.PermissionsPreflight},
+		{Action: "ec2:CreateVpcEndpoint", Condition: TaggedOnCreate, Feature: config.PermissionsPreflight},
+		{Action: "ec2:CreateTags", Condition: OwnedResource, Feature: config.PermissionsPreflight},
+		{Action: "ec2:DeleteVpc", Condition: OwnedResource, ManagedNetworkOnly: true, Feature: config.PermissionsPreflight},
+		{Action: "ec2:DeleteSubnet", Condition: OwnedResource, ManagedNetworkOnly: true, Feature: config.PermissionsPreflight},
+		{Action: "ec2:DeleteRouteTable", Condition: OwnedResource, ManagedNetworkOnly: true, Feature: config.PermissionsPreflight},
+		{Action: "ec2:DisassociateRouteTable", ManagedNetworkOnly: true, Feature: config.PermissionsPreflight},
+		{Action: "ec2:DeleteVpcEndpoints", Condition: OwnedResource, Feature: config.PermissionsPreflight},
+		{Action: "ec2:DeleteTags", Condition: OwnedResource, Feature: config.PermissionsPreflight},
+	},
+	"quotas": {
+		{Action: "ec2:DescribeVpcs", ManagedNetworkOnly: true, Feature: config.QuotaPreflight},
+		{Action: "ec2:DescribeVpcEndpoints", Feature: config.QuotaPreflight},
+		{Action: "ec2:DescribeAddresses", ManagedNetworkOnly: true, Feature: config.QuotaPreflight},
+		{Action: "servicequotas:GetServiceQuota", Feature: config.QuotaPreflight},
+		{Action: "servicequotas:GetAWSDefaultServiceQuota", Feature: config.QuotaPreflight},
+	},
+	"routetables": {
+		{Action: "ec2:DescribeRouteTables", ManagedNetworkOnly: true},
+		{Action: "ec2:CreateRouteTable", Condition: TaggedOnCreate, ManagedNetworkOnly: true},
+		// subnets that are associated may have been created by the user
+		{Action: "ec2:AssociateRouteTable", ManagedNetworkOnly: true},
+		{Action: "ec2:DisassociateRouteTable", ManagedNetworkOnly: true},
+		{Action: "ec2:DeleteRouteTable", Condition: OwnedResource, ManagedNetworkOnly: true},
+	},
+	"snapshot": {
+		{Action: "ec2:DescribeVpcs"},
+		{Action: "ec2:DescribeVpcAttribute", ManagedNetworkOnly: true},
+		// endpoint subnets and route tables are looked up for unmanaged
+		// networks too
+		{Action: "ec2:DescribeSubnets"},
+		{Action: "ec2:DescribeRouteTables"},
+		{Action: "ec2:DescribeVpcEndpoints"},
+	},
+	"subnets": {
+		{Action: "ec2:DescribeSubnets", ManagedNetworkOnly: true},
+		{Action: "ec2:DescribeRouteTables", ManagedNetworkOnly: true},
+		// availability zones of new subnets without one in AWSCluster spec
+		{Action: "ec2:DescribeAvailabilityZones", ManagedNetworkOnly: true},
+		{Action: "ec2:CreateSubnet", Condition: TaggedOnCreate, ManagedNetworkOnly: true},
+		{Action: "ec2:AssociateRouteTable", ManagedNetworkOnly: true},
+		{Action: "ec2:DisassociateRouteTable", ManagedNetworkOnly: true},
+		{Action: "ec2:DeleteSubnet", Condition: OwnedResource, ManagedNetworkOnly: true},
+	},
+	"tags": {
+		{Action: "ec2:CreateTags", Condition: OwnedResource},
+		{Action: "ec2:DeleteTags", Condition: OwnedResource},
+	},
+	"vpc": {
+		{Action: "ec2:DescribeVpcs", ManagedNetworkOnly: true},
+		{Action: "ec2:CreateVpc", Condition: TaggedOnCreate, ManagedNetworkOnly: true},
+		{Action: "ec2:ModifyVpcAttribute", Condition: OwnedResource, ManagedNetworkOnly: true},
+		{Action: "ec2:DeleteVpc", Condition: OwnedResource, ManagedNetworkOnly: true},
+	},
+	"vpcendpoint": {
+		{Action: "ec2:CreateVpcEndpoint", Condition: TaggedOnCreate},
+		{Action: "ec2:ModifyVpcEndpoint", Condition: OwnedResource},
+		{Action: "ec2:DeleteVpcEndpoints", Condition: OwnedResource},
+	},
+}
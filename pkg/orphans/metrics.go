@@ -0,0 +1,76 @@
+package orphans
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "aws_vpc_operator"
+	metricsSubsystem = "orphan_scanner"
+)
+
+var (
+	resourceLabels = []string{"account", "region", "type", "cluster_name"}
+
+	orphanedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "orphaned_resources",
+		Help:      "Number of resources owned by clusters without an AWSCluster, found in the last scan.",
+	}, resourceLabels)
+
+	deletedResources = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "deleted_resources_total",
+		Help:      "Number of orphaned resources that were deleted after their deletion was approved.",
+	}, resourceLabels)
+
+	scanErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "errors_total",
+		Help:      "Number of failed scans of targets and deletions of orphaned resources.",
+	})
+
+	lastScanTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "last_scan_timestamp_seconds",
+		Help:      "Time of the last scan for orphaned resources, in seconds since the Unix epoch.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedResources, deletedResources, scanErrors, lastScanTimestamp)
+}
+
+// updateMetrics replaces the orphaned resources metric with the orphans of the
+// report, and counts the deleted orphans and errors.
+func updateMetrics(report Report) {
+	orphanedResources.Reset()
+	for _, orphan := range report.Orphans {
+		orphanedResources.With(orphan.labels()).Inc()
+	}
+	for _, orphan := range report.Deleted {
+		deletedResources.With(orphan.labels()).Inc()
+		orphanedResources.With(orphan.labels()).Dec()
+	}
+	scanErrors.Add(float64(len(report.Errors)))
+	lastScanTimestamp.Set(float64(report.Time.Unix()))
+}
+
+func (o Orphan) labels() prometheus.Labels {
+	account := ""
+	if parsed, err := arn.Parse(o.RoleARN); err == nil {
+		account = parsed.AccountID
+	}
+	return prometheus.Labels{
+		"account":      account,
+		"region":       o.Region,
+		"type":         string(o.Type),
+		"cluster_name": o.ClusterName,
+	}
+}
@@ -0,0 +1,163 @@
+// Package orphans finds network resources with the aws-vpc-operator ownership
+// tag of clusters whose AWSCluster no longer exists in the management
+// cluster, e.g. because the AWSCluster was deleted while the operator was not
+// running. Orphans are reported, and they are only deleted when their
+// deletion has been explicitly approved for their cluster.
+//
+// The ownership tag only has the cluster name, so resources of clusters of
+// other management clusters that use the same roles and regions are reported
+// as orphans too, see ReportNote.
+package orphans
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/discovery"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// Target is a role and region in which orphans are searched.
+type Target struct {
+	RoleARN string `json:"roleARN"`
+	Region  string `json:"region"`
+}
+
+func (t Target) String() string {
+	return t.RoleARN + "@" + t.Region
+}
+
+// ParseTargets parses comma-separated targets in the format
+// <role-arn>@<region>, e.g.
+// "arn:aws:iam::123456789012:role/operator@eu-west-1".
+func ParseTargets(s string) ([]Target, error) {
+	var targets []Target
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		roleArn, region, ok := strings.Cut(item, "@")
+		if !ok || roleArn == "" || region == "" {
+			return nil, microerror.Maskf(errors.InvalidConfigError, "target %q must be in the format <role-arn>@<region>", item)
+		}
+		targets = append(targets, Target{RoleARN: roleArn, Region: region})
+	}
+	return targets, nil
+}
+
+// Orphan is a resource that is owned by a cluster without an AWSCluster.
+type Orphan struct {
+	Target
+	Type        discovery.ResourceType `json:"type"`
+	Id          string                 `json:"id"`
+	VpcId       string                 `json:"vpcId,omitempty"`
+	ClusterName string                 `json:"clusterName"`
+
+	// ServiceName is the AWS service of a VPC endpoint.
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+func (o Orphan) String() string {
+	return fmt.Sprintf("%s %s of cluster %s in %s", o.Type, o.Id, o.ClusterName, o.Region)
+}
+
+// ReportNote explains which clusters are reported as orphans, so that the
+// deletion of clusters of other management clusters is not approved.
+const ReportNote = "Resources of clusters without an AWSCluster in this management cluster are reported, " +
+	"including resources of clusters of other management clusters that use the same roles and regions. " +
+	"Check that a cluster does not exist in any management cluster before approving the deletion of its resources."
+
+// Report is the result of a scan.
+type Report struct {
+	Time    time.Time `json:"time"`
+	Targets []Target  `json:"targets"`
+	Orphans []Orphan  `json:"orphans"`
+
+	// Note is ReportNote.
+	Note string `json:"note"`
+
+	// Deleted are the orphans that were deleted after the scan, because
+	// their deletion was approved.
+	Deleted []Orphan `json:"deleted,omitempty"`
+
+	// Errors are the failures of scanning targets and of deleting orphans.
+	// Orphans of targets that failed are missing from the report.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ClusterNames returns the sorted names of the clusters that have orphans.
+func (r Report) ClusterNames() []string {
+	seen := map[string]bool{}
+	var clusterNames []string
+	for _, orphan := range r.Orphans {
+		if !seen[orphan.ClusterName] {
+			seen[orphan.ClusterName] = true
+			clusterNames = append(clusterNames, orphan.ClusterName)
+		}
+	}
+	sort.Strings(clusterNames)
+	return clusterNames
+}
+
+// Summary returns a human-readable summary of the report, with the orphans of
+// each cluster on a separate line.
+func (r Report) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Scanned %d targets at %s, found %d orphaned resources", len(r.Targets), r.Time.Format(time.RFC3339), len(r.Orphans))
+	if len(r.Deleted) > 0 {
+		fmt.Fprintf(&b, ", deleted %d", len(r.Deleted))
+	}
+	b.WriteString("\n")
+	if r.Note != "" {
+		fmt.Fprintf(&b, "note: %s\n", r.Note)
+	}
+
+	for _, clusterName := range r.ClusterNames() {
+		var resources []string
+		for _, orphan := range r.Orphans {
+			if orphan.ClusterName == clusterName {
+				resources = append(resources, fmt.Sprintf("%s %s (%s)", orphan.Type, orphan.Id, orphan.Region))
+			}
+		}
+		fmt.Fprintf(&b, "%s: %s\n", clusterName, strings.Join(resources, ", "))
+	}
+	for _, err := range r.Errors {
+		fmt.Fprintf(&b, "error: %s\n", err)
+	}
+	return b.String()
+}
+
+func sortOrphans(orphans []Orphan) {
+	sort.SliceStable(orphans, func(i, j int) bool {
+		if orphans[i].ClusterName != orphans[j].ClusterName {
+			return orphans[i].ClusterName < orphans[j].ClusterName
+		}
+		if orphans[i].Target != orphans[j].Target {
+			return orphans[i].Target.String() < orphans[j].Target.String()
+		}
+		if orphans[i].Type != orphans[j].Type {
+			return deletionOrder(orphans[i].Type) < deletionOrder(orphans[j].Type)
+		}
+		return orphans[i].Id < orphans[j].Id
+	})
+}
+
+// deletionOrder returns the position of the resource type in the order in
+// which resources have to be deleted.
+func deletionOrder(resourceType discovery.ResourceType) int {
+	switch resourceType {
+	case discovery.ResourceTypeVpcEndpoint:
+		return 0
+	case discovery.ResourceTypeRouteTable:
+		return 1
+	case discovery.ResourceTypeSubnet:
+		return 2
+	default:
+		return 3
+	}
+}
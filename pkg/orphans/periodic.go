@@ -0,0 +1,224 @@
+package orphans
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	// DefaultInterval is how often the periodic scanner scans the targets.
+	DefaultInterval = time.Hour
+
+	// ApproveDeletionAnnotation on the report ConfigMap approves deletion of
+	// the orphans of the listed clusters, as comma-separated cluster names.
+	// Clusters are removed from the annotation once all their orphans have
+	// been deleted, so that the approval does not apply to another cluster
+	// with the same name later.
+	ApproveDeletionAnnotation = "aws-vpc-operator.giantswarm.io/approve-orphan-deletion"
+
+	// ReportKey is the key of the report as JSON in the report ConfigMap.
+	ReportKey = "orphans.json"
+
+	// SummaryKey is the key of the human-readable summary of the report in
+	// the report ConfigMap.
+	SummaryKey = "summary"
+)
+
+// PeriodicScanner scans the targets for orphans periodically, deletes the
+// orphans of clusters approved in the report ConfigMap, and writes the report
+// to that ConfigMap and to metrics.
+type PeriodicScanner struct {
+	scanner   *Scanner
+	k8sClient client.Client
+	apiReader client.Reader
+	targets   []Target
+	configMap types.NamespacedName
+	interval  time.Duration
+	config    *config.Store
+}
+
+// NewPeriodicScanner creates a periodic scanner. The report ConfigMap is read
+// with the API reader, so that ConfigMaps are not cached by the manager.
+func NewPeriodicScanner(scanner *Scanner, k8sClient client.Client, apiReader client.Reader, targets []Target, configMap types.NamespacedName, interval time.Duration, config *config.Store) (*PeriodicScanner, error) {
+	if scanner == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "scanner must not be empty")
+	}
+	if k8sClient == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "k8sClient must not be empty")
+	}
+	if apiReader == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "apiReader must not be empty")
+	}
+	if len(targets) == 0 {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "targets must not be empty")
+	}
+	if configMap.Namespace == "" || configMap.Name == "" {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "configMap must have namespace and name")
+	}
+	if config == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "config must not be empty")
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return &PeriodicScanner{
+		scanner:   scanner,
+		k8sClient: k8sClient,
+		apiReader: apiReader,
+		targets:   targets,
+		configMap: configMap,
+		interval:  interval,
+		config:    config,
+	}, nil
+}
+
+// Start scans the targets right away and then periodically until the context
+// is done. It implements manager.Runnable.
+func (p *PeriodicScanner) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithName("orphan-scanner"))
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.run(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader scan, as it may delete resources.
+// It implements manager.LeaderElectionRunnable.
+func (p *PeriodicScanner) NeedLeaderElection() bool {
+	return true
+}
+
+func (p *PeriodicScanner) run(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	configMap := &corev1.ConfigMap{}
+	err := p.apiReader.Get(ctx, p.configMap, configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = nil
+	} else if err != nil {
+		logger.Error(err, "Failed to get orphan report ConfigMap")
+		scanErrors.Inc()
+		return
+	}
+
+	report, err := p.scanner.Scan(ctx, p.targets)
+	if err != nil {
+		scanErrors.Inc()
+		return
+	}
+
+	var approved []string
+	if configMap != nil {
+		approved = ParseClusterNames(configMap.Annotations[ApproveDeletionAnnotation])
+	}
+	if len(approved) > 0 {
+		if p.config.Get().DryRun {
+			logger.Info("Skipped deleting approved orphaned resources in dry-run mode", "cluster-names", approved)
+		} else {
+			p.scanner.DeleteApproved(ctx, &report, approved)
+		}
+	}
+	updateMetrics(report)
+
+	err = p.writeReport(ctx, configMap, report, approved)
+	if err != nil {
+		logger.Error(err, "Failed to write orphan report ConfigMap")
+		scanErrors.Inc()
+	}
+}
+
+// writeReport writes the report to the ConfigMap, and removes the approvals
+// of clusters without remaining orphans.
+func (p *PeriodicScanner) writeReport(ctx context.Context, configMap *corev1.ConfigMap, report Report, approved []string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	create := configMap == nil
+	if create {
+		configMap = &corev1.ConfigMap{}
+		configMap.Namespace = p.configMap.Namespace
+		configMap.Name = p.configMap.Name
+	}
+	configMap.Data = map[string]string{
+		ReportKey:  string(data),
+		SummaryKey: report.Summary(),
+	}
+
+	// clusters whose orphans may be hidden by a failed scan keep approval
+	if len(approved) > 0 && len(report.Errors) == 0 {
+		remaining := map[string]bool{}
+		for _, clusterName := range report.remaining() {
+			remaining[clusterName] = true
+		}
+		var stillApproved []string
+		for _, clusterName := range approved {
+			if remaining[clusterName] {
+				stillApproved = append(stillApproved, clusterName)
+			}
+		}
+		if len(stillApproved) > 0 {
+			configMap.Annotations[ApproveDeletionAnnotation] = strings.Join(stillApproved, ",")
+		} else {
+			delete(configMap.Annotations, ApproveDeletionAnnotation)
+		}
+	}
+
+	if create {
+		return microerror.Mask(p.k8sClient.Create(ctx, configMap))
+	}
+	return microerror.Mask(p.k8sClient.Update(ctx, configMap))
+}
+
+// remaining returns the sorted names of clusters with orphans that have not
+// been deleted.
+func (r Report) remaining() []string {
+	deleted := map[Orphan]bool{}
+	for _, orphan := range r.Deleted {
+		deleted[orphan] = true
+	}
+	seen := map[string]bool{}
+	var clusterNames []string
+	for _, orphan := range r.Orphans {
+		if !deleted[orphan] && !seen[orphan.ClusterName] {
+			seen[orphan.ClusterName] = true
+			clusterNames = append(clusterNames, orphan.ClusterName)
+		}
+	}
+	sort.Strings(clusterNames)
+	return clusterNames
+}
+
+// ParseClusterNames parses comma-separated cluster names.
+func ParseClusterNames(s string) []string {
+	var clusterNames []string
+	for _, clusterName := range strings.Split(s, ",") {
+		clusterName = strings.TrimSpace(clusterName)
+		if clusterName != "" {
+			clusterNames = append(clusterNames, clusterName)
+		}
+	}
+	return clusterNames
+}
@@ -0,0 +1,214 @@
+package orphans
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/giantswarm/microerror"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/assumerole"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/discovery"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/routetables"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/subnets"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpc"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/vpcendpoint"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// Scanner finds orphans in AWS and deletes them.
+type Scanner struct {
+	k8sClient         client.Reader
+	discoveryClient   discovery.Client
+	vpcClient         vpc.Client
+	subnetsClient     subnets.Client
+	routeTablesClient routetables.Client
+	vpcEndpointClient vpcendpoint.Client
+}
+
+// NewScanner creates a scanner that lists AWSClusters with the Kubernetes
+// client, and finds and deletes resources with the AWS clients.
+func NewScanner(k8sClient client.Reader, ec2Client *ec2.Client, assumeRoleClient assumerole.Client) (*Scanner, error) {
+	if k8sClient == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "k8sClient must not be empty")
+	}
+
+	discoveryClient, err := discovery.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	vpcClient, err := vpc.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	subnetsClient, err := subnets.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	routeTablesClient, err := routetables.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	vpcEndpointClient, err := vpcendpoint.NewClient(ec2Client, assumeRoleClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return &Scanner{
+		k8sClient:         k8sClient,
+		discoveryClient:   discoveryClient,
+		vpcClient:         vpcClient,
+		subnetsClient:     subnetsClient,
+		routeTablesClient: routeTablesClient,
+		vpcEndpointClient: vpcEndpointClient,
+	}, nil
+}
+
+// Scan finds the resources in the targets that are owned by clusters without
+// an AWSCluster in any namespace of this management cluster, which includes
+// clusters of other management clusters, see ReportNote. Targets that cannot be scanned are reported
+// in the errors of the report, while failing to list AWSClusters fails the
+// whole scan, so that resources of existing clusters are never reported.
+func (s *Scanner) Scan(ctx context.Context, targets []Target) (report Report, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started scanning for orphaned resources", "targets", len(targets))
+	defer func() {
+		if err == nil {
+			logger.Info("Finished scanning for orphaned resources", "orphans", len(report.Orphans), "errors", len(report.Errors))
+		} else {
+			logger.Error(err, "Failed to scan for orphaned resources")
+		}
+	}()
+
+	awsClusters := &capa.AWSClusterList{}
+	err = s.k8sClient.List(ctx, awsClusters)
+	if err != nil {
+		return Report{}, microerror.Mask(err)
+	}
+	existingClusters := map[string]bool{}
+	for _, awsCluster := range awsClusters.Items {
+		existingClusters[awsCluster.Name] = true
+	}
+
+	report = Report{
+		Time:    time.Now().UTC(),
+		Targets: targets,
+		Orphans: []Orphan{},
+		Note:    ReportNote,
+	}
+	for _, target := range targets {
+		discovered, err := s.discoveryClient.DiscoverAll(ctx, discovery.DiscoverAllInput{
+			RoleARN: target.RoleARN,
+			Region:  target.Region,
+		})
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to scan %s: %s", target, err))
+			continue
+		}
+
+		for _, resource := range discovered {
+			if existingClusters[resource.ClusterName] {
+				continue
+			}
+			report.Orphans = append(report.Orphans, Orphan{
+				Target:      target,
+				Type:        resource.Type,
+				Id:          resource.Id,
+				VpcId:       resource.VpcId,
+				ClusterName: resource.ClusterName,
+				ServiceName: resource.ServiceName,
+			})
+		}
+	}
+	sortOrphans(report.Orphans)
+
+	return report, nil
+}
+
+// DeleteApproved deletes the orphans of the approved clusters in the report,
+// and adds them to the deleted orphans of the report. Resources are deleted
+// in dependency order, and the remaining resources of a cluster in a target
+// are skipped after a failure, so that they are deleted after a later scan.
+func (s *Scanner) DeleteApproved(ctx context.Context, report *Report, approvedClusterNames []string) {
+	approved := map[string]bool{}
+	for _, clusterName := range approvedClusterNames {
+		approved[clusterName] = true
+	}
+
+	type group struct {
+		target      Target
+		clusterName string
+	}
+	failed := map[group]bool{}
+
+	// orphans are sorted by cluster, target and deletion order
+	for _, orphan := range report.Orphans {
+		g := group{target: orphan.Target, clusterName: orphan.ClusterName}
+		if !approved[orphan.ClusterName] || failed[g] {
+			continue
+		}
+
+		logger := log.FromContext(ctx).WithValues("cluster-name", orphan.ClusterName, "region", orphan.Region, "type", orphan.Type, "id", orphan.Id)
+		logger.Info("Deleting orphaned resource")
+		err := s.delete(ctx, orphan)
+		if errors.IsResourceDeletionInProgress(err) {
+			logger.Info("Deletion of orphaned resource is in progress")
+			failed[g] = true
+			continue
+		} else if err != nil {
+			logger.Error(err, "Failed to delete orphaned resource")
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to delete %s: %s", orphan, err))
+			failed[g] = true
+			continue
+		}
+		logger.Info("Deleted orphaned resource")
+		report.Deleted = append(report.Deleted, orphan)
+	}
+}
+
+func (s *Scanner) delete(ctx context.Context, orphan Orphan) error {
+	var err error
+	switch orphan.Type {
+	case discovery.ResourceTypeVpcEndpoint:
+		err = s.vpcEndpointClient.Delete(ctx, vpcendpoint.DeleteVpcEndpointInput{
+			RoleARN:     orphan.RoleARN,
+			Region:      orphan.Region,
+			ServiceName: orphan.ServiceName,
+			Type:        ec2Types.VpcEndpointTypeGateway,
+			VpcId:       orphan.VpcId,
+			ClusterName: orphan.ClusterName,
+		})
+		if errors.IsResourceAlreadyDeleted(err) {
+			err = nil
+		}
+	case discovery.ResourceTypeRouteTable:
+		err = s.routeTablesClient.Delete(ctx, routetables.DeleteRouteTableInput{
+			RoleARN:      orphan.RoleARN,
+			Region:       orphan.Region,
+			RouteTableId: orphan.Id,
+			ClusterName:  orphan.ClusterName,
+		})
+	case discovery.ResourceTypeSubnet:
+		err = s.subnetsClient.Delete(ctx, subnets.DeleteSubnetsInput{
+			RoleARN:     orphan.RoleARN,
+			Region:      orphan.Region,
+			SubnetIds:   []string{orphan.Id},
+			ClusterName: orphan.ClusterName,
+		})
+	case discovery.ResourceTypeVpc:
+		err = s.vpcClient.Delete(ctx, vpc.DeleteVpcInput{
+			RoleARN:     orphan.RoleARN,
+			Region:      orphan.Region,
+			VpcId:       orphan.Id,
+			ClusterName: orphan.ClusterName,
+		})
+	default:
+		err = microerror.Maskf(errors.InvalidConfigError, "unknown resource type %q", orphan.Type)
+	}
+	return microerror.Mask(err)
+}
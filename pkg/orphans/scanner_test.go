@@ -0,0 +1,175 @@
+package orphans
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/tags"
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+// newFakeEC2 fakes an account with a VPC of cluster existing, which has an
+// AWSCluster, and a VPC of cluster deleted, which does not.
+func newFakeEC2() *awstest.EC2 {
+	vpcs := []ec2Types.Vpc{
+		{VpcId: aws.String("vpc-existing"), CidrBlock: aws.String("10.0.0.0/16"), Tags: []ec2Types.Tag{{Key: aws.String(tags.NameAWSProviderPrefix + "existing"), Value: aws.String("owned")}}},
+		{VpcId: aws.String("vpc-orphan"), CidrBlock: aws.String("10.1.0.0/16"), Tags: []ec2Types.Tag{{Key: aws.String(tags.NameAWSProviderPrefix + "deleted"), Value: aws.String("owned")}}},
+	}
+
+	return awstest.NewEC2().
+		On("DescribeVpcs", func(input interface{}) (interface{}, error) {
+			vpcIds := input.(*ec2.DescribeVpcsInput).VpcIds
+			if len(vpcIds) == 0 {
+				return &ec2.DescribeVpcsOutput{Vpcs: vpcs}, nil
+			}
+			output := &ec2.DescribeVpcsOutput{}
+			for _, vpc := range vpcs {
+				if aws.ToString(vpc.VpcId) == vpcIds[0] {
+					output.Vpcs = append(output.Vpcs, vpc)
+				}
+			}
+			return output, nil
+		}).
+		On("DescribeSubnets", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeSubnetsOutput{}, nil
+		}).
+		On("DescribeRouteTables", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeRouteTablesOutput{}, nil
+		}).
+		On("DescribeVpcEndpoints", func(interface{}) (interface{}, error) {
+			return &ec2.DescribeVpcEndpointsOutput{}, nil
+		}).
+		On("DeleteVpc", func(interface{}) (interface{}, error) {
+			return &ec2.DeleteVpcOutput{}, nil
+		})
+}
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := capa.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &capa.AWSCluster{}
+	existing.Namespace = "org-test"
+	existing.Name = "existing"
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, existing)...).Build()
+}
+
+func Test_Scanner(t *testing.T) {
+	testCases := []struct {
+		name            string
+		approved        []string
+		expectedDeleted int
+	}{
+		{
+			name: "case 0: orphans are only reported without approval",
+		},
+		{
+			name:            "case 1: orphans of approved cluster are deleted",
+			approved:        []string{"deleted"},
+			expectedDeleted: 1,
+		},
+		{
+			name:     "case 2: approval of cluster without orphans deletes nothing",
+			approved: []string{"existing"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeEC2 := newFakeEC2()
+			scanner, err := NewScanner(newFakeClient(t), fakeEC2.Client(), awstest.AssumeRoleClient{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			report, err := scanner.Scan(context.Background(), []Target{{RoleARN: awstest.RoleARN, Region: awstest.Region}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(report.Orphans) != 1 || report.Orphans[0].Id != "vpc-orphan" || report.Orphans[0].ClusterName != "deleted" {
+				t.Fatalf("expected orphaned VPC of cluster deleted, got %v", report.Orphans)
+			}
+
+			scanner.DeleteApproved(context.Background(), &report, tc.approved)
+			if len(report.Deleted) != tc.expectedDeleted {
+				t.Errorf("expected %d deleted orphans, got %v", tc.expectedDeleted, report.Deleted)
+			}
+			if calls := fakeEC2.Calls("DeleteVpc"); calls != tc.expectedDeleted {
+				t.Errorf("expected %d DeleteVpc calls, got %d", tc.expectedDeleted, calls)
+			}
+			if len(report.Errors) != 0 {
+				t.Errorf("unexpected errors %v", report.Errors)
+			}
+		})
+	}
+}
+
+func Test_PeriodicScanner_run(t *testing.T) {
+	configMapKey := types.NamespacedName{Namespace: "giantswarm", Name: "aws-vpc-operator-orphans"}
+	configMap := &corev1.ConfigMap{}
+	configMap.Namespace = configMapKey.Namespace
+	configMap.Name = configMapKey.Name
+	configMap.Annotations = map[string]string{ApproveDeletionAnnotation: "deleted, other"}
+
+	k8sClient := newFakeClient(t, configMap)
+	fakeEC2 := newFakeEC2()
+	scanner, err := NewScanner(k8sClient, fakeEC2.Client(), awstest.AssumeRoleClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	operatorConfig := config.Default()
+	periodicScanner, err := NewPeriodicScanner(scanner, k8sClient, k8sClient, []Target{{RoleARN: awstest.RoleARN, Region: awstest.Region}}, configMapKey, 0, config.NewStore(&operatorConfig))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	periodicScanner.run(context.Background())
+
+	if calls := fakeEC2.Calls("DeleteVpc"); calls != 1 {
+		t.Errorf("expected approved orphan to be deleted, got %d DeleteVpc calls", calls)
+	}
+
+	updated := &corev1.ConfigMap{}
+	err = k8sClient.Get(context.Background(), configMapKey, updated)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var report Report
+	err = json.Unmarshal([]byte(updated.Data[ReportKey]), &report)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Orphans) != 1 || len(report.Deleted) != 1 {
+		t.Errorf("expected report with 1 orphan that was deleted, got %v", report)
+	}
+	if updated.Data[SummaryKey] == "" {
+		t.Errorf("expected summary to be written")
+	}
+	if !strings.Contains(updated.Data[SummaryKey], ReportNote) {
+		t.Errorf("expected summary to note that clusters of other management clusters are reported, got %q", updated.Data[SummaryKey])
+	}
+	if _, ok := updated.Annotations[ApproveDeletionAnnotation]; ok {
+		t.Errorf("expected approval to be removed after all orphans were deleted, got %q", updated.Annotations[ApproveDeletionAnnotation])
+	}
+}
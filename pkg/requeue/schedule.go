@@ -0,0 +1,219 @@
+// Package requeue decides when a reconciliation that is waiting for AWS
+// resources is retried. It initially retries often and then progressively
+// backs off, based on how long the condition has been waiting.
+package requeue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/util/wait"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// DefaultSchedule retries every minute during the first 5 minutes, then every
+// 5 minutes until 15 minutes have passed, and then every 15 minutes with
+// warning severity.
+var DefaultSchedule = Schedule{
+	Steps: []Step{
+		{After: 0, RequeueAfter: time.Minute, Severity: capi.ConditionSeverityInfo},
+		{After: 5 * time.Minute, RequeueAfter: 5 * time.Minute, Severity: capi.ConditionSeverityInfo},
+		{After: 15 * time.Minute, RequeueAfter: 15 * time.Minute, Severity: capi.ConditionSeverityWarning},
+	},
+	Jitter: 0.1,
+}
+
+// Step of a schedule, which is used when a condition has been waiting for at
+// least After.
+type Step struct {
+	After        time.Duration
+	RequeueAfter time.Duration
+	Severity     capi.ConditionSeverity
+}
+
+// Schedule is a progressive requeue policy.
+type Schedule struct {
+	// Steps ordered by After. The first step must start after 0.
+	Steps []Step
+
+	// Jitter is the max fraction of RequeueAfter that is randomly added to
+	// it, so that clusters that started waiting together are not retried
+	// together.
+	Jitter float64
+}
+
+// Decision tells when the reconciliation is retried, and how the waiting is
+// reported in the condition.
+type Decision struct {
+	RequeueAfter time.Duration
+	Severity     capi.ConditionSeverity
+
+	// MessageSuffix is appended to the condition message when the waiting
+	// takes longer than expected, e.g. " for more than 15 minutes".
+	MessageSuffix string
+}
+
+// Parse parses a schedule in the format used by the operator flag and the
+// AWSCluster annotation, which is a comma-separated list of steps in the
+// format <after>:<requeue-after>[:<severity>], e.g.
+// "0s:1m,5m:5m,15m:15m:Warning". Severity defaults to Info.
+func Parse(s string, jitter float64) (Schedule, error) {
+	schedule := Schedule{
+		Jitter: jitter,
+	}
+
+	for _, rawStep := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(rawStep), ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return Schedule{}, microerror.Maskf(errors.InvalidConfigError, "requeue schedule step %q must be in format <after>:<requeue-after>[:<severity>]", rawStep)
+		}
+
+		after, err := time.ParseDuration(parts[0])
+		if err != nil {
+			return Schedule{}, microerror.Maskf(errors.InvalidConfigError, "requeue schedule step %q has invalid duration: %s", rawStep, err)
+		}
+		requeueAfter, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return Schedule{}, microerror.Maskf(errors.InvalidConfigError, "requeue schedule step %q has invalid duration: %s", rawStep, err)
+		}
+		severity := capi.ConditionSeverityInfo
+		if len(parts) == 3 {
+			severity = capi.ConditionSeverity(parts[2])
+		}
+
+		schedule.Steps = append(schedule.Steps, Step{
+			After:        after,
+			RequeueAfter: requeueAfter,
+			Severity:     severity,
+		})
+	}
+
+	sort.SliceStable(schedule.Steps, func(i, j int) bool {
+		return schedule.Steps[i].After < schedule.Steps[j].After
+	})
+
+	err := schedule.Validate()
+	if err != nil {
+		return Schedule{}, microerror.Mask(err)
+	}
+
+	return schedule, nil
+}
+
+// Validate checks that the schedule can be used.
+func (s Schedule) Validate() error {
+	if len(s.Steps) == 0 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Steps must not be empty", s)
+	}
+	if s.Steps[0].After != 0 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Steps must start with a step after 0s", s)
+	}
+	if s.Jitter < 0 || s.Jitter > 1 {
+		return microerror.Maskf(errors.InvalidConfigError, "%T.Jitter must be between 0 and 1", s)
+	}
+	for _, step := range s.Steps {
+		if step.RequeueAfter <= 0 {
+			return microerror.Maskf(errors.InvalidConfigError, "%T.RequeueAfter must be positive", step)
+		}
+		if severityRank(step.Severity) < 0 {
+			return microerror.Maskf(errors.InvalidConfigError, "%T.Severity must be one of Info, Warning or Error, got %q", step, step.Severity)
+		}
+	}
+	return nil
+}
+
+// String returns the schedule in the format accepted by Parse.
+func (s Schedule) String() string {
+	var steps []string
+	for _, step := range s.Steps {
+		steps = append(steps, fmt.Sprintf("%s:%s:%s", step.After, step.RequeueAfter, step.Severity))
+	}
+	return strings.Join(steps, ",")
+}
+
+// Next returns the decision for a condition that has been waiting for the
+// specified time, and that currently has the specified severity. Severity
+// never goes down while waiting, so a condition that was already raised to
+// warning stays at a warning step, even when its last transition time has
+// been reset by the severity change.
+func (s Schedule) Next(waiting time.Duration, severity capi.ConditionSeverity) Decision {
+	index := 0
+	for i, step := range s.Steps {
+		if waiting >= step.After {
+			index = i
+		}
+	}
+	for index < len(s.Steps)-1 && severityRank(s.Steps[index].Severity) < severityRank(severity) {
+		index++
+	}
+
+	step := s.Steps[index]
+	decision := Decision{
+		RequeueAfter: step.RequeueAfter,
+		Severity:     step.Severity,
+	}
+	if s.Jitter > 0 {
+		decision.RequeueAfter = wait.Jitter(step.RequeueAfter, s.Jitter)
+	}
+	if severityRank(step.Severity) > severityRank(capi.ConditionSeverityInfo) && step.After > 0 {
+		decision.MessageSuffix = fmt.Sprintf(" for more than %s", formatDuration(step.After))
+	}
+
+	return decision
+}
+
+// ForCondition returns the decision for the specified condition. Conditions
+// that are not false have not started waiting yet.
+func (s Schedule) ForCondition(object conditions.Getter, condition capi.ConditionType) Decision {
+	if !conditions.IsFalse(object, condition) {
+		return s.Next(0, capi.ConditionSeverityInfo)
+	}
+
+	var waiting time.Duration
+	if lastTransitionTime := conditions.GetLastTransitionTime(object, condition); lastTransitionTime != nil {
+		waiting = time.Since(lastTransitionTime.Time)
+	}
+	var severity capi.ConditionSeverity
+	if s := conditions.GetSeverity(object, condition); s != nil {
+		severity = *s
+	}
+
+	return s.Next(waiting, severity)
+}
+
+// Last returns the decision for the last step of the schedule, which is used
+// when there is nothing to do but wait for a long time, e.g. for someone to
+// fix a failed deletion.
+func (s Schedule) Last() Decision {
+	return s.Next(s.Steps[len(s.Steps)-1].After, capi.ConditionSeverityInfo)
+}
+
+func severityRank(severity capi.ConditionSeverity) int {
+	switch severity {
+	case capi.ConditionSeverityNone, capi.ConditionSeverityInfo:
+		return 0
+	case capi.ConditionSeverityWarning:
+		return 1
+	case capi.ConditionSeverityError:
+		return 2
+	default:
+		return -1
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	if d%time.Minute == 0 {
+		minutes := int(d.Minutes())
+		if minutes == 1 {
+			return "1 minute"
+		}
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+	return d.String()
+}
@@ -0,0 +1,108 @@
+package requeue
+
+import (
+	"testing"
+	"time"
+
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func Test_Parse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		schedule      string
+		expectedSteps []Step
+		expectError   bool
+	}{
+		{
+			name:     "default schedule",
+			schedule: "0s:1m,5m:5m,15m:15m:Warning",
+			expectedSteps: []Step{
+				{After: 0, RequeueAfter: time.Minute, Severity: capi.ConditionSeverityInfo},
+				{After: 5 * time.Minute, RequeueAfter: 5 * time.Minute, Severity: capi.ConditionSeverityInfo},
+				{After: 15 * time.Minute, RequeueAfter: 15 * time.Minute, Severity: capi.ConditionSeverityWarning},
+			},
+		},
+		{
+			name:     "unordered steps",
+			schedule: "10m:10m:Error, 0s:30s",
+			expectedSteps: []Step{
+				{After: 0, RequeueAfter: 30 * time.Second, Severity: capi.ConditionSeverityInfo},
+				{After: 10 * time.Minute, RequeueAfter: 10 * time.Minute, Severity: capi.ConditionSeverityError},
+			},
+		},
+		{name: "missing first step", schedule: "5m:5m", expectError: true},
+		{name: "invalid duration", schedule: "0s:soon", expectError: true},
+		{name: "invalid severity", schedule: "0s:1m:Critical", expectError: true},
+		{name: "invalid format", schedule: "1m", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.schedule, 0)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got schedule %s", schedule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(schedule.Steps) != len(tc.expectedSteps) {
+				t.Fatalf("expected steps %v, got %v", tc.expectedSteps, schedule.Steps)
+			}
+			for i := range tc.expectedSteps {
+				if schedule.Steps[i] != tc.expectedSteps[i] {
+					t.Errorf("expected step %d to be %v, got %v", i, tc.expectedSteps[i], schedule.Steps[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_Schedule_Next(t *testing.T) {
+	schedule := DefaultSchedule
+	schedule.Jitter = 0
+
+	testCases := []struct {
+		name                  string
+		waiting               time.Duration
+		severity              capi.ConditionSeverity
+		expectedRequeueAfter  time.Duration
+		expectedSeverity      capi.ConditionSeverity
+		expectedMessageSuffix string
+	}{
+		{"just started waiting", 0, capi.ConditionSeverityInfo, time.Minute, capi.ConditionSeverityInfo, ""},
+		{"waiting for a few minutes", 7 * time.Minute, capi.ConditionSeverityInfo, 5 * time.Minute, capi.ConditionSeverityInfo, ""},
+		{"waiting for too long", 20 * time.Minute, capi.ConditionSeverityInfo, 15 * time.Minute, capi.ConditionSeverityWarning, " for more than 15 minutes"},
+		{"severity already raised", 0, capi.ConditionSeverityWarning, 15 * time.Minute, capi.ConditionSeverityWarning, " for more than 15 minutes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision := schedule.Next(tc.waiting, tc.severity)
+			if decision.RequeueAfter != tc.expectedRequeueAfter {
+				t.Errorf("expected requeue after %s, got %s", tc.expectedRequeueAfter, decision.RequeueAfter)
+			}
+			if decision.Severity != tc.expectedSeverity {
+				t.Errorf("expected severity %s, got %s", tc.expectedSeverity, decision.Severity)
+			}
+			if decision.MessageSuffix != tc.expectedMessageSuffix {
+				t.Errorf("expected message suffix %q, got %q", tc.expectedMessageSuffix, decision.MessageSuffix)
+			}
+		})
+	}
+}
+
+func Test_Schedule_NextWithJitter(t *testing.T) {
+	schedule := DefaultSchedule
+	schedule.Jitter = 0.5
+
+	for i := 0; i < 100; i++ {
+		decision := schedule.Next(0, capi.ConditionSeverityInfo)
+		if decision.RequeueAfter < time.Minute || decision.RequeueAfter > 90*time.Second {
+			t.Fatalf("expected requeue after between 1m and 1m30s, got %s", decision.RequeueAfter)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	stderrors "errors"
+	"reflect"
+	"strings"
+	"unicode"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const middlewareID = "AWSVPCOperatorTracing"
+
+// AppendMiddlewares adds the tracing middleware to the specified AWS SDK API
+// options, so that a span is created for every AWS API call.
+//
+// Example:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	tracing.AppendMiddlewares(&cfg.APIOptions)
+func AppendMiddlewares(apiOptions *[]func(*middleware.Stack) error) {
+	*apiOptions = append(*apiOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(middlewareID, handleInitialize), middleware.After)
+	})
+}
+
+func handleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (out middleware.InitializeOutput, metadata middleware.Metadata, err error) {
+	serviceID := awsmiddleware.GetServiceID(ctx)
+	operation := awsmiddleware.GetOperationName(ctx)
+
+	attributes := []attribute.KeyValue{
+		attribute.String("rpc.system", "aws-api"),
+		attribute.String("rpc.service", serviceID),
+		attribute.String("rpc.method", operation),
+		RegionKey.String(awsmiddleware.GetRegion(ctx)),
+	}
+	attributes = append(attributes, resourceIdAttributes(in.Parameters)...)
+
+	ctx, span := otel.Tracer(TracerName).Start(ctx, serviceID+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...))
+	defer func() {
+		span.SetAttributes(RequestIDKey.String(requestID(metadata, err)))
+		End(span, err)
+	}()
+
+	return next.HandleInitialize(ctx, in)
+}
+
+// requestID returns AWS request ID from the response metadata or from the
+// response error.
+func requestID(metadata middleware.Metadata, err error) string {
+	if id, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		return id
+	}
+
+	var responseError *awshttp.ResponseError
+	if stderrors.As(err, &responseError) {
+		return responseError.ServiceRequestID()
+	}
+
+	return ""
+}
+
+// resourceIdAttributes returns span attributes for all resource IDs that are
+// set in the AWS API call input, e.g. VpcId is set as aws.ec2.vpc_id and
+// SubnetIds are set as aws.ec2.subnet_ids.
+func resourceIdAttributes(input interface{}) []attribute.KeyValue {
+	value := reflect.ValueOf(input)
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var attributes []attribute.KeyValue
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		fieldValue := value.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(field.Name, "Id") && field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.String:
+			if !fieldValue.IsNil() {
+				attributes = append(attributes, attribute.String(attributeKey(field.Name), fieldValue.Elem().String()))
+			}
+		case strings.HasSuffix(field.Name, "Ids") && field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.String:
+			if fieldValue.Len() > 0 {
+				attributes = append(attributes, attribute.StringSlice(attributeKey(field.Name), fieldValue.Interface().([]string)))
+			}
+		}
+	}
+
+	return attributes
+}
+
+// attributeKey converts an EC2 input field name to a span attribute key, e.g.
+// RouteTableId to aws.ec2.route_table_id.
+func attributeKey(fieldName string) string {
+	var key strings.Builder
+	key.WriteString("aws.ec2.")
+	for i, r := range fieldName {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				key.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		key.WriteRune(r)
+	}
+	return key.String()
+}
@@ -0,0 +1,76 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/aws/awstest"
+	"github.com/giantswarm/aws-vpc-operator/pkg/tracing"
+)
+
+func Test_AWSCallsAreTraced(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := tracing.NewSDKTracerProvider(exporter, 1)
+	otel.SetTracerProvider(tracerProvider)
+
+	fakeEC2 := awstest.NewEC2().On("DescribeVpcs", func(interface{}) (interface{}, error) {
+		return &ec2.DescribeVpcsOutput{}, nil
+	})
+	var apiOptions []func(*middleware.Stack) error
+	tracing.AppendMiddlewares(&apiOptions)
+	ec2Client := fakeEC2.Client(apiOptions...)
+
+	ctx, reconcileSpan := tracing.Start(context.Background(), "Reconcile", tracing.Cluster("org-test", "test")...)
+	_, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		VpcIds: []string{"vpc-1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tracing.End(reconcileSpan, nil)
+
+	err = tracerProvider.ForceFlush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	awsSpan := spans[0]
+	if awsSpan.Name != "EC2.DescribeVpcs" {
+		t.Errorf("expected span name EC2.DescribeVpcs, got %s", awsSpan.Name)
+	}
+	if awsSpan.Parent.SpanID() != spans[1].SpanContext.SpanID() {
+		t.Errorf("expected AWS call span to be a child of the reconcile span")
+	}
+
+	wantedAttributes := map[attribute.Key]string{
+		tracing.RegionKey:    awstest.Region,
+		tracing.RequestIDKey: "request-DescribeVpcs",
+		"rpc.method":         "DescribeVpcs",
+		"aws.ec2.vpc_ids":    `["vpc-1"]`,
+	}
+	for key, wantedValue := range wantedAttributes {
+		found := false
+		for _, kv := range awsSpan.Attributes {
+			if kv.Key == key {
+				found = true
+				if kv.Value.Emit() != wantedValue {
+					t.Errorf("expected attribute %s to be %q, got %q", key, wantedValue, kv.Value.Emit())
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected attribute %s to be set", key)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+const (
+	// TracerName is the name of the tracer used for all spans created by
+	// aws-vpc-operator.
+	TracerName = "github.com/giantswarm/aws-vpc-operator"
+
+	serviceName = "aws-vpc-operator"
+)
+
+// Span attribute keys that are set on reconciliation and AWS API call spans.
+const (
+	ClusterNameKey      = attribute.Key("cluster.name")
+	ClusterNamespaceKey = attribute.Key("cluster.namespace")
+	RegionKey           = attribute.Key("cloud.region")
+	RoleARNKey          = attribute.Key("aws.role_arn")
+	RequestIDKey        = attribute.Key("aws.request_id")
+	VpcIdKey            = attribute.Key("aws.ec2.vpc_id")
+	SubnetIdsKey        = attribute.Key("aws.ec2.subnet_ids")
+	RouteTableIdsKey    = attribute.Key("aws.ec2.route_table_ids")
+	VpcEndpointIdKey    = attribute.Key("aws.ec2.vpc_endpoint_id")
+)
+
+// Config specifies if and where traces are exported.
+type Config struct {
+	// Enabled enables tracing. When tracing is disabled, a no-op tracer
+	// provider is used.
+	Enabled bool
+
+	// Endpoint is the OTLP/HTTP endpoint (host:port) to which the spans are
+	// exported.
+	Endpoint string
+
+	// Insecure disables TLS for the OTLP exporter.
+	Insecure bool
+
+	// SampleRatio is the ratio of reconciliations that are sampled, from 0 to 1.
+	SampleRatio float64
+}
+
+// NewTracerProvider creates a tracer provider for the specified config. The
+// returned shutdown func flushes all pending spans and must be called before
+// the process exits.
+func NewTracerProvider(ctx context.Context, config Config) (trace.TracerProvider, func(context.Context) error, error) {
+	if !config.Enabled {
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	}
+	if config.Endpoint == "" {
+		return nil, nil, microerror.Maskf(errors.InvalidConfigError, "%T.Endpoint must not be empty when tracing is enabled", config)
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, nil, microerror.Maskf(errors.InvalidConfigError, "%T.SampleRatio must be between 0 and 1", config)
+	}
+
+	exporterOptions := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(config.Endpoint),
+	}
+	if config.Insecure {
+		exporterOptions = append(exporterOptions, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOptions...)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	tracerProvider := NewSDKTracerProvider(exporter, config.SampleRatio)
+	return tracerProvider, tracerProvider.Shutdown, nil
+}
+
+// NewSDKTracerProvider creates a tracer provider that exports spans with the
+// specified exporter. It is used by NewTracerProvider with the OTLP exporter,
+// and in tests with an in-memory exporter.
+func NewSDKTracerProvider(exporter sdktrace.SpanExporter, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+}
+
+// Start starts a new span with the specified name and attributes, using the
+// globally registered tracer provider.
+func Start(ctx context.Context, spanName string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, spanName, trace.WithAttributes(attributes...))
+}
+
+// End records the specified error (if any) on the span and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Cluster returns span attributes that identify the reconciled cluster.
+func Cluster(namespace, name string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		ClusterNamespaceKey.String(namespace),
+		ClusterNameKey.String(name),
+	}
+}
+
+// SpanFromContext returns the current span from the context, so that callers
+// can add attributes to it once the IDs of reconciled resources are known.
+func SpanFromContext(ctx context.Context) trace.Span {
+	return trace.SpanFromContext(ctx)
+}
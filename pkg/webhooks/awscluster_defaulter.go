@@ -0,0 +1,137 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"net/netip"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+	"github.com/giantswarm/aws-vpc-operator/pkg/errors"
+)
+
+// maxSubnetPrefixLength is the smallest subnet size allowed by AWS.
+const maxSubnetPrefixLength = 28
+
+// +kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta2-awscluster,mutating=true,failurePolicy=ignore,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=create;update,versions=v1beta2,name=default.awscluster.aws-vpc-operator.giantswarm.io,admissionReviewVersions=v1
+
+// AWSClusterDefaulter sets network defaults from the operator configuration
+// in AWSClusters in private VPC mode, so that the network spec is explicit
+// and can be reviewed before aws-vpc-operator creates any AWS resources. The
+// VPC and subnets are defaulted only until the VPC is created, so existing
+// networks are never changed.
+type AWSClusterDefaulter struct {
+	config *config.Store
+}
+
+var _ webhook.CustomDefaulter = &AWSClusterDefaulter{}
+
+func NewAWSClusterDefaulter(config *config.Store) (*AWSClusterDefaulter, error) {
+	if config == nil {
+		return nil, microerror.Maskf(errors.InvalidConfigError, "config must not be empty")
+	}
+
+	return &AWSClusterDefaulter{
+		config: config,
+	}, nil
+}
+
+func (d *AWSClusterDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	awsCluster, ok := obj.(*capa.AWSCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an AWSCluster but got a %T", obj))
+	}
+	if !isPrivateVpcMode(awsCluster) || !awsCluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	defaults := d.config.Get().Defaults
+
+	if defaults.VpcEndpointMode != "" {
+		if _, ok := awsCluster.Annotations[annotation.VPCEndpointModeAnnotation]; !ok {
+			awsCluster.Annotations[annotation.VPCEndpointModeAnnotation] = defaults.VpcEndpointMode
+		}
+	}
+
+	// Tags are defaulted only on creation, i.e. before the finalizer is set,
+	// so that operator tags that have been removed by the user are not added
+	// back.
+	if len(awsCluster.Finalizers) == 0 && len(defaults.AdditionalTags) > 0 {
+		if awsCluster.Spec.AdditionalTags == nil {
+			awsCluster.Spec.AdditionalTags = capa.Tags{}
+		}
+		for key, value := range defaults.AdditionalTags {
+			if _, ok := awsCluster.Spec.AdditionalTags[key]; !ok {
+				awsCluster.Spec.AdditionalTags[key] = value
+			}
+		}
+	}
+
+	networkSpec := &awsCluster.Spec.NetworkSpec
+	if networkSpec.VPC.ID != "" {
+		// existing VPC, including unmanaged networks
+		return nil
+	}
+
+	if networkSpec.VPC.CidrBlock == "" {
+		networkSpec.VPC.CidrBlock = defaults.VpcCidr
+	}
+	vpcPrefix, err := parseCidr(networkSpec.VPC.CidrBlock)
+	if err != nil {
+		// invalid CIDR block is rejected by the validating webhook
+		return nil
+	}
+
+	if len(networkSpec.Subnets) == 0 && awsCluster.Spec.Region != "" {
+		subnetPrefixes, ok := splitPrefix(vpcPrefix, defaults.AvailabilityZoneCount)
+		if !ok {
+			return nil
+		}
+		// availability zones are left empty, as zone names differ between
+		// regions and accounts, and are selected from the zones of the region
+		// when the subnets are created
+		for _, subnetPrefix := range subnetPrefixes {
+			networkSpec.Subnets = append(networkSpec.Subnets, capa.SubnetSpec{
+				CidrBlock: subnetPrefix.String(),
+				IsPublic:  false,
+			})
+		}
+	}
+
+	return nil
+}
+
+// subnetPrefixLength returns the prefix length of subnets when the VPC prefix
+// is split evenly into the specified number of subnets.
+func subnetPrefixLength(vpcPrefix netip.Prefix, count int) int {
+	return vpcPrefix.Bits() + bits.Len(uint(count-1))
+}
+
+// splitPrefix splits the prefix into the specified number of equal subnets,
+// e.g. 10.0.0.0/16 into 10.0.0.0/18, 10.0.64.0/18 and 10.0.128.0/18. It
+// returns false when the subnets would be smaller than AWS allows.
+func splitPrefix(prefix netip.Prefix, count int) ([]netip.Prefix, bool) {
+	prefixLength := subnetPrefixLength(prefix, count)
+	if prefixLength > maxSubnetPrefixLength {
+		return nil, false
+	}
+
+	base := prefix.Masked().Addr().As4()
+	start := uint32(base[0])<<24 | uint32(base[1])<<16 | uint32(base[2])<<8 | uint32(base[3])
+	size := uint32(1) << (32 - prefixLength)
+
+	subnets := make([]netip.Prefix, 0, count)
+	for i := 0; i < count; i++ {
+		address := start + uint32(i)*size
+		addr := netip.AddrFrom4([4]byte{byte(address >> 24), byte(address >> 16), byte(address >> 8), byte(address)})
+		subnets = append(subnets, netip.PrefixFrom(addr, prefixLength))
+	}
+
+	return subnets, true
+}
@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+
+	"github.com/giantswarm/aws-vpc-operator/pkg/config"
+)
+
+func TestAWSClusterDefaulter_Default(t *testing.T) {
+	base := config.Default()
+	base.Defaults = config.Defaults{
+		VpcCidr:               "10.0.0.0/16",
+		AvailabilityZoneCount: 3,
+		VpcEndpointMode:       annotation.VPCEndpointModeGiantSwarmManaged,
+		AdditionalTags:        map[string]string{"team": "phoenix"},
+	}
+	operatorConfig, err := config.Load("", base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name                    string
+		mutate                  func(*capa.AWSCluster)
+		expectedVpcCidr         string
+		expectedSubnets         []capa.SubnetSpec
+		expectedVpcEndpointMode string
+		expectedTags            capa.Tags
+	}{
+		{
+			name: "new cluster without network",
+			mutate: func(c *capa.AWSCluster) {
+				c.Spec.NetworkSpec = capa.NetworkSpec{}
+				c.Spec.AdditionalTags = capa.Tags{"team": "rocket"}
+			},
+			expectedVpcCidr: "10.0.0.0/16",
+			expectedSubnets: []capa.SubnetSpec{
+				{CidrBlock: "10.0.0.0/18"},
+				{CidrBlock: "10.0.64.0/18"},
+				{CidrBlock: "10.0.128.0/18"},
+			},
+			expectedVpcEndpointMode: annotation.VPCEndpointModeGiantSwarmManaged,
+			expectedTags:            capa.Tags{"team": "rocket"},
+		},
+		{
+			name: "subnets split the specified VPC CIDR",
+			mutate: func(c *capa.AWSCluster) {
+				c.Spec.NetworkSpec = capa.NetworkSpec{VPC: capa.VPCSpec{CidrBlock: "192.168.0.0/20"}}
+				c.Annotations[annotation.VPCEndpointModeAnnotation] = annotation.VPCEndpointModeUserManaged
+			},
+			expectedVpcCidr: "192.168.0.0/20",
+			expectedSubnets: []capa.SubnetSpec{
+				{CidrBlock: "192.168.0.0/22"},
+				{CidrBlock: "192.168.4.0/22"},
+				{CidrBlock: "192.168.8.0/22"},
+			},
+			expectedVpcEndpointMode: annotation.VPCEndpointModeUserManaged,
+			expectedTags:            capa.Tags{"team": "phoenix"},
+		},
+		{
+			name: "existing VPC is not changed",
+			mutate: func(c *capa.AWSCluster) {
+				c.Finalizers = []string{"aws-vpc-operator.finalizers.giantswarm.io"}
+				c.Spec.NetworkSpec = capa.NetworkSpec{VPC: capa.VPCSpec{ID: "vpc-1"}}
+			},
+			expectedVpcEndpointMode: annotation.VPCEndpointModeGiantSwarmManaged,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := NewAWSClusterDefaulter(config.NewStore(operatorConfig))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			awsCluster := testAWSCluster(tc.mutate)
+
+			err = d.Default(context.Background(), awsCluster)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if awsCluster.Spec.NetworkSpec.VPC.CidrBlock != tc.expectedVpcCidr {
+				t.Errorf("expected VPC CIDR %q, got %q", tc.expectedVpcCidr, awsCluster.Spec.NetworkSpec.VPC.CidrBlock)
+			}
+			if !reflect.DeepEqual([]capa.SubnetSpec(awsCluster.Spec.NetworkSpec.Subnets), tc.expectedSubnets) {
+				t.Errorf("expected subnets %v, got %v", tc.expectedSubnets, awsCluster.Spec.NetworkSpec.Subnets)
+			}
+			if mode := awsCluster.Annotations[annotation.VPCEndpointModeAnnotation]; mode != tc.expectedVpcEndpointMode {
+				t.Errorf("expected VPC endpoint mode %q, got %q", tc.expectedVpcEndpointMode, mode)
+			}
+			if !reflect.DeepEqual(awsCluster.Spec.AdditionalTags, tc.expectedTags) {
+				t.Errorf("expected tags %v, got %v", tc.expectedTags, awsCluster.Spec.AdditionalTags)
+			}
+
+			// defaulted spec is valid
+			_, err = (&AWSClusterValidator{}).ValidateCreate(context.Background(), awsCluster)
+			if err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// availabilityZoneSuffix matches the suffix of zone names after the region
+// name, e.g. "a" in the availability zone eu-west-1a, "-lax-1a" in the Local
+// Zone us-west-2-lax-1a and "-wl1-bos-wlz-1" in the Wavelength Zone
+// us-east-1-wl1-bos-wlz-1.
+var availabilityZoneSuffix = regexp.MustCompile(`^([a-z]|-[a-z0-9-]+)$`)
+
+// +kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta2-awscluster,mutating=false,failurePolicy=ignore,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=create;update,versions=v1beta2,name=validation.awscluster.aws-vpc-operator.giantswarm.io,admissionReviewVersions=v1
+
+// AWSClusterValidator validates network specs of AWSClusters in private VPC
+// mode, so that invalid specs are rejected before any AWS API call. AWSClusters
+// in other VPC modes are not validated, they are validated by CAPA.
+type AWSClusterValidator struct{}
+
+var _ webhook.CustomValidator = &AWSClusterValidator{}
+
+func (v *AWSClusterValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	awsCluster, ok := obj.(*capa.AWSCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AWSCluster but got a %T", obj))
+	}
+	if !isPrivateVpcMode(awsCluster) {
+		return nil, nil
+	}
+
+	return nil, toInvalidError(awsCluster, validateNetwork(awsCluster))
+}
+
+func (v *AWSClusterValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldAWSCluster, ok := oldObj.(*capa.AWSCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AWSCluster but got a %T", oldObj))
+	}
+	newAWSCluster, ok := newObj.(*capa.AWSCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AWSCluster but got a %T", newObj))
+	}
+
+	var allErrs field.ErrorList
+	if isPrivateVpcMode(oldAWSCluster) && !isPrivateVpcMode(newAWSCluster) && newAWSCluster.DeletionTimestamp.IsZero() {
+		// the network of a live cluster would no longer be reconciled, and
+		// it would not be deleted together with the cluster
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("metadata", "annotations").Key(annotation.AWSVPCMode),
+			fmt.Sprintf("must not be changed from %q on a cluster that is not being deleted", annotation.AWSVPCModePrivate)))
+	}
+	if !isPrivateVpcMode(newAWSCluster) {
+		return nil, toInvalidError(newAWSCluster, allErrs)
+	}
+
+	allErrs = append(allErrs, validateNetwork(newAWSCluster)...)
+
+	oldVpc := oldAWSCluster.Spec.NetworkSpec.VPC
+	newVpc := newAWSCluster.Spec.NetworkSpec.VPC
+	if oldVpc.ID != "" && oldVpc.CidrBlock != "" && newVpc.CidrBlock != oldVpc.CidrBlock {
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec", "network", "vpc", "cidrBlock"),
+			fmt.Sprintf("must not be changed from %s after VPC %s has been created", oldVpc.CidrBlock, oldVpc.ID)))
+	}
+
+	return nil, toInvalidError(newAWSCluster, allErrs)
+}
+
+func (v *AWSClusterValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func isPrivateVpcMode(awsCluster *capa.AWSCluster) bool {
+	return awsCluster.Annotations[annotation.AWSVPCMode] == annotation.AWSVPCModePrivate
+}
+
+// validateNetwork validates identity, VPC and subnets in the AWSCluster spec.
+func validateNetwork(awsCluster *capa.AWSCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if awsCluster.Spec.IdentityRef == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("identityRef"), "identity is required to assume the role in the cluster AWS account"))
+	}
+
+	vpcCidrPath := specPath.Child("network", "vpc", "cidrBlock")
+	var vpcCidr netip.Prefix
+	if cidrBlock := awsCluster.Spec.NetworkSpec.VPC.CidrBlock; cidrBlock != "" {
+		var err error
+		vpcCidr, err = parseCidr(cidrBlock)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(vpcCidrPath, cidrBlock, err.Error()))
+		}
+	}
+
+	subnetsPath := specPath.Child("network", "subnets")
+	subnetCidrs := make([]netip.Prefix, len(awsCluster.Spec.NetworkSpec.Subnets))
+	for i, subnet := range awsCluster.Spec.NetworkSpec.Subnets {
+		subnetPath := subnetsPath.Index(i)
+
+		// new subnets without an availability zone get one of the zones of
+		// the region when they are created
+		if subnet.AvailabilityZone != "" && !isAvailabilityZoneInRegion(subnet.AvailabilityZone, awsCluster.Spec.Region) {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("availabilityZone"), subnet.AvailabilityZone,
+				fmt.Sprintf("must be an availability zone in region %s", awsCluster.Spec.Region)))
+		}
+
+		if subnet.CidrBlock == "" {
+			continue
+		}
+		cidr, err := parseCidr(subnet.CidrBlock)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("cidrBlock"), subnet.CidrBlock, err.Error()))
+			continue
+		}
+		subnetCidrs[i] = cidr
+
+		if vpcCidr.IsValid() && !containsPrefix(vpcCidr, cidr) {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("cidrBlock"), subnet.CidrBlock,
+				fmt.Sprintf("must be within VPC CIDR %s", vpcCidr)))
+		}
+		for j := 0; j < i; j++ {
+			if subnetCidrs[j].IsValid() && subnetCidrs[j].Overlaps(cidr) {
+				allErrs = append(allErrs, field.Invalid(subnetPath.Child("cidrBlock"), subnet.CidrBlock,
+					fmt.Sprintf("must not overlap with CIDR %s of %s", subnetCidrs[j], subnetsPath.Index(j))))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// parseCidr parses an IPv4 CIDR block and checks that it has no host bits
+// set, e.g. 10.0.0.1/16 is rejected, like it is rejected by EC2.
+func parseCidr(cidrBlock string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(cidrBlock)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("must be a valid CIDR block")
+	}
+	if !prefix.Addr().Is4() {
+		return netip.Prefix{}, fmt.Errorf("must be an IPv4 CIDR block")
+	}
+	if prefix.Masked() != prefix {
+		return netip.Prefix{}, fmt.Errorf("must not have host bits set, e.g. %s", prefix.Masked())
+	}
+	return prefix, nil
+}
+
+// containsPrefix checks if the inner prefix is within the outer prefix.
+func containsPrefix(outer, inner netip.Prefix) bool {
+	return outer.Bits() <= inner.Bits() && outer.Contains(inner.Addr())
+}
+
+// isAvailabilityZoneInRegion checks if the availability zone name is a zone
+// name of the region, e.g. eu-west-1a is in eu-west-1. It does not check that
+// the zone exists, EC2 rejects subnets in unknown zones when they are
+// created. When the region is not set, the zone name is not checked.
+func isAvailabilityZoneInRegion(availabilityZone, region string) bool {
+	if region == "" {
+		return true
+	}
+	suffix, found := strings.CutPrefix(availabilityZone, region)
+	return found && availabilityZoneSuffix.MatchString(suffix)
+}
+
+func toInvalidError(awsCluster *capa.AWSCluster, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(capa.GroupVersion.WithKind("AWSCluster").GroupKind(), awsCluster.Name, allErrs)
+}
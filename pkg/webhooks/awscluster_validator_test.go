@@ -0,0 +1,175 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+func testAWSCluster(mutate func(*capa.AWSCluster)) *capa.AWSCluster {
+	awsCluster := &capa.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "org-test",
+			Name:        "test",
+			Annotations: map[string]string{annotation.AWSVPCMode: annotation.AWSVPCModePrivate},
+		},
+		Spec: capa.AWSClusterSpec{
+			Region:      "eu-west-1",
+			IdentityRef: &capa.AWSIdentityReference{Kind: capa.ClusterRoleIdentityKind, Name: "default"},
+			NetworkSpec: capa.NetworkSpec{
+				VPC: capa.VPCSpec{CidrBlock: "10.0.0.0/16"},
+				Subnets: capa.Subnets{
+					{CidrBlock: "10.0.0.0/18", AvailabilityZone: "eu-west-1a"},
+					{CidrBlock: "10.0.64.0/18", AvailabilityZone: "eu-west-1b"},
+				},
+			},
+		},
+	}
+	if mutate != nil {
+		mutate(awsCluster)
+	}
+	return awsCluster
+}
+
+func TestAWSClusterValidator_ValidateCreate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mutate        func(*capa.AWSCluster)
+		expectedError bool
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "not private VPC mode",
+			mutate: func(c *capa.AWSCluster) {
+				c.Annotations = nil
+				c.Spec.IdentityRef = nil
+			},
+		},
+		{
+			name:          "missing identity",
+			mutate:        func(c *capa.AWSCluster) { c.Spec.IdentityRef = nil },
+			expectedError: true,
+		},
+		{
+			name:          "subnet outside VPC CIDR",
+			mutate:        func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].CidrBlock = "10.1.0.0/18" },
+			expectedError: true,
+		},
+		{
+			name:          "subnet larger than VPC CIDR",
+			mutate:        func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].CidrBlock = "10.0.0.0/8" },
+			expectedError: true,
+		},
+		{
+			name:          "overlapping subnets",
+			mutate:        func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].CidrBlock = "10.0.32.0/20" },
+			expectedError: true,
+		},
+		{
+			name:          "subnet CIDR with host bits",
+			mutate:        func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].CidrBlock = "10.0.64.1/18" },
+			expectedError: true,
+		},
+		{
+			name:          "availability zone in another region",
+			mutate:        func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].AvailabilityZone = "us-east-1b" },
+			expectedError: true,
+		},
+		{
+			name:          "availability zone in region with the same prefix",
+			mutate:        func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].AvailabilityZone = "eu-west-10a" },
+			expectedError: true,
+		},
+		{
+			name:   "local zone",
+			mutate: func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].AvailabilityZone = "eu-west-1-ham-1a" },
+		},
+		{
+			name:   "wavelength zone",
+			mutate: func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].AvailabilityZone = "eu-west-1-wl1-man-wlz-1" },
+		},
+		{
+			name:   "missing availability zone of new subnet",
+			mutate: func(c *capa.AWSCluster) { c.Spec.NetworkSpec.Subnets[1].AvailabilityZone = "" },
+		},
+		{
+			name: "missing availability zone of existing subnet",
+			mutate: func(c *capa.AWSCluster) {
+				c.Spec.NetworkSpec.Subnets[1].ID = "subnet-1"
+				c.Spec.NetworkSpec.Subnets[1].AvailabilityZone = ""
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &AWSClusterValidator{}
+			_, err := v.ValidateCreate(context.Background(), testAWSCluster(tc.mutate))
+			if tc.expectedError && err == nil {
+				t.Fatal("expected error, got nil")
+			} else if !tc.expectedError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAWSClusterValidator_ValidateUpdate(t *testing.T) {
+	withVpc := func(c *capa.AWSCluster) { c.Spec.NetworkSpec.VPC.ID = "vpc-1" }
+
+	testCases := []struct {
+		name          string
+		old           func(*capa.AWSCluster)
+		new           func(*capa.AWSCluster)
+		expectedError bool
+	}{
+		{
+			name: "unchanged",
+			old:  withVpc,
+			new:  withVpc,
+		},
+		{
+			name: "VPC CIDR changed before VPC is created",
+			new:  func(c *capa.AWSCluster) { c.Spec.NetworkSpec.VPC.CidrBlock = "10.0.0.0/15" },
+		},
+		{
+			name: "VPC CIDR changed on existing VPC",
+			old:  withVpc,
+			new: func(c *capa.AWSCluster) {
+				withVpc(c)
+				c.Spec.NetworkSpec.VPC.CidrBlock = "10.0.0.0/15"
+			},
+			expectedError: true,
+		},
+		{
+			name:          "VPC mode annotation removed",
+			new:           func(c *capa.AWSCluster) { delete(c.Annotations, annotation.AWSVPCMode) },
+			expectedError: true,
+		},
+		{
+			name: "VPC mode annotation removed on deleted cluster",
+			new: func(c *capa.AWSCluster) {
+				delete(c.Annotations, annotation.AWSVPCMode)
+				now := metav1.Now()
+				c.DeletionTimestamp = &now
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &AWSClusterValidator{}
+			_, err := v.ValidateUpdate(context.Background(), testAWSCluster(tc.old), testAWSCluster(tc.new))
+			if tc.expectedError && err == nil {
+				t.Fatal("expected error, got nil")
+			} else if !tc.expectedError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
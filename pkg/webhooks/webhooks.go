@@ -0,0 +1,23 @@
+// Package webhooks implements admission webhooks for AWSClusters in private
+// VPC mode.
+package webhooks
+
+import (
+	"github.com/giantswarm/microerror"
+	capa "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// SetupAWSClusterWebhooksWithManager registers the AWSCluster defaulting and
+// validating webhooks with the manager webhook server.
+func SetupAWSClusterWebhooksWithManager(mgr ctrl.Manager, defaulter *AWSClusterDefaulter, validator *AWSClusterValidator) error {
+	err := ctrl.NewWebhookManagedBy(mgr).
+		For(&capa.AWSCluster{}).
+		WithDefaulter(defaulter).
+		WithValidator(validator).
+		Complete()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	return nil
+}